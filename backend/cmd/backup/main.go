@@ -0,0 +1,138 @@
+// Command backup writes the whole glossary to a portable archive and restores archives, for
+// moving a glossary between installations or keeping copies outside the database.
+//
+// Usage:
+//
+//	backup export -out FILE
+//	backup restore [-dry-run] [-merge] FILE
+//
+// An archive is a zip with a JSON Lines file per entity (terms, contexts, examples,
+// relationships, aliases, versions, proposals, flags, clusters, onboarding paths and branding)
+// and a manifest with the schema version and each file's record count and SHA-256 checksum.
+// Restore verifies the archive before writing anything and gives every record a new ID.
+// It refuses a database that already has terms unless -merge is given; -dry-run runs the
+// restore and rolls it back.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
+	"clarityconnect/pkg/database"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n  %s export -out FILE\n  %s restore [-dry-run] [-merge] FILE\n", os.Args[0], os.Args[0])
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "export":
+		export(os.Args[2:])
+	case "restore":
+		restore(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func export(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	out := flags.String("out", "", "archive file to write")
+	flags.Parse(args)
+	if *out == "" || flags.NArg() > 0 {
+		usage()
+	}
+
+	if err := database.InitDB(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.CloseDB()
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", *out, err)
+	}
+	manifest, err := service.NewBackupService().Backup(context.Background(), file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		log.Fatalf("Backup failed: %v", err)
+	}
+
+	fmt.Printf("Wrote %s (schema version %d)\n", *out, manifest.SchemaVersion)
+	for _, entity := range manifest.Entities {
+		fmt.Printf("  %-17s %d\n", entity.Name, entity.Count)
+	}
+}
+
+func restore(args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "run the restore, then roll it back")
+	merge := flags.Bool("merge", false, "restore into a database that already has terms")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	path := flags.Arg(0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+	archive, err := service.ReadBackup(file, info.Size())
+	if err != nil {
+		log.Fatalf("Invalid archive: %v", err)
+	}
+
+	if err := database.InitDB(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.CloseDB()
+
+	result, err := service.NewBackupService().Restore(context.Background(), archive, service.BackupRestoreOptions{
+		DryRun: *dryRun,
+		Merge:  *merge,
+	})
+	if err != nil {
+		log.Fatalf("Restore failed: %v", err)
+	}
+	printResult(result)
+}
+
+func printResult(result *models.BackupRestoreResult) {
+	if result.DryRun {
+		fmt.Printf("Dry run of schema version %d archive, nothing was written\n", result.SchemaVersion)
+	} else {
+		fmt.Printf("Restored schema version %d archive\n", result.SchemaVersion)
+	}
+	names := make([]string, 0, len(result.Restored))
+	for name := range result.Restored {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		line := fmt.Sprintf("  %-17s %d", name, result.Restored[name])
+		if skipped := result.Skipped[name]; skipped > 0 {
+			line += fmt.Sprintf(" (%d skipped)", skipped)
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,132 @@
+// Command import loads terms, contexts, examples, relationships and aliases into the glossary from an
+// XLSX workbook or CSV files, with the same validation and column layout as POST /api/v1/import.
+//
+// Usage:
+//
+//	import [-dry-run] [-user UUID] glossary.xlsx
+//	import [-dry-run] [-user UUID] -terms terms.csv [-contexts contexts.csv] [-examples examples.csv] [-relationships relationships.csv] [-aliases aliases.csv]
+//
+// Nothing is imported when any row has an error. Running servers pick up the imported terms on
+// their next index refresh.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
+	"clarityconnect/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "validate and report what would change without importing")
+	user := flag.String("user", middleware.DefaultUserID.String(), "ID of the user recorded as creator")
+	csvFiles := map[string]*string{}
+	for _, sheet := range service.ImportSheets {
+		csvFiles[sheet] = flag.String(sheet, "", fmt.Sprintf("CSV file with the %s sheet", sheet))
+	}
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [workbook.xlsx]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	userID, err := uuid.Parse(*user)
+	if err != nil {
+		log.Fatalf("Invalid -user: %v", err)
+	}
+
+	tables, err := readTables(flag.Args(), csvFiles)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(tables) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := database.InitDB(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.CloseDB()
+
+	result, err := service.NewGlossaryImportService().Import(context.Background(), tables, *dryRun, &userID)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if len(result.Errors) > 0 {
+		for _, rowErr := range result.Errors {
+			fmt.Fprintln(os.Stderr, formatRowError(rowErr))
+		}
+		fmt.Fprintf(os.Stderr, "%d errors, nothing was imported\n", len(result.Errors))
+		database.CloseDB()
+		os.Exit(1)
+	}
+
+	if result.DryRun {
+		fmt.Println("Dry run, nothing was imported:")
+	}
+	fmt.Printf("Terms:         %d created, %d updated\n", result.TermsCreated, result.TermsUpdated)
+	fmt.Printf("Contexts:      %d created, %d updated\n", result.ContextsCreated, result.ContextsUpdated)
+	fmt.Printf("Examples:      %d created, %d already present\n", result.ExamplesCreated, result.ExamplesSkipped)
+	fmt.Printf("Relationships: %d created, %d already present\n", result.RelationshipsCreated, result.RelationshipsSkipped)
+	fmt.Printf("Aliases:       %d created, %d already present\n", result.AliasesCreated, result.AliasesSkipped)
+}
+
+// readTables reads a workbook given as argument and the CSV files given as flags
+func readTables(args []string, csvFiles map[string]*string) ([]*service.ImportTable, error) {
+	tables := []*service.ImportTable{}
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("only one workbook can be imported at once")
+	}
+	if len(args) == 1 {
+		if strings.ToLower(filepath.Ext(args[0])) != ".xlsx" {
+			return nil, fmt.Errorf("%s is not an .xlsx workbook; pass CSV files with -terms, -contexts, -examples, -relationships and -aliases", args[0])
+		}
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if tables, err = service.ReadImportXLSX(file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sheet := range service.ImportSheets {
+		path := *csvFiles[sheet]
+		if path == "" {
+			continue
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		table, err := service.ReadImportCSV(sheet, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func formatRowError(rowErr models.ImportRowError) string {
+	if rowErr.Column != "" {
+		return fmt.Sprintf("%s row %d, %s: %s", rowErr.Sheet, rowErr.Row, rowErr.Column, rowErr.Message)
+	}
+	return fmt.Sprintf("%s row %d: %s", rowErr.Sheet, rowErr.Row, rowErr.Message)
+}
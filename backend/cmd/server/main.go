@@ -1,15 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"clarityconnect/internal/handlers"
 	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/service"
 	"clarityconnect/pkg/database"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds how long in-flight requests and buffered usage events get to finish
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// Initialize database
 	if err := database.InitDB(); err != nil {
@@ -28,24 +39,72 @@ func main() {
 	// Apply CORS middleware
 	r.Use(middleware.SetupCORS())
 
-	// Apply usage logging middleware
-	r.Use(middleware.UsageLoggerMiddleware())
-
-	// Apply user context middleware (extracts department from headers)
+	// Apply user context middleware (extracts user and department from headers)
 	r.Use(middleware.UserContextMiddleware())
 
+	// Apply usage logging middleware (buffered, written in batches in the background)
+	usageRecorder := service.NewUsageRecorder()
+	r.Use(middleware.UsageLoggerMiddleware(usageRecorder))
+
+	// Roll up, pseudonymise and purge usage logs nightly
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	usageRetention := service.NewUsageRetentionService()
+	usageRetention.StartNightly(backgroundCtx)
+
+	// In-memory typeahead index, rebuilt periodically and when terms change
+	suggestIndex := service.NewSuggestIndex()
+	suggestIndex.Start(backgroundCtx)
+
+	// Aho-Corasick matcher of term names, aliases and code names for document annotation
+	termAnnotator := service.NewTermAnnotator()
+	termAnnotator.Start(backgroundCtx)
+
+	// TF-IDF index of definitions for related-term recommendations
+	termRecommender := service.NewTermRecommender()
+	termRecommender.Start(backgroundCtx)
+
+	// Notify users when terms enter or leave the results of their saved searches
+	savedSearchAlerts := service.NewSavedSearchAlertService()
+	savedSearchAlerts.Start(backgroundCtx)
+
 	// Setup routes
-	setupRoutes(r)
+	setupRoutes(r, usageRecorder, usageRetention, suggestIndex, termAnnotator, termRecommender, savedSearchAlerts)
 
 	// Start server
 	port := ":3001"
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{Addr: port, Handler: r}
+
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Printf("Shutting down server...")
+	stopBackground()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Warning: Server shutdown did not complete: %v", err)
+	}
+
+	// Drain buffered usage events before the database pool is closed
+	if err := usageRecorder.Close(ctx); err != nil {
+		log.Printf("Warning: Failed to drain usage events: %v", err)
 	}
+	stats := usageRecorder.Stats()
+	log.Printf("Usage pipeline: %d written, %d dropped, %d failed", stats.Written, stats.Dropped, stats.Failed)
 }
 
-func setupRoutes(r *gin.Engine) {
+func setupRoutes(r *gin.Engine, usageRecorder *service.UsageRecorder, usageRetention *service.UsageRetentionService, suggestIndex *service.SuggestIndex, termAnnotator *service.TermAnnotator, termRecommender *service.TermRecommender, savedSearchAlerts *service.SavedSearchAlertService) {
 	api := r.Group("/api/v1")
 	{
 		// Health check
@@ -54,15 +113,22 @@ func setupRoutes(r *gin.Engine) {
 		})
 
 		// Initialize handlers
-		termHandler := handlers.NewTermHandler()
-		searchHandler := handlers.NewSearchHandler()
+		termHandler := handlers.NewTermHandler(suggestIndex, termAnnotator, termRecommender)
+		searchHandler := handlers.NewSearchHandler(usageRecorder, suggestIndex)
 		governanceHandler := handlers.NewGovernanceHandler()
 		brandingHandler := handlers.NewBrandingHandler()
 		gapHandler := handlers.NewGapHandler()
-		usageHandler := handlers.NewUsageHandler()
+		usageHandler := handlers.NewUsageHandler(usageRecorder, usageRetention)
 		onboardingHandler := handlers.NewOnboardingHandler()
 		complianceHandler := handlers.NewComplianceHandler()
-		versionHandler := handlers.NewVersionHandler()
+		versionHandler := handlers.NewVersionHandler(suggestIndex, termAnnotator, termRecommender)
+		savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchAlerts)
+		notificationHandler := handlers.NewNotificationHandler()
+		annotationHandler := handlers.NewAnnotationHandler(termAnnotator)
+		discoveryHandler := handlers.NewDiscoveryHandler()
+		recommendationHandler := handlers.NewRecommendationHandler(termRecommender, suggestIndex)
+		importHandler := handlers.NewImportHandler(suggestIndex, termAnnotator, termRecommender)
+		exportHandler := handlers.NewExportHandler()
 
 		// Terms routes
 		terms := api.Group("/terms")
@@ -75,12 +141,56 @@ func setupRoutes(r *gin.Engine) {
 			terms.POST("/:id/contexts", termHandler.CreateContext)
 			terms.POST("/:id/examples", termHandler.CreateExample)
 			terms.POST("/:id/relationships", termHandler.CreateRelationship)
+			terms.POST("/:id/aliases", termHandler.CreateAlias)
+			terms.DELETE("/:id/aliases/:aliasId", termHandler.DeleteAlias)
 			terms.GET("/:id/versions", versionHandler.ListVersions)
 			terms.POST("/:id/rollback", versionHandler.RollbackVersion)
+			terms.GET("/:id/recommendations", recommendationHandler.ListRecommendations)
+			terms.POST("/:id/recommendations/accept", recommendationHandler.AcceptRecommendation)
 		}
 
 		// Search routes
 		api.GET("/search", searchHandler.SearchTerms)
+		api.GET("/search/suggest", searchHandler.SuggestTerms)
+		api.POST("/search/:id/click", searchHandler.RecordSearchClick)
+
+		// Annotation routes
+		api.POST("/annotate", annotationHandler.AnnotateText)
+		api.POST("/annotate/html", annotationHandler.AnnotateHTML)
+
+		// Candidate term discovery routes
+		api.POST("/discovery/candidates", discoveryHandler.DiscoverCandidates)
+		api.POST("/discovery/proposals", discoveryHandler.ProposeCandidate)
+
+		// Bulk import routes
+		api.POST("/import", importHandler.ImportGlossary)
+		api.POST("/import/skos", importHandler.ImportSKOS)
+		api.POST("/import/dbt", importHandler.ImportDBT)
+		api.POST("/import/openmetadata", importHandler.ImportOpenMetadata)
+		api.POST("/import/datahub", importHandler.ImportDataHub)
+
+		// Export routes
+		api.GET("/export/skos", exportHandler.ExportSKOS)
+		api.GET("/export/dbt", exportHandler.ExportDBT)
+		api.GET("/export/openmetadata", exportHandler.ExportOpenMetadata)
+		api.GET("/export/datahub", exportHandler.ExportDataHub)
+		api.GET("/export/xlsx", exportHandler.ExportXLSX)
+
+		// Saved search routes
+		savedSearches := api.Group("/saved-searches")
+		{
+			savedSearches.GET("", savedSearchHandler.ListSavedSearches)
+			savedSearches.POST("", savedSearchHandler.CreateSavedSearch)
+			savedSearches.POST("/check", savedSearchHandler.CheckSavedSearches)
+			savedSearches.GET("/:id", savedSearchHandler.GetSavedSearch)
+			savedSearches.PUT("/:id", savedSearchHandler.UpdateSavedSearch)
+			savedSearches.DELETE("/:id", savedSearchHandler.DeleteSavedSearch)
+			savedSearches.GET("/:id/run", savedSearchHandler.RunSavedSearch)
+		}
+
+		// Notification routes
+		api.GET("/notifications", notificationHandler.ListNotifications)
+		api.PATCH("/notifications/:id/read", notificationHandler.MarkNotificationRead)
 
 		// Governance routes
 		proposals := api.Group("/proposals")
@@ -119,6 +229,7 @@ func setupRoutes(r *gin.Engine) {
 			clusters.GET("", gapHandler.ListClusters)
 			clusters.GET("/:name/terms", gapHandler.GetClusterTerms)
 			clusters.GET("/:name/comparison", gapHandler.GetClusterComparison)
+			clusters.GET("/:name/matrix", gapHandler.GetClusterMatrix)
 		}
 
 		// Term cluster comparison
@@ -129,11 +240,22 @@ func setupRoutes(r *gin.Engine) {
 		{
 			analytics.GET("/gaps", gapHandler.GetGapAnalytics)
 			analytics.GET("/cluster-coverage", gapHandler.GetClusterCoverage)
+			analytics.GET("/usage/top-terms", usageHandler.GetTopTerms)
+			analytics.GET("/usage/breakdown", usageHandler.GetUsageBreakdown)
+			analytics.GET("/usage/heatmap", usageHandler.GetDepartmentHeatmap)
+			analytics.GET("/usage/timeseries", usageHandler.GetUsageTimeSeries)
+			analytics.GET("/usage/trending", usageHandler.GetTrendingTerms)
+			analytics.GET("/usage/unused", usageHandler.GetUnusedTerms)
+			analytics.GET("/references", usageHandler.GetTermReferenceReport)
+			analytics.GET("/search/zero-results", usageHandler.GetZeroResultQueries)
 		}
 
 		// Usage analytics routes
 		api.GET("/terms/:id/views", usageHandler.GetTermViewCount)
 		api.GET("/usage/recently-viewed", usageHandler.GetRecentlyViewedTerms)
+		api.GET("/usage/pipeline", usageHandler.GetPipelineStats)
+		api.POST("/usage/retention/run", usageHandler.RunRetention)
+		api.POST("/usage/references", usageHandler.CreateTermReferences)
 
 		// Onboarding routes
 		onboarding := api.Group("/onboarding")
@@ -0,0 +1,67 @@
+// Command sitegen renders the glossary to a directory of static pages: an A–Z index, a page per
+// category and per cluster, and a page per term with its contexts, examples, relationships and
+// compliance frameworks. HTML pages are styled with the organization's branding colors.
+//
+// Usage:
+//
+//	sitegen [-format html|markdown] [-public] [-department NAME] [-org ID] [-title TITLE] -out DIR
+//
+// -public leaves out department_restricted terms, for audiences outside the organization;
+// -department builds the glossary as that department sees it. Without either, every term is
+// included and restricted pages name the departments they are visible to.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"clarityconnect/internal/service"
+	"clarityconnect/pkg/database"
+)
+
+func main() {
+	out := flag.String("out", "", "directory to write the site to (created if missing)")
+	format := flag.String("format", service.StaticSiteFormatHTML, "page format: html or markdown")
+	public := flag.Bool("public", false, "leave out department_restricted terms")
+	department := flag.String("department", "", "include only the terms visible to this department")
+	org := flag.String("org", "default", "organization whose branding colors style the pages")
+	title := flag.String("title", "", "site title (default \"Business Glossary\")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] -out DIR\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *out == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	pageFormat, ok := service.ParseStaticSiteFormat(*format)
+	if !ok {
+		log.Fatalf("Invalid -format %q: use html or markdown", *format)
+	}
+
+	options := service.StaticSiteOptions{
+		Format:         pageFormat,
+		Title:          *title,
+		OrganizationID: *org,
+		Public:         *public,
+	}
+	if *department != "" {
+		options.Department = department
+	}
+
+	if err := database.InitDB(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.CloseDB()
+
+	files, err := service.NewStaticSiteService().Build(context.Background(), *out, options)
+	if err != nil {
+		log.Fatalf("Site generation failed: %v", err)
+	}
+	fmt.Printf("Wrote %d files to %s\n", files, *out)
+}
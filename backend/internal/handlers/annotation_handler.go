@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// annotateMaxBytes bounds the size of a document sent for annotation
+const annotateMaxBytes = 2 << 20
+
+type AnnotationHandler struct {
+	annotator *service.TermAnnotator
+}
+
+func NewAnnotationHandler(annotator *service.TermAnnotator) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotator: annotator,
+	}
+}
+
+// AnnotateText handles POST /api/v1/annotate. The body is either JSON ({"text": ..., "cluster": ...})
+// or the raw document as text/plain with an optional ?cluster= parameter.
+func (h *AnnotationHandler) AnnotateText(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, annotateMaxBytes)
+
+	var req models.AnnotateRequest
+	if strings.HasPrefix(c.ContentType(), "text/") {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondBodyError(c, err, annotateMaxBytes)
+			return
+		}
+		req.Text = string(body)
+		if cluster := c.Query("cluster"); cluster != "" {
+			req.Cluster = &cluster
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		respondBodyError(c, err, annotateMaxBytes)
+		return
+	}
+
+	if strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
+		return
+	}
+
+	result, err := h.annotator.Annotate(c.Request.Context(), req.Text, req.Cluster, middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mentions": result.Mentions,
+		"terms":    result.Terms,
+		"total":    len(result.Mentions),
+		"cluster":  req.Cluster,
+	})
+}
+
+// AnnotateHTML handles POST /api/v1/annotate/html. The body is either JSON ({"html": ...,
+// "cluster": ..., "first_only": ...}) or the document as text/html with optional ?cluster= and
+// ?first_only=true parameters. The response contains sanitized HTML with terms wrapped in
+// <span class="glossary-term" data-term-id=... data-definition=...> elements.
+func (h *AnnotationHandler) AnnotateHTML(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, annotateMaxBytes)
+
+	var req models.AnnotateHTMLRequest
+	if strings.HasPrefix(c.ContentType(), "text/") {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondBodyError(c, err, annotateMaxBytes)
+			return
+		}
+		req.HTML = string(body)
+		if cluster := c.Query("cluster"); cluster != "" {
+			req.Cluster = &cluster
+		}
+		req.FirstOnly = c.Query("first_only") == "true"
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		respondBodyError(c, err, annotateMaxBytes)
+		return
+	}
+
+	if strings.TrimSpace(req.HTML) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "html is required"})
+		return
+	}
+
+	result, err := h.annotator.AnnotateHTML(c.Request.Context(), req.HTML, req.Cluster, middleware.GetUserDepartment(c), req.FirstOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"html":     result.HTML,
+		"mentions": result.Mentions,
+		"terms":    result.Terms,
+		"cluster":  req.Cluster,
+	})
+}
+
+// respondBodyError reports an unreadable request body, distinguishing bodies over the size limit
+func respondBodyError(c *gin.Context, err error, limit int64) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds the %d MiB limit", limit>>20)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// discoveryMaxUploadBytes bounds the total size of the documents of one discovery request
+	discoveryMaxUploadBytes = 10 << 20
+
+	// discoveryMaxFiles bounds the number of documents of one discovery request
+	discoveryMaxFiles = 50
+)
+
+type DiscoveryHandler struct {
+	discovery *service.TermDiscoveryService
+}
+
+func NewDiscoveryHandler() *DiscoveryHandler {
+	return &DiscoveryHandler{
+		discovery: service.NewTermDiscoveryService(),
+	}
+}
+
+// DiscoverCandidates handles POST /api/v1/discovery/candidates (multipart/form-data)
+//
+// Form fields: files (repeated; .txt, .md or .csv), columns (optional, repeated; CSV columns to
+// read, all by default - column names are always included), min_frequency (default 2) and
+// limit (default 50, at most 500).
+func (h *DiscoveryHandler) DiscoverCandidates(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, discoveryMaxUploadBytes)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		respondBodyError(c, err, discoveryMaxUploadBytes)
+		return
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one file is required in the 'files' field"})
+		return
+	}
+	if len(files) > discoveryMaxFiles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d files can be analysed at once", discoveryMaxFiles)})
+		return
+	}
+
+	minFrequency, err := strconv.Atoi(c.DefaultPostForm("min_frequency", "2"))
+	if err != nil || minFrequency < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_frequency must be a positive integer"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultPostForm("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+	columns := map[string]bool{}
+	for _, column := range c.PostFormArray("columns") {
+		for _, name := range strings.Split(column, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				columns[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	documents := make([]models.DiscoveryDocument, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read %s: %v", header.Filename, err)})
+			return
+		}
+		content, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read %s: %v", header.Filename, err)})
+			return
+		}
+
+		document := models.DiscoveryDocument{Name: header.Filename}
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".txt", ".text":
+			document.Segments = []string{string(content)}
+		case ".md", ".markdown":
+			document.Segments = []string{service.StripMarkdown(string(content))}
+		case ".csv":
+			document.Segments, err = csvSegments(content, columns)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to parse %s: %v", header.Filename, err)})
+				return
+			}
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported file type %s: use .txt, .md or .csv", header.Filename)})
+			return
+		}
+		documents = append(documents, document)
+	}
+
+	result, err := h.discovery.Discover(c.Request.Context(), documents, minFrequency, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      result.Candidates,
+		"total":     len(result.Candidates),
+		"documents": result.Documents,
+		"sentences": result.Sentences,
+	})
+}
+
+// csvSegments returns the column names and the cells of the selected columns (all when none are selected)
+func csvSegments(content []byte, columns map[string]bool) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Column names are segments of their own: snake_case headers are often undefined jargon
+	segments := append([]string{}, header...)
+	selected := make([]bool, len(header))
+	for i, name := range header {
+		selected[i] = len(columns) == 0 || columns[strings.ToLower(strings.TrimSpace(name))]
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, cell := range record {
+			if i < len(selected) && selected[i] && strings.TrimSpace(cell) != "" {
+				segments = append(segments, cell)
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// ProposeCandidate handles POST /api/v1/discovery/proposals - turns a candidate into a create proposal
+func (h *DiscoveryHandler) ProposeCandidate(c *gin.Context) {
+	var req models.ProposeCandidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	proposal, err := h.discovery.ProposeCandidate(c.Request.Context(), req, &userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
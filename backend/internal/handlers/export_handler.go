@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	skos     *service.SKOSService
+	catalog  *service.CatalogService
+	workbook *service.GlossaryWorkbookService
+}
+
+func NewExportHandler() *ExportHandler {
+	return &ExportHandler{
+		skos:     service.NewSKOSService(),
+		catalog:  service.NewCatalogService(),
+		workbook: service.NewGlossaryWorkbookService(),
+	}
+}
+
+// ExportSKOS handles GET /api/v1/export/skos?format=turtle|jsonld
+//
+// Exports the terms visible to the user as a SKOS concept scheme, as a file download.
+func (h *ExportHandler) ExportSKOS(c *gin.Context) {
+	format, ok := service.ParseSKOSFormat(c.Query("format"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be turtle or jsonld"})
+		return
+	}
+
+	data, err := h.skos.Export(c.Request.Context(), format, middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType, filename := "text/turtle; charset=utf-8", "glossary.ttl"
+	if format == service.SKOSFormatJSONLD {
+		contentType, filename = "application/ld+json", "glossary.jsonld"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ExportDBT handles GET /api/v1/export/dbt?format=docs|schema&model=glossary
+//
+// docs is a markdown file with a docs block per term code name; schema a schema.yml describing a
+// column per code name in the model, referring to the docs blocks.
+func (h *ExportHandler) ExportDBT(c *gin.Context) {
+	var data []byte
+	var err error
+	var contentType, filename string
+	switch c.DefaultQuery("format", "docs") {
+	case "docs":
+		data, err = h.catalog.ExportDBTDocs(c.Request.Context(), middleware.GetUserDepartment(c))
+		contentType, filename = "text/markdown; charset=utf-8", "glossary.md"
+	case "schema":
+		model := c.DefaultQuery("model", service.DBTDefaultModel)
+		data, err = h.catalog.ExportDBTSchema(c.Request.Context(), model, middleware.GetUserDepartment(c))
+		contentType, filename = "application/x-yaml", "schema.yml"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be docs or schema"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ExportOpenMetadata handles GET /api/v1/export/openmetadata
+func (h *ExportHandler) ExportOpenMetadata(c *gin.Context) {
+	data, err := h.catalog.ExportOpenMetadata(c.Request.Context(), middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="openmetadata-glossary.json"`)
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// ExportDataHub handles GET /api/v1/export/datahub
+func (h *ExportHandler) ExportDataHub(c *gin.Context) {
+	data, err := h.catalog.ExportDataHub(c.Request.Context(), middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="datahub-glossary.json"`)
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// ExportXLSX handles GET /api/v1/export/xlsx?category=...&framework=...
+//
+// Exports the terms visible to the user as an Excel workbook with a master term sheet, a sheet
+// per cluster, a cluster comparison matrix and the open gaps. category and framework can be
+// repeated; a term matches any of the values given.
+func (h *ExportHandler) ExportXLSX(c *gin.Context) {
+	filter := service.WorkbookFilter{
+		Categories: queryValues(c, "category"),
+		Frameworks: queryValues(c, "framework"),
+	}
+
+	data, err := h.workbook.Export(c.Request.Context(), filter, middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="glossary.xlsx"`)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+}
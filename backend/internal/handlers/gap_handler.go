@@ -33,6 +33,7 @@ func (h *GapHandler) ListGaps(c *gin.Context) {
 	cluster := c.Query("cluster")
 	severity := c.Query("severity")
 	resolvedStr := c.Query("resolved")
+	dimension := c.Query("dimension")
 
 	var gapTypePtr *string
 	if gapType != "" {
@@ -55,7 +56,12 @@ func (h *GapHandler) ListGaps(c *gin.Context) {
 		resolvedPtr = &resolved
 	}
 
-	gaps, total, err := h.repo.ListGaps(c.Request.Context(), limit, offset, gapTypePtr, clusterPtr, severityPtr, resolvedPtr)
+	var dimensionPtr *string
+	if dimension != "" {
+		dimensionPtr = &dimension
+	}
+
+	gaps, total, err := h.repo.ListGaps(c.Request.Context(), limit, offset, gapTypePtr, clusterPtr, severityPtr, resolvedPtr, dimensionPtr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -91,17 +97,20 @@ func (h *GapHandler) GetGap(c *gin.Context) {
 }
 
 // DetectGaps handles POST /api/v1/gaps/detect
+// The response holds a sample of the detected gaps; page through all of them with GET /gaps
 func (h *GapHandler) DetectGaps(c *gin.Context) {
-	gaps, err := h.service.DetectGaps(c.Request.Context())
+	sample, stats, err := h.service.DetectGaps(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "stats": stats})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Gap detection completed",
-		"gaps_detected": len(gaps),
-		"gaps": gaps,
+		"gaps_detected": stats.GapsDetected,
+		"gaps": sample,
+		"gaps_truncated": stats.GapsDetected > int64(len(sample)),
+		"stats": stats,
 	})
 }
 
@@ -199,16 +208,55 @@ func (h *GapHandler) GetClusterComparison(c *gin.Context) {
 		return
 	}
 
+	// Build term × system and term × product matrices within the cluster
+	systemMatrix, err := h.service.BuildDefinitionMatrix(c.Request.Context(), clusterName, "system")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	productMatrix, err := h.service.BuildDefinitionMatrix(c.Request.Context(), clusterName, "product")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Build comparison data
 	comparison := make(map[string]interface{})
 	comparison["cluster"] = clusterName
 	comparison["terms"] = terms
 	comparison["other_clusters"] = allClusters
+	comparison["system_matrix"] = systemMatrix
+	comparison["product_matrix"] = productMatrix
 
 	c.JSON(http.StatusOK, comparison)
 }
 
+// GetClusterMatrix handles GET /api/v1/clusters/:name/matrix?dimension=system|product
+func (h *GapHandler) GetClusterMatrix(c *gin.Context) {
+	clusterName := c.Param("name")
+	if clusterName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cluster name is required"})
+		return
+	}
+
+	dimension := c.DefaultQuery("dimension", "system")
+	if dimension != "system" && dimension != "product" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dimension must be 'system' or 'product'"})
+		return
+	}
+
+	matrix, err := h.service.BuildDefinitionMatrix(c.Request.Context(), clusterName, dimension)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
+
 // GetTermClusterComparison handles GET /api/v1/terms/:id/cluster-comparison
+// With ?dimension=system|product (and optionally ?cluster=) it compares definitions within clusters instead.
 func (h *GapHandler) GetTermClusterComparison(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -216,6 +264,35 @@ func (h *GapHandler) GetTermClusterComparison(c *gin.Context) {
 		return
 	}
 
+	dimension := c.Query("dimension")
+	if dimension != "" && dimension != "cluster" {
+		if dimension != "system" && dimension != "product" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dimension must be 'cluster', 'system' or 'product'"})
+			return
+		}
+
+		cluster := c.Query("cluster")
+		var clusterPtr *string
+		if cluster != "" {
+			clusterPtr = &cluster
+		}
+
+		contexts, conflicting, err := h.service.CompareContexts(c.Request.Context(), id, dimension, clusterPtr)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"term_id":     id,
+			"dimension":   dimension,
+			"cluster":     clusterPtr,
+			"contexts":    contexts,
+			"conflicting": conflicting,
+		})
+		return
+	}
+
 	comparison, err := h.service.CompareClusters(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
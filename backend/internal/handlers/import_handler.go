@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importMaxUploadBytes bounds the size of the files of one import request
+const importMaxUploadBytes = 20 << 20
+
+type ImportHandler struct {
+	importer *service.GlossaryImportService
+	catalog  *service.CatalogService
+	termIndexes
+}
+
+func NewImportHandler(suggest *service.SuggestIndex, annotator *service.TermAnnotator, recommender *service.TermRecommender) *ImportHandler {
+	return &ImportHandler{
+		importer:    service.NewGlossaryImportService(),
+		catalog:     service.NewCatalogService(),
+		termIndexes: termIndexes{suggest: suggest, annotator: annotator, recommender: recommender},
+	}
+}
+
+// ImportGlossary handles POST /api/v1/import (multipart/form-data)
+//
+// Form fields: file (an .xlsx workbook with terms, contexts, examples, relationships and aliases
+// worksheets) or one CSV file per sheet in the fields terms, contexts, examples, relationships
+// and aliases; dry_run=true validates without importing. Row errors are returned with 422 and
+// nothing is imported.
+func (h *ImportHandler) ImportGlossary(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxUploadBytes)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		respondBodyError(c, err, importMaxUploadBytes)
+		return
+	}
+
+	tables := []*service.ImportTable{}
+	if files := form.File["file"]; len(files) > 0 {
+		if len(files) > 1 || strings.ToLower(filepath.Ext(files[0].Filename)) != ".xlsx" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the 'file' field takes a single .xlsx workbook; send CSV files in the terms, contexts, examples, relationships and aliases fields"})
+			return
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read %s: %v", files[0].Filename, err)})
+			return
+		}
+		tables, err = service.ReadImportXLSX(file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	for _, sheet := range service.ImportSheets {
+		files := form.File[sheet]
+		if len(files) == 0 {
+			continue
+		}
+		if len(files) > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("only one %s file can be imported at once", sheet)})
+			return
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read %s: %v", files[0].Filename, err)})
+			return
+		}
+		table, err := service.ReadImportCSV(sheet, file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tables = append(tables, table)
+	}
+
+	if len(tables) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no import sheets found: send an .xlsx workbook in 'file' or CSV files in terms, contexts, examples, relationships or aliases"})
+		return
+	}
+
+	dryRun := c.PostForm("dry_run") == "true" || c.Query("dry_run") == "true"
+	userID := middleware.GetUserID(c)
+
+	result, err := h.importer.Import(c.Request.Context(), tables, dryRun, &userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondImport(c, result)
+}
+
+// ImportSKOS handles POST /api/v1/import/skos
+//
+// The body is a SKOS vocabulary in Turtle or JSON-LD, chosen by ?format=turtle|jsonld or the
+// Content-Type. Concepts are imported as terms with the validation of ImportGlossary;
+// dry_run=true validates without importing. Row errors name the concept they come from.
+func (h *ImportHandler) ImportSKOS(c *gin.Context) {
+	format, ok := service.ParseSKOSFormat(c.Query("format"))
+	if c.Query("format") == "" {
+		format, ok = service.ParseSKOSFormat(c.ContentType())
+		if !ok {
+			format, ok = service.SKOSFormatTurtle, true
+		}
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be turtle or jsonld"})
+		return
+	}
+
+	data, ok := readImportBody(c)
+	if !ok {
+		return
+	}
+	source, err := service.ReadSKOS(data, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.importConverted(c, source)
+}
+
+// ImportDBT handles POST /api/v1/import/dbt (multipart/form-data)
+//
+// Form fields: docs (markdown files with docs blocks) and schema (properties YAML files), each
+// repeatable; dry_run=true validates without importing. Columns become terms keyed by code name.
+func (h *ImportHandler) ImportDBT(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxUploadBytes)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		respondBodyError(c, err, importMaxUploadBytes)
+		return
+	}
+
+	project := service.NewDBTProject()
+	for _, field := range []string{"docs", "schema"} {
+		for _, header := range form.File[field] {
+			file, err := header.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read %s: %v", header.Filename, err)})
+				return
+			}
+			if field == "docs" {
+				err = project.AddDocs(header.Filename, file)
+			} else {
+				err = project.AddSchema(header.Filename, file)
+			}
+			file.Close()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+	if project.Empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no dbt files found: send docs blocks in 'docs' or schema.yml files in 'schema'"})
+		return
+	}
+
+	source, err := h.catalog.ConvertDBT(c.Request.Context(), project)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.importConverted(c, source)
+}
+
+// ImportOpenMetadata handles POST /api/v1/import/openmetadata
+//
+// The body is OpenMetadata glossary JSON: the export of GET /export/openmetadata, a list of
+// glossary terms or a glossaryTerms API response. dry_run=true validates without importing.
+func (h *ImportHandler) ImportOpenMetadata(c *gin.Context) {
+	data, ok := readImportBody(c)
+	if !ok {
+		return
+	}
+	source, err := service.ReadOpenMetadata(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.importConverted(c, source)
+}
+
+// ImportDataHub handles POST /api/v1/import/datahub
+//
+// The body is a DataHub JSON file of metadata change proposals or events, such as the export of
+// GET /export/datahub. dry_run=true validates without importing.
+func (h *ImportHandler) ImportDataHub(c *gin.Context) {
+	data, ok := readImportBody(c)
+	if !ok {
+		return
+	}
+	source, err := service.ReadDataHub(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.importConverted(c, source)
+}
+
+// readImportBody reads the raw body of an import request, responding on failure
+func readImportBody(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxUploadBytes)
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBodyError(c, err, importMaxUploadBytes)
+		return nil, false
+	}
+	return data, true
+}
+
+// importConverted imports a source converted from another format
+func (h *ImportHandler) importConverted(c *gin.Context, source *service.ImportSource) {
+	dryRun := c.Query("dry_run") == "true" || c.PostForm("dry_run") == "true"
+	userID := middleware.GetUserID(c)
+
+	result, err := h.importer.ImportConverted(c.Request.Context(), source, dryRun, &userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondImport(c, result)
+}
+
+// respondImport reports the result of an import and refreshes the indexes after changes
+func (h *ImportHandler) respondImport(c *gin.Context, result *models.ImportResult) {
+	if len(result.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   fmt.Sprintf("import has %d errors, nothing was imported", len(result.Errors)),
+			"errors":  result.Errors,
+			"dry_run": result.DryRun,
+		})
+		return
+	}
+
+	if !result.DryRun {
+		h.invalidateIndexes()
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	repo *repository.NotificationRepository
+}
+
+func NewNotificationHandler() *NotificationHandler {
+	return &NotificationHandler{
+		repo: repository.NewNotificationRepository(),
+	}
+}
+
+// ListNotifications handles GET /api/v1/notifications?unread=true
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.repo.ListNotifications(c.Request.Context(), middleware.GetUserID(c), unreadOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   notifications,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// MarkNotificationRead handles PATCH /api/v1/notifications/:id/read
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	if err := h.repo.MarkNotificationRead(c.Request.Context(), id, middleware.GetUserID(c)); err != nil {
+		if err.Error() == "notification not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
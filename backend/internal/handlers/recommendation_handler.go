@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// recommendationsMaxLimit bounds the number of recommendations per request
+const recommendationsMaxLimit = 50
+
+type RecommendationHandler struct {
+	repo        *repository.TermRepository
+	recommender *service.TermRecommender
+	suggest     *service.SuggestIndex
+}
+
+func NewRecommendationHandler(recommender *service.TermRecommender, suggest *service.SuggestIndex) *RecommendationHandler {
+	return &RecommendationHandler{
+		repo:        repository.NewTermRepository(),
+		recommender: recommender,
+		suggest:     suggest,
+	}
+}
+
+// ListRecommendations handles GET /api/v1/terms/:id/recommendations?limit=10
+func (h *RecommendationHandler) ListRecommendations(c *gin.Context) {
+	termID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > recommendationsMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+		return
+	}
+
+	recommendations, err := h.recommender.Recommend(c.Request.Context(), termID, middleware.GetUserDepartment(c), middleware.DefaultUserID, limit)
+	if err != nil {
+		if err.Error() == "term not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"term_id":         termID,
+		"recommendations": recommendations,
+		"total":           len(recommendations),
+	})
+}
+
+// AcceptRecommendation handles POST /api/v1/terms/:id/recommendations/accept and records a
+// recommended term as a relationship of the given type (default related)
+func (h *RecommendationHandler) AcceptRecommendation(c *gin.Context) {
+	termID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	var req models.AcceptRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.RelatedTermID == termID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a term cannot be related to itself"})
+		return
+	}
+	if req.RelationshipType == "" {
+		req.RelationshipType = "related"
+	}
+
+	for _, id := range []uuid.UUID{termID, req.RelatedTermID} {
+		if _, err := h.repo.GetTermByID(c.Request.Context(), id); err != nil {
+			if err.Error() == "term not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+
+	relationship, err := h.repo.CreateRelationship(c.Request.Context(), termID, models.CreateRelationshipRequest{
+		RelatedTermID:    req.RelatedTermID,
+		RelationshipType: req.RelationshipType,
+	}, &userID)
+	if err != nil {
+		if err.Error() == "relationship already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.suggest.Invalidate()
+	c.JSON(http.StatusCreated, relationship)
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SavedSearchHandler struct {
+	repo     *repository.SavedSearchRepository
+	termRepo *repository.TermRepository
+	alerts   *service.SavedSearchAlertService
+}
+
+func NewSavedSearchHandler(alerts *service.SavedSearchAlertService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		repo:     repository.NewSavedSearchRepository(),
+		termRepo: repository.NewTermRepository(),
+		alerts:   alerts,
+	}
+}
+
+// CreateSavedSearch handles POST /api/v1/saved-searches
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req models.CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := service.ParseSearchQuery(req.Query); err != nil {
+		respondSearchQueryError(c, err)
+		return
+	}
+
+	department := middleware.GetUserDepartment(c)
+	if req.Notify {
+		candidate := &models.SavedSearch{Query: req.Query, Filters: req.Filters, Department: department}
+		if !h.checkAlertScope(c, candidate) {
+			return
+		}
+	}
+
+	search, err := h.repo.CreateSavedSearch(c.Request.Context(), middleware.GetUserID(c), department, req)
+	if err != nil {
+		switch err.Error() {
+		case "saved search name already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "user not found":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "saved searches need a known user in X-User-ID"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// checkAlertScope responds 400 when a search matches too many terms for change alerts
+func (h *SavedSearchHandler) checkAlertScope(c *gin.Context, search *models.SavedSearch) bool {
+	err := h.alerts.CheckScope(c.Request.Context(), search)
+	if err == nil {
+		return true
+	}
+	var queryErr *service.SearchQueryError
+	switch {
+	case errors.As(err, &queryErr):
+		respondSearchQueryError(c, err)
+	case strings.HasPrefix(err.Error(), "search matches more than"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error() + "; narrow it to enable notify"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return false
+}
+
+// ListSavedSearches handles GET /api/v1/saved-searches
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	searches, err := h.repo.ListSavedSearches(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": searches, "total": len(searches)})
+}
+
+// GetSavedSearch handles GET /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	search, err := h.repo.GetSavedSearch(c.Request.Context(), id, middleware.GetUserID(c))
+	if err != nil {
+		if err.Error() == "saved search not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// UpdateSavedSearch handles PUT /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	var req models.UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Query != nil {
+		if *req.Query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query must not be empty"})
+			return
+		}
+		if _, err := service.ParseSearchQuery(*req.Query); err != nil {
+			respondSearchQueryError(c, err)
+			return
+		}
+	}
+
+	// Searches with alerts must stay within the number of terms the alert check tracks
+	department := middleware.GetUserDepartment(c)
+	if req.Query != nil || req.Filters != nil || (req.Notify != nil && *req.Notify) {
+		current, err := h.repo.GetSavedSearch(c.Request.Context(), id, middleware.GetUserID(c))
+		if err != nil {
+			if err.Error() == "saved search not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Query != nil {
+			current.Query = *req.Query
+		}
+		if req.Filters != nil {
+			current.Filters = *req.Filters
+		}
+		if req.Query != nil || req.Filters != nil {
+			current.Department = department
+		}
+		if req.Notify != nil {
+			current.Notify = *req.Notify
+		}
+		if current.Notify && !h.checkAlertScope(c, current) {
+			return
+		}
+	}
+
+	search, err := h.repo.UpdateSavedSearch(c.Request.Context(), id, middleware.GetUserID(c), department, req)
+	if err != nil {
+		switch err.Error() {
+		case "saved search not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "saved search name already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// DeleteSavedSearch handles DELETE /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	if err := h.repo.DeleteSavedSearch(c.Request.Context(), id, middleware.GetUserID(c)); err != nil {
+		if err.Error() == "saved search not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "saved search deleted successfully"})
+}
+
+// RunSavedSearch handles GET /api/v1/saved-searches/:id/run?limit=20&offset=0
+func (h *SavedSearchHandler) RunSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	search, err := h.repo.GetSavedSearch(c.Request.Context(), id, middleware.GetUserID(c))
+	if err != nil {
+		if err.Error() == "saved search not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	req, withContexts, err := service.SavedSearchRequest(search)
+	if err != nil {
+		respondSearchQueryError(c, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	req.Limit = limit
+	req.Offset = offset
+	req.SkipFacets = c.Query("facets") == "false"
+	req.UserDepartment = middleware.GetUserDepartment(c)
+
+	var results *models.SearchResults
+	if withContexts {
+		results, err = h.termRepo.SearchTermsWithContext(c.Request.Context(), req)
+	} else {
+		results, err = h.termRepo.SearchTerms(c.Request.Context(), req)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.MarkSavedSearchRun(c.Request.Context(), search.ID, time.Now()); err != nil {
+		log.Printf("Warning: Failed to record saved search run: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"saved_search": search,
+		"data":         results.Hits,
+		"total":        results.Total,
+		"limit":        limit,
+		"offset":       offset,
+		"fuzzy":        results.Fuzzy,
+		"did_you_mean": results.Suggestions,
+		"facets":       results.Facets,
+	})
+}
+
+// CheckSavedSearches handles POST /api/v1/saved-searches/check - runs the change alert check now
+func (h *SavedSearchHandler) CheckSavedSearches(c *gin.Context) {
+	result, err := h.alerts.Run(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
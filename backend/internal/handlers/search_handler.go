@@ -1,22 +1,38 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"clarityconnect/internal/middleware"
 	"clarityconnect/internal/models"
 	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type SearchHandler struct {
-	repo *repository.TermRepository
+	repo      *repository.TermRepository
+	usageRepo *repository.UsageRepository
+	recorder  *service.UsageRecorder
+	suggest   *service.SuggestIndex
+	weights   models.SearchRankWeights // personalized ranking defaults
 }
 
-func NewSearchHandler() *SearchHandler {
+func NewSearchHandler(recorder *service.UsageRecorder, suggest *service.SuggestIndex) *SearchHandler {
 	return &SearchHandler{
-		repo: repository.NewTermRepository(),
+		repo:      repository.NewTermRepository(),
+		usageRepo: repository.NewUsageRepository(),
+		recorder:  recorder,
+		suggest:   suggest,
+		weights:   service.SearchRankWeightsFromEnv(),
 	}
 }
 
@@ -30,20 +46,31 @@ func (h *SearchHandler) SearchTerms(c *gin.Context) {
 		return
 	}
 
-	if category := c.Query("category"); category != "" {
-		req.Category = &category
-	}
-
-	if cluster := c.Query("cluster"); cluster != "" {
-		req.Cluster = &cluster
+	// Advanced syntax: phrases, OR, negation and field filters such as cluster:Retail
+	expr, err := service.ParseSearchQuery(req.Query)
+	if err != nil {
+		respondSearchQueryError(c, err)
+		return
 	}
+	req.Expr = expr
 
-	if system := c.Query("system"); system != "" {
-		req.System = &system
-	}
+	// Filters are multi-select: repeat a parameter to OR values, e.g. ?cluster=Retail&cluster=Corporate
+	req.Categories = queryValues(c, "category")
+	req.Clusters = queryValues(c, "cluster")
+	req.Systems = queryValues(c, "system")
+	req.Products = queryValues(c, "product")
+	req.Tags = queryValues(c, "tags")
+	req.ComplianceFrameworks = queryValues(c, "compliance_frameworks")
+	req.Visibility = queryValues(c, "visibility")
+	req.SkipFacets = c.Query("facets") == "false"
 
-	if tags := c.QueryArray("tags"); len(tags) > 0 {
-		req.Tags = tags
+	req.HighlightPre = c.Query("highlight_pre")
+	req.HighlightPost = c.Query("highlight_post")
+	for _, marker := range []string{req.HighlightPre, req.HighlightPost} {
+		if len(marker) > 32 || strings.ContainsAny(marker, "\"\\") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "highlight markers must be at most 32 characters and must not contain quotes or backslashes"})
+			return
+		}
 	}
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
@@ -51,15 +78,27 @@ func (h *SearchHandler) SearchTerms(c *gin.Context) {
 	req.Limit = limit
 	req.Offset = offset
 
-	// Use context-aware search if cluster or system filters are provided
-	var terms []models.Term
-	var total int
-	var err error
+	// Personalized ranking blends in usage from the caller's cluster/department and term status.
+	// In debug mode the weights can be overridden per request to tune them.
+	req.Personalized = c.Query("rank") == "personalized"
+	req.Debug = c.Query("debug") == "true"
+	req.RankWeights = h.weights
+	if req.Debug {
+		if err := overrideRankWeights(c, &req.RankWeights); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	req.UserCluster = middleware.GetUserCluster(c)
+	req.UserDepartment = middleware.GetUserDepartment(c)
+
+	// Use context-aware search if cluster, system or product filters are provided
+	var results *models.SearchResults
 
-	if req.Cluster != nil || req.System != nil {
-		terms, total, err = h.repo.SearchTermsWithContext(c.Request.Context(), req)
+	if len(req.Clusters) > 0 || len(req.Systems) > 0 || len(req.Products) > 0 {
+		results, err = h.repo.SearchTermsWithContext(c.Request.Context(), req)
 	} else {
-		terms, total, err = h.repo.SearchTerms(c.Request.Context(), req)
+		results, err = h.repo.SearchTerms(c.Request.Context(), req)
 	}
 
 	if err != nil {
@@ -67,12 +106,178 @@ func (h *SearchHandler) SearchTerms(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  terms,
-		"total": total,
+	searchID := h.logSearch(c, req, results.Total)
+
+	response := gin.H{
+		"data":  results.Hits,
+		"total": results.Total,
 		"limit": limit,
 		"offset": offset,
 		"query":  req.Query,
+		"search_id": searchID,
+		"fuzzy": results.Fuzzy,
+		"did_you_mean": results.Suggestions,
+		"facets": results.Facets,
+	}
+	if req.Debug {
+		response["personalized"] = req.Personalized
+		response["rank_weights"] = req.RankWeights
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// overrideRankWeights applies weight_popularity, weight_affinity, boost_certified, boost_approved
+// and demote_deprecated query parameters
+func overrideRankWeights(c *gin.Context, weights *models.SearchRankWeights) error {
+	for key, weight := range map[string]*float64{
+		"weight_popularity": &weights.Popularity,
+		"weight_affinity":   &weights.Affinity,
+		"boost_certified":   &weights.Certified,
+		"boost_approved":    &weights.Approved,
+		"demote_deprecated": &weights.Deprecated,
+	} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+			return fmt.Errorf("%s must be a non-negative number", key)
+		}
+		*weight = parsed
+	}
+	return nil
+}
+
+// respondSearchQueryError reports an invalid advanced query with the position and token at fault
+func respondSearchQueryError(c *gin.Context, err error) {
+	var queryErr *service.SearchQueryError
+	if errors.As(err, &queryErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error(), "position": queryErr.Position, "token": queryErr.Token})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// queryValues returns the non-empty values of a repeated query parameter
+func queryValues(c *gin.Context, key string) []string {
+	values := []string{}
+	for _, value := range c.QueryArray(key) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// SuggestTerms handles GET /api/v1/search/suggest?q=prefix&limit=10
+func (h *SearchHandler) SuggestTerms(c *gin.Context) {
+	prefix := c.Query("q")
+	if strings.TrimSpace(prefix) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+		return
+	}
+
+	suggestions := h.suggest.Suggest(prefix, middleware.GetUserDepartment(c), limit)
+
+	c.JSON(http.StatusOK, gin.H{"data": suggestions, "query": prefix})
+}
+
+// logSearch queues the query and its result count for the usage recorder. Logging never fails
+// the search; the returned ID is nil if the search was dropped, and the client simply cannot
+// report a click-through.
+func (h *SearchHandler) logSearch(c *gin.Context, req models.SearchRequest, total int) *uuid.UUID {
+	filters := map[string]interface{}{}
+	for key, values := range map[string][]string{
+		"category":              req.Categories,
+		"cluster":               req.Clusters,
+		"system":                req.Systems,
+		"product":               req.Products,
+		"tags":                  req.Tags,
+		"compliance_frameworks": req.ComplianceFrameworks,
+		"visibility":            req.Visibility,
+	} {
+		if len(values) > 0 {
+			filters[key] = values
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+	searchLog := models.SearchLog{
+		ID:          uuid.New(),
+		Query:       req.Query,
+		Filters:     filters,
+		ResultCount: total,
+		UserID:      &userID,
+		Department:  middleware.GetUserDepartment(c),
+		CreatedAt:   time.Now(),
+	}
+
+	if !h.recorder.RecordSearch(searchLog) {
+		return nil
+	}
+
+	return &searchLog.ID
+}
+
+// RecordSearchClick handles POST /api/v1/search/:id/click
+func (h *SearchHandler) RecordSearchClick(c *gin.Context) {
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid search ID"})
+		return
+	}
+
+	var req models.SearchClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exists, err := h.usageRepo.TermExists(c.Request.Context(), req.TermID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+		return
+	}
+
+	// Recent searches may still be buffered by the recorder rather than written
+	searchLog, buffered := h.recorder.ClickSearch(searchID, req.TermID)
+	if !buffered {
+		searchLog, err = h.usageRepo.RecordSearchClick(c.Request.Context(), searchID, req.TermID)
+		if err != nil {
+			if err.Error() == "search not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// A click-through counts as the term being found by searching
+	userID := middleware.GetUserID(c)
+	h.recorder.Record(models.TermUsageLog{
+		TermID:     req.TermID,
+		Cluster:    req.Cluster,
+		UserID:     &userID,
+		Department: middleware.GetUserDepartment(c),
+		Action:     "searched",
 	})
+
+	c.JSON(http.StatusOK, searchLog)
 }
 
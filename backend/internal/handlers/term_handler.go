@@ -7,18 +7,35 @@ import (
 	"clarityconnect/internal/middleware"
 	"clarityconnect/internal/models"
 	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// termIndexes are the in-memory indexes built from terms, rebuilt after terms change
+type termIndexes struct {
+	suggest     *service.SuggestIndex
+	annotator   *service.TermAnnotator
+	recommender *service.TermRecommender
+}
+
+// invalidateIndexes schedules a rebuild of all indexes
+func (t termIndexes) invalidateIndexes() {
+	t.suggest.Invalidate()
+	t.annotator.Invalidate()
+	t.recommender.Invalidate()
+}
+
 type TermHandler struct {
 	repo *repository.TermRepository
+	termIndexes
 }
 
-func NewTermHandler() *TermHandler {
+func NewTermHandler(suggest *service.SuggestIndex, annotator *service.TermAnnotator, recommender *service.TermRecommender) *TermHandler {
 	return &TermHandler{
-		repo: repository.NewTermRepository(),
+		repo:        repository.NewTermRepository(),
+		termIndexes: termIndexes{suggest: suggest, annotator: annotator, recommender: recommender},
 	}
 }
 
@@ -39,6 +56,7 @@ func (h *TermHandler) CreateTerm(c *gin.Context) {
 		return
 	}
 
+	h.invalidateIndexes()
 	c.JSON(http.StatusCreated, term)
 }
 
@@ -148,6 +166,7 @@ func (h *TermHandler) UpdateTerm(c *gin.Context) {
 		return
 	}
 
+	h.invalidateIndexes()
 	c.JSON(http.StatusOK, term)
 }
 
@@ -169,6 +188,7 @@ func (h *TermHandler) DeleteTerm(c *gin.Context) {
 		return
 	}
 
+	h.invalidateIndexes()
 	c.JSON(http.StatusOK, gin.H{"message": "term deleted successfully"})
 }
 
@@ -194,6 +214,7 @@ func (h *TermHandler) CreateContext(c *gin.Context) {
 		return
 	}
 
+	h.recommender.Invalidate()
 	c.JSON(http.StatusCreated, context)
 }
 
@@ -248,6 +269,75 @@ func (h *TermHandler) CreateRelationship(c *gin.Context) {
 		return
 	}
 
+	h.suggest.Invalidate()
 	c.JSON(http.StatusCreated, relationship)
 }
 
+// CreateAlias handles POST /api/v1/terms/:id/aliases
+func (h *TermHandler) CreateAlias(c *gin.Context) {
+	termID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	var req models.CreateAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.repo.GetTermByID(c.Request.Context(), termID); err != nil {
+		if err.Error() == "term not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+
+	alias, err := h.repo.CreateAlias(c.Request.Context(), termID, req, &userID)
+	if err != nil {
+		if err.Error() == "alias already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.suggest.Invalidate()
+	h.annotator.Invalidate()
+	c.JSON(http.StatusCreated, alias)
+}
+
+// DeleteAlias handles DELETE /api/v1/terms/:id/aliases/:aliasId
+func (h *TermHandler) DeleteAlias(c *gin.Context) {
+	termID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	aliasID, err := uuid.Parse(c.Param("aliasId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alias ID"})
+		return
+	}
+
+	err = h.repo.DeleteAlias(c.Request.Context(), termID, aliasID)
+	if err != nil {
+		if err.Error() == "alias not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.suggest.Invalidate()
+	h.annotator.Invalidate()
+	c.JSON(http.StatusOK, gin.H{"message": "alias deleted successfully"})
+}
@@ -1,19 +1,47 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"clarityconnect/internal/middleware"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
 	"clarityconnect/pkg/database"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-type UsageHandler struct{}
+type UsageHandler struct {
+	repo      *repository.UsageRepository
+	recorder  *service.UsageRecorder
+	retention *service.UsageRetentionService
+}
+
+// usageMaxLimit caps the rows the usage analytics endpoints return
+const usageMaxLimit = 500
+
+func NewUsageHandler(recorder *service.UsageRecorder, retention *service.UsageRetentionService) *UsageHandler {
+	return &UsageHandler{
+		repo:      repository.NewUsageRepository(),
+		recorder:  recorder,
+		retention: retention,
+	}
+}
 
-func NewUsageHandler() *UsageHandler {
-	return &UsageHandler{}
+// parseUsageLimit reads the limit query parameter, responding 400 when it is not between 1 and usageMaxLimit
+func parseUsageLimit(c *gin.Context, defaultLimit string) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil || limit < 1 || limit > usageMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", usageMaxLimit)})
+		return 0, false
+	}
+	return limit, true
 }
 
 // GetTermViewCount returns the view count for a term
@@ -24,10 +52,11 @@ func (h *UsageHandler) GetTermViewCount(c *gin.Context) {
 		return
 	}
 
+	// Includes views that have been rolled up and purged from the raw logs
 	var count int
 	query := `
-		SELECT COUNT(*)
-		FROM term_usage_logs
+		SELECT COALESCE(SUM(event_count), 0)::bigint
+		FROM term_usage_events
 		WHERE term_id = $1 AND action = 'viewed'
 	`
 
@@ -42,21 +71,27 @@ func (h *UsageHandler) GetTermViewCount(c *gin.Context) {
 
 // GetRecentlyViewedTerms returns recently viewed terms for a user
 func (h *UsageHandler) GetRecentlyViewedTerms(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	limit, ok := parseUsageLimit(c, "10")
+	if !ok {
+		return
+	}
 	
-	// For POC, use default user ID
-	defaultUserID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	userID := middleware.GetUserID(c)
 
 	query := `
-		SELECT DISTINCT ON (tul.term_id) tul.term_id, t.term, t.base_definition, tul.created_at as last_viewed
-		FROM term_usage_logs tul
-		JOIN terms t ON tul.term_id = t.id
-		WHERE tul.user_id = $1 AND tul.action = 'viewed'
-		ORDER BY tul.term_id, tul.created_at DESC
+		SELECT term_id, term, base_definition, last_viewed
+		FROM (
+			SELECT DISTINCT ON (tul.term_id) tul.term_id, t.term, t.base_definition, tul.created_at as last_viewed
+			FROM term_usage_logs tul
+			JOIN terms t ON tul.term_id = t.id
+			WHERE tul.user_id = $1 AND tul.action = 'viewed'
+			ORDER BY tul.term_id, tul.created_at DESC
+		) recent
+		ORDER BY last_viewed DESC
 		LIMIT $2
 	`
 
-	rows, err := database.DB.Query(c.Request.Context(), query, defaultUserID, limit)
+	rows, err := database.DB.Query(c.Request.Context(), query, userID, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get recently viewed terms"})
 		return
@@ -67,23 +102,363 @@ func (h *UsageHandler) GetRecentlyViewedTerms(c *gin.Context) {
 		TermID        uuid.UUID `json:"term_id"`
 		Term          string    `json:"term"`
 		BaseDefinition string  `json:"base_definition"`
-		LastViewed    string    `json:"last_viewed"`
+		LastViewed    time.Time `json:"last_viewed"`
 	}
 
-	var terms []RecentTerm
+	terms := []RecentTerm{}
 	for rows.Next() {
 		var rt RecentTerm
-		var lastViewed interface{}
-		err := rows.Scan(&rt.TermID, &rt.Term, &rt.BaseDefinition, &lastViewed)
-		if err != nil {
-			continue
-		}
-		if lastViewed != nil {
-			rt.LastViewed = lastViewed.(string)
+		if err := rows.Scan(&rt.TermID, &rt.Term, &rt.BaseDefinition, &rt.LastViewed); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get recently viewed terms"})
+			return
 		}
 		terms = append(terms, rt)
 	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get recently viewed terms"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"data": terms})
 }
 
+// GetPipelineStats handles GET /api/v1/usage/pipeline
+func (h *UsageHandler) GetPipelineStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.recorder.Stats())
+}
+
+// RunRetention handles POST /api/v1/usage/retention/run
+func (h *UsageHandler) RunRetention(c *gin.Context) {
+	result, err := h.retention.Run(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseUsageFilter reads the period (?days= or ?from=&to=) and the action, cluster and department filters
+func parseUsageFilter(c *gin.Context, defaultAction string) (models.UsageFilter, error) {
+	filter := models.UsageFilter{
+		To:             time.Now(),
+		UserDepartment: middleware.GetUserDepartment(c),
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := parseUsageTime(to)
+		if err != nil {
+			return filter, fmt.Errorf("invalid 'to' date")
+		}
+		filter.To = parsed
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := parseUsageTime(from)
+		if err != nil {
+			return filter, fmt.Errorf("invalid 'from' date")
+		}
+		filter.From = parsed
+	} else {
+		days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+		if err != nil || days <= 0 {
+			return filter, fmt.Errorf("days must be a positive integer")
+		}
+		filter.From = filter.To.AddDate(0, 0, -days)
+	}
+
+	if !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("'from' must be before 'to'")
+	}
+
+	action := c.DefaultQuery("action", defaultAction)
+	if action != "" && action != "all" {
+		filter.Action = &action
+	}
+
+	if cluster := c.Query("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+
+	if department := c.Query("usage_department"); department != "" {
+		filter.Department = &department
+	}
+
+	return filter, nil
+}
+
+// parseUsageTime accepts either a date (YYYY-MM-DD) or an RFC 3339 timestamp
+func parseUsageTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// GetTopTerms handles GET /api/v1/analytics/usage/top-terms
+func (h *UsageHandler) GetTopTerms(c *gin.Context) {
+	filter, err := parseUsageFilter(c, "viewed")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, ok := parseUsageLimit(c, "10")
+	if !ok {
+		return
+	}
+
+	terms, err := h.repo.GetTopTerms(c.Request.Context(), filter, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": terms, "from": filter.From, "to": filter.To, "action": filter.Action})
+}
+
+// GetUsageBreakdown handles GET /api/v1/analytics/usage/breakdown?by=cluster|department
+func (h *UsageHandler) GetUsageBreakdown(c *gin.Context) {
+	filter, err := parseUsageFilter(c, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	by := c.DefaultQuery("by", "cluster")
+	if by != "cluster" && by != "department" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "by must be 'cluster' or 'department'"})
+		return
+	}
+
+	breakdown, err := h.repo.GetUsageBreakdown(c.Request.Context(), filter, by)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": breakdown, "by": by, "from": filter.From, "to": filter.To})
+}
+
+// GetDepartmentHeatmap handles GET /api/v1/analytics/usage/heatmap?columns=cluster|category
+func (h *UsageHandler) GetDepartmentHeatmap(c *gin.Context) {
+	filter, err := parseUsageFilter(c, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	column := c.DefaultQuery("columns", "cluster")
+	if column != "cluster" && column != "category" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "columns must be 'cluster' or 'category'"})
+		return
+	}
+
+	cells, err := h.repo.GetDepartmentHeatmap(c.Request.Context(), filter, column)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cells, "columns": column, "from": filter.From, "to": filter.To})
+}
+
+// GetUsageTimeSeries handles GET /api/v1/analytics/usage/timeseries?bucket=hour|day|week|month
+func (h *UsageHandler) GetUsageTimeSeries(c *gin.Context) {
+	filter, err := parseUsageFilter(c, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bucket := c.DefaultQuery("bucket", "day")
+	if bucket != "hour" && bucket != "day" && bucket != "week" && bucket != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bucket must be 'hour', 'day', 'week' or 'month'"})
+		return
+	}
+
+	var termIDPtr *uuid.UUID
+	if termIDStr := c.Query("term_id"); termIDStr != "" {
+		termID, err := uuid.Parse(termIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+			return
+		}
+		termIDPtr = &termID
+	}
+
+	series, err := h.repo.GetUsageTimeSeries(c.Request.Context(), filter, bucket, termIDPtr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series, "bucket": bucket, "from": filter.From, "to": filter.To})
+}
+
+// GetTrendingTerms handles GET /api/v1/analytics/usage/trending?window=7&baseline=28
+func (h *UsageHandler) GetTrendingTerms(c *gin.Context) {
+	window, err := strconv.Atoi(c.DefaultQuery("window", "7"))
+	if err != nil || window <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive number of days"})
+		return
+	}
+
+	baseline, err := strconv.Atoi(c.DefaultQuery("baseline", "28"))
+	if err != nil || baseline <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "baseline must be a positive number of days"})
+		return
+	}
+
+	minCount, err := strconv.Atoi(c.DefaultQuery("min_count", "3"))
+	if err != nil || minCount < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_count must be a positive number"})
+		return
+	}
+	limit, ok := parseUsageLimit(c, "10")
+	if !ok {
+		return
+	}
+
+	action := c.DefaultQuery("action", "viewed")
+	now := time.Now()
+	recentStart := now.AddDate(0, 0, -window)
+	filter := models.UsageFilter{
+		From:           recentStart.AddDate(0, 0, -baseline),
+		To:             now,
+		UserDepartment: middleware.GetUserDepartment(c),
+	}
+	if action != "all" {
+		filter.Action = &action
+	}
+	if cluster := c.Query("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+
+	trending, err := h.repo.GetTrendingTerms(c.Request.Context(), filter, recentStart, minCount, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": trending, "window_days": window, "baseline_days": baseline})
+}
+
+// GetUnusedTerms handles GET /api/v1/analytics/usage/unused?days=90
+func (h *UsageHandler) GetUnusedTerms(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+		return
+	}
+
+	limit, ok := parseUsageLimit(c, "50")
+	if !ok {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	terms, err := h.repo.GetUnusedTerms(c.Request.Context(), cutoff, middleware.GetUserDepartment(c), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": terms, "days": days, "total": len(terms)})
+}
+
+// CreateTermReferences handles POST /api/v1/usage/references
+func (h *UsageHandler) CreateTermReferences(c *gin.Context) {
+	var req models.CreateTermReferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, ref := range req.References {
+		if ref.ReferenceType != nil && !validReferenceTypes[*ref.ReferenceType] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid reference_type %q", *ref.ReferenceType)})
+			return
+		}
+	}
+
+	references, err := h.repo.UpsertTermReferences(c.Request.Context(), req.References)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "term not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	for _, ref := range req.References {
+		h.recorder.Record(models.TermUsageLog{
+			TermID:     ref.TermID,
+			Cluster:    ref.Cluster,
+			Department: middleware.GetUserDepartment(c),
+			UserID:     &userID,
+			Action:     "referenced",
+		})
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": references, "total": len(references)})
+}
+
+var validReferenceTypes = map[string]bool{
+	"report":      true,
+	"dashboard":   true,
+	"pipeline":    true,
+	"application": true,
+	"other":       true,
+}
+
+// GetTermReferenceReport handles GET /api/v1/analytics/references?system=&term_id=
+func (h *UsageHandler) GetTermReferenceReport(c *gin.Context) {
+	var system *string
+	if value := c.Query("system"); value != "" {
+		system = &value
+	}
+
+	var termID *uuid.UUID
+	if value := c.Query("term_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+			return
+		}
+		termID = &parsed
+	}
+
+	report, err := h.repo.GetTermReferenceReport(c.Request.Context(), system, termID, middleware.GetUserDepartment(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report, "total": len(report)})
+}
+
+// GetZeroResultQueries handles GET /api/v1/analytics/search/zero-results?days=30
+func (h *UsageHandler) GetZeroResultQueries(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+		return
+	}
+
+	limit, ok := parseUsageLimit(c, "50")
+	if !ok {
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	queries, err := h.repo.GetZeroResultQueries(c.Request.Context(), since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": queries, "days": days, "total": len(queries)})
+}
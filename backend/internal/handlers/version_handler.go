@@ -6,6 +6,7 @@ import (
 
 	"clarityconnect/internal/models"
 	"clarityconnect/internal/repository"
+	"clarityconnect/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,12 +15,14 @@ import (
 type VersionHandler struct {
 	versionRepo *repository.VersionRepository
 	termRepo    *repository.TermRepository
+	termIndexes
 }
 
-func NewVersionHandler() *VersionHandler {
+func NewVersionHandler(suggest *service.SuggestIndex, annotator *service.TermAnnotator, recommender *service.TermRecommender) *VersionHandler {
 	return &VersionHandler{
 		versionRepo: repository.NewVersionRepository(),
 		termRepo:    repository.NewTermRepository(),
+		termIndexes: termIndexes{suggest: suggest, annotator: annotator, recommender: recommender},
 	}
 }
 
@@ -223,6 +226,7 @@ func (h *VersionHandler) RollbackVersion(c *gin.Context) {
 		return
 	}
 
+	h.invalidateIndexes()
 	c.JSON(http.StatusOK, updatedTerm)
 }
 
@@ -10,7 +10,7 @@ func SetupCORS() gin.HandlerFunc {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:3000", "http://localhost:5173"}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-User-Department"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-User-Department", "X-User-ID"}
 	config.AllowCredentials = true
 
 	return cors.New(config)
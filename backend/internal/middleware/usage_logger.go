@@ -1,55 +1,50 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
-	"strings"
+	"time"
 
-	"clarityconnect/pkg/database"
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// UsageLoggerMiddleware logs term views for analytics
-func UsageLoggerMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Only log GET requests to term detail endpoints
-		if c.Request.Method == http.MethodGet && strings.HasPrefix(c.Request.URL.Path, "/api/v1/terms/") {
-			termIDStr := c.Param("id")
-			if termIDStr != "" {
-				termID, err := uuid.Parse(termIDStr)
-				if err == nil {
-					// Extract cluster from query params if available
-					cluster := c.Query("cluster")
-					var clusterPtr *string
-					if cluster != "" {
-						clusterPtr = &cluster
-					}
-					// Log asynchronously to avoid blocking the request
-					go logTermView(context.Background(), termID, clusterPtr)
-				}
-			}
-		}
+// termDetailRoute is the route whose successful responses count as term views
+const termDetailRoute = "/api/v1/terms/:id"
 
+// UsageLoggerMiddleware logs term views for analytics.
+// It runs after the handler so the matched route and response status are known,
+// and hands the event to the recorder's buffer instead of writing it inline.
+func UsageLoggerMiddleware(recorder *service.UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		c.Next()
-	}
-}
 
-func logTermView(ctx context.Context, termID uuid.UUID, cluster *string) {
-	// For POC, use default user ID (no auth yet)
-	defaultUserID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		// Only log successful GET requests to the term detail endpoint
+		if c.Request.Method != http.MethodGet || c.FullPath() != termDetailRoute || c.Writer.Status() != http.StatusOK {
+			return
+		}
 
-	query := `
-		INSERT INTO term_usage_logs (term_id, cluster, user_id, action, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
-	`
+		termID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return
+		}
 
-	_, err := database.DB.Exec(ctx, query, termID, cluster, defaultUserID, "viewed")
-	if err != nil {
-		// Log error but don't fail the request
-		// In production, use proper logging
-		_ = err
+		// Extract cluster from query params if available
+		var clusterPtr *string
+		if cluster := c.Query("cluster"); cluster != "" {
+			clusterPtr = &cluster
+		}
+
+		userID := GetUserID(c)
+		recorder.Record(models.TermUsageLog{
+			TermID:     termID,
+			Cluster:    clusterPtr,
+			Department: GetUserDepartment(c),
+			UserID:     &userID,
+			Action:     "viewed",
+			CreatedAt:  time.Now(),
+		})
 	}
 }
-
@@ -2,12 +2,20 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const UserDepartmentKey = "user_department"
 
-// UserContextMiddleware extracts user department from request headers or query params
-// and stores it in the Gin context for handlers to access
+const UserIDKey = "user_id"
+
+const UserClusterKey = "user_cluster"
+
+// DefaultUserID is used when no user is supplied (for POC, no auth yet)
+var DefaultUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// UserContextMiddleware extracts user department, cluster and user ID from request headers or query params
+// and stores them in the Gin context for handlers to access
 func UserContextMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Try to get department from header first
@@ -20,8 +28,51 @@ func UserContextMiddleware() gin.HandlerFunc {
 		
 		// Store in context (empty string means show all terms)
 		c.Set(UserDepartmentKey, department)
+
+		// Optional cluster of the user, used to personalise search ranking
+		cluster := c.GetHeader("X-User-Cluster")
+		if cluster == "" {
+			cluster = c.Query("user_cluster")
+		}
+		c.Set(UserClusterKey, cluster)
+
+		// Fall back to the default user when no valid user ID is supplied
+		userID := DefaultUserID
+		if parsed, err := uuid.Parse(c.GetHeader("X-User-ID")); err == nil {
+			userID = parsed
+		}
+		c.Set(UserIDKey, userID)
 		
 		c.Next()
 	}
 }
 
+// GetUserID returns the user ID stored by UserContextMiddleware
+func GetUserID(c *gin.Context) uuid.UUID {
+	if value, exists := c.Get(UserIDKey); exists {
+		if userID, ok := value.(uuid.UUID); ok {
+			return userID
+		}
+	}
+	return DefaultUserID
+}
+
+// GetUserDepartment returns the user department stored by UserContextMiddleware, or nil if none was supplied
+func GetUserDepartment(c *gin.Context) *string {
+	if value, exists := c.Get(UserDepartmentKey); exists {
+		if department, ok := value.(string); ok && department != "" {
+			return &department
+		}
+	}
+	return nil
+}
+
+// GetUserCluster returns the user cluster stored by UserContextMiddleware, or nil if none was supplied
+func GetUserCluster(c *gin.Context) *string {
+	if value, exists := c.Get(UserClusterKey); exists {
+		if cluster, ok := value.(string); ok && cluster != "" {
+			return &cluster
+		}
+	}
+	return nil
+}
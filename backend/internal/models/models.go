@@ -42,6 +42,7 @@ type Term struct {
 	ComplianceFrameworks []string `json:"compliance_frameworks,omitempty"` // e.g., 'BCBS 239', 'GDPR', 'FICA'
 	VisibilityType     *string   `json:"visibility_type,omitempty"` // 'public' or 'department_restricted'
 	AllowedDepartments []string  `json:"allowed_departments,omitempty"` // Which departments can see this term
+	Status             string    `json:"status,omitempty"` // draft, approved, certified, deprecated
 	CreatedBy          *uuid.UUID `json:"created_by,omitempty"`
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
@@ -49,6 +50,7 @@ type Term struct {
 	Contexts           []TermContext `json:"contexts,omitempty"`
 	Examples           []TermExample `json:"examples,omitempty"`
 	Relationships      []TermRelationship `json:"relationships,omitempty"`
+	Aliases            []TermAlias `json:"aliases,omitempty"`
 }
 
 // TermContext represents a contextual variation of a term
@@ -89,6 +91,16 @@ type TermRelationship struct {
 	RelatedTerm     *Term     `json:"related_term,omitempty"`
 }
 
+// TermAlias represents an alternative name for a term (acronym, abbreviation, alternative spelling)
+type TermAlias struct {
+	ID        uuid.UUID  `json:"id"`
+	TermID    uuid.UUID  `json:"term_id"`
+	Alias     string     `json:"alias"`
+	AliasType string     `json:"alias_type"` // acronym, abbreviation, alternative_spelling, other
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
 // TermProposal represents a proposed change to a term
 type TermProposal struct {
 	ID           uuid.UUID              `json:"id"`
@@ -126,6 +138,7 @@ type CreateTermRequest struct {
 	CodeName            *string  `json:"code_name,omitempty"`
 	Tags                []string `json:"tags,omitempty"`
 	ComplianceFrameworks []string `json:"compliance_frameworks,omitempty"`
+	Status              *string  `json:"status,omitempty" binding:"omitempty,oneof=draft approved certified deprecated"`
 }
 
 // UpdateTermRequest represents a request to update a term
@@ -136,6 +149,7 @@ type UpdateTermRequest struct {
 	CodeName            *string  `json:"code_name,omitempty"`
 	Tags                []string `json:"tags,omitempty"`
 	ComplianceFrameworks []string `json:"compliance_frameworks,omitempty"`
+	Status              *string  `json:"status,omitempty" binding:"omitempty,oneof=draft approved certified deprecated"`
 	ChangeReason        *string  `json:"change_reason,omitempty"`
 }
 
@@ -162,17 +176,169 @@ type CreateRelationshipRequest struct {
 	RelationshipType string   `json:"relationship_type" binding:"required"`
 }
 
+// CreateAliasRequest represents a request to add an alias to a term
+type CreateAliasRequest struct {
+	Alias     string `json:"alias" binding:"required"`
+	AliasType string `json:"alias_type" binding:"omitempty,oneof=acronym abbreviation alternative_spelling other"`
+}
+
 // SearchRequest represents a search query
 type SearchRequest struct {
 	Query    string   `json:"query" form:"q"`
-	Category *string  `json:"category" form:"category"`
-	Cluster  *string  `json:"cluster" form:"cluster"`
-	System   *string  `json:"system" form:"system"`
+	// Multi-select filters: values within a filter are ORed, filters are ANDed
+	Categories           []string `json:"categories" form:"category"`
+	Clusters             []string `json:"clusters" form:"cluster"`
+	Systems              []string `json:"systems" form:"system"`
+	Products             []string `json:"products" form:"product"`
 	Tags     []string `json:"tags" form:"tags"`
+	ComplianceFrameworks []string `json:"compliance_frameworks" form:"compliance_frameworks"`
+	Visibility           []string `json:"visibility" form:"visibility"`
+	HighlightPre  string `json:"highlight_pre" form:"highlight_pre"`   // marker before highlighted words, default <mark>
+	HighlightPost string `json:"highlight_post" form:"highlight_post"` // marker after highlighted words, default </mark>
+	SkipFacets    bool   `json:"skip_facets" form:"skip_facets"`
+	Expr          *SearchExpr `json:"-"` // parsed advanced query; nil for plain text queries
+	Personalized  bool              `json:"personalized" form:"personalized"` // blend popularity, affinity and status into the ranking
+	Debug         bool              `json:"debug" form:"debug"`               // return the score breakdown per hit
+	RankWeights   SearchRankWeights `json:"-"`
+	UserCluster    *string `json:"-"` // caller's cluster and department, for affinity
+	UserDepartment *string `json:"-"`
 	Limit    int      `json:"limit" form:"limit"`
 	Offset   int      `json:"offset" form:"offset"`
 }
 
+// SearchExpr is a node of a parsed advanced search query
+type SearchExpr struct {
+	Op       string        `json:"op"` // and, or, not, text, phrase, field
+	Children []*SearchExpr `json:"children,omitempty"`
+	Field    string        `json:"field,omitempty"`    // category, cluster, system, product, framework, tag, code, updated, created
+	Operator string        `json:"operator,omitempty"` // =, >, >=, <, <= (dates)
+	Value    string        `json:"value,omitempty"`
+}
+
+// SearchHit represents a term returned by search with its relevance score and why it matched
+type SearchHit struct {
+	Term
+	Score          float64         `json:"score"`
+	Highlights     SearchHighlight `json:"highlights"`
+	MatchFields    []string        `json:"match_fields"`              // term, code_name, alias, base_definition, context_definition, examples, business_rules, tags, synonym
+	ContextMatches []ContextMatch  `json:"context_matches,omitempty"` // contexts whose definition matched
+	MatchedVia     []QueryExpansion `json:"matched_via,omitempty"`    // aliases or synonyms that led to this hit
+	ScoreBreakdown *ScoreBreakdown  `json:"score_breakdown,omitempty"` // debug mode only
+}
+
+// SearchRankWeights tunes the personalized ranking blend. Usage counts enter as ln(1 + uses).
+type SearchRankWeights struct {
+	Popularity float64 `json:"popularity"` // usage by everyone
+	Affinity   float64 `json:"affinity"`   // usage from the caller's cluster or department
+	Certified  float64 `json:"certified"`
+	Approved   float64 `json:"approved"`
+	Deprecated float64 `json:"deprecated"` // subtracted from the score
+}
+
+// ScoreBreakdown explains a hit's score: Score = Text + Popularity + Affinity + Status
+type ScoreBreakdown struct {
+	Text            float64 `json:"text"`
+	Popularity      float64 `json:"popularity"`
+	Affinity        float64 `json:"affinity"`
+	Status          float64 `json:"status"`
+	UsageCount      int64   `json:"usage_count"`
+	LocalUsageCount int64   `json:"local_usage_count"`
+}
+
+// AnnotateRequest represents a document to find glossary terms in
+type AnnotateRequest struct {
+	Text    string  `json:"text" binding:"required"`
+	Cluster *string `json:"cluster,omitempty"` // resolve definitions for this cluster
+}
+
+// TermMention is an occurrence of a term, alias or code name in a document. Offsets are in
+// characters (Unicode code points), End exclusive.
+type TermMention struct {
+	Start              int         `json:"start"`
+	End                int         `json:"end"`
+	Text               string      `json:"text"` // as written in the document
+	TermID             uuid.UUID   `json:"term_id"`
+	Term               string      `json:"term"`
+	MatchedVia         string      `json:"matched_via"`                    // term, code_name, acronym, alias
+	AlternativeTermIDs []uuid.UUID `json:"alternative_term_ids,omitempty"` // other terms sharing the matched text
+}
+
+// AnnotatedTerm is a term mentioned in a document with its definition for the requested cluster
+type AnnotatedTerm struct {
+	TermID     uuid.UUID `json:"term_id"`
+	Term       string    `json:"term"`
+	CodeName   *string   `json:"code_name,omitempty"`
+	Definition string    `json:"definition"`
+	Cluster    *string   `json:"cluster,omitempty"` // set when the definition is cluster-specific
+	Mentions   int       `json:"mentions"`
+}
+
+// AnnotationResult lists the mentions in a document and the terms they refer to
+type AnnotationResult struct {
+	Mentions []TermMention   `json:"mentions"`
+	Terms    []AnnotatedTerm `json:"terms"`
+}
+
+// AnnotateHTMLRequest represents an HTML document or fragment to add term tooltips to
+type AnnotateHTMLRequest struct {
+	HTML      string  `json:"html" binding:"required"`
+	Cluster   *string `json:"cluster,omitempty"`
+	FirstOnly bool    `json:"first_only"` // wrap only the first mention of each term
+}
+
+// HTMLAnnotationResult is a sanitized HTML document with the mentioned terms wrapped
+type HTMLAnnotationResult struct {
+	HTML     string          `json:"html"`
+	Mentions int             `json:"mentions"`
+	Terms    []AnnotatedTerm `json:"terms"`
+}
+
+// QueryExpansion records why a term matched a search without containing the query itself,
+// e.g. {"type": "synonym", "value": "Net Interest Margin"}
+type QueryExpansion struct {
+	Type  string `json:"type"` // alias, synonym
+	Value string `json:"value"`
+}
+
+// SearchHighlight holds the term and a definition snippet with query words highlighted
+type SearchHighlight struct {
+	Term           string `json:"term"`
+	BaseDefinition string `json:"base_definition"`
+}
+
+// ContextMatch represents a context definition that matched a search, with a highlighted snippet
+type ContextMatch struct {
+	ContextID uuid.UUID `json:"context_id"`
+	Cluster   *string   `json:"cluster,omitempty"`
+	System    *string   `json:"system,omitempty"`
+	Product   *string   `json:"product,omitempty"`
+	Snippet   string    `json:"snippet"`
+}
+
+// SearchResults represents a page of search hits
+type SearchResults struct {
+	Hits        []SearchHit `json:"data"`
+	Total       int         `json:"total"`
+	Fuzzy       bool        `json:"fuzzy"`                 // true when typo-tolerant matching was used
+	Suggestions []string    `json:"did_you_mean,omitempty"` // similar term names when few results were found
+	Facets      map[string][]FacetValue `json:"facets,omitempty"`
+}
+
+// FacetValue represents a facet value and the number of matching terms that have it
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Suggestion represents a typeahead match for a search prefix
+type Suggestion struct {
+	TermID     uuid.UUID `json:"term_id"`
+	Term       string    `json:"term"`
+	Text       string    `json:"text"` // the matched text: term name, code name, acronym, alias or synonym
+	Kind       string    `json:"kind"` // term, code_name, acronym, alias, synonym
+	Popularity int64     `json:"popularity"`
+}
+
 // CreateProposalRequest represents a request to create a proposal
 type CreateProposalRequest struct {
 	TermID       *uuid.UUID              `json:"term_id,omitempty"`
@@ -181,6 +347,155 @@ type CreateProposalRequest struct {
 	Reason       *string                 `json:"reason,omitempty"`
 }
 
+// DiscoveryDocument is an uploaded document split into text segments (a file, or CSV cells)
+type DiscoveryDocument struct {
+	Name     string
+	Segments []string
+}
+
+// TermCandidate is a possible glossary term found in documents that is not yet a term, alias or code name
+type TermCandidate struct {
+	Text          string             `json:"text"`
+	Kind          string             `json:"kind"` // phrase, acronym, identifier
+	Frequency     int                `json:"frequency"`
+	DocumentCount int                `json:"document_count"`
+	Score         float64            `json:"score"`
+	Examples      []CandidateExample `json:"examples"`
+}
+
+// CandidateExample is a sentence a candidate term was found in
+type CandidateExample struct {
+	Document string `json:"document"`
+	Sentence string `json:"sentence"`
+}
+
+// DiscoveryResult lists ranked candidate terms found in a set of documents
+type DiscoveryResult struct {
+	Candidates []TermCandidate `json:"candidates"`
+	Documents  int             `json:"documents"`
+	Sentences  int             `json:"sentences"`
+}
+
+// ProposeCandidateRequest represents a request to turn a candidate term into a create proposal
+type ProposeCandidateRequest struct {
+	Text           string   `json:"text" binding:"required"`
+	Kind           string   `json:"kind" binding:"omitempty,oneof=phrase acronym identifier"`
+	BaseDefinition *string  `json:"base_definition,omitempty"`
+	Category       *string  `json:"category,omitempty"`
+	Examples       []string `json:"examples,omitempty"` // example sentences from the documents
+	Reason         *string  `json:"reason,omitempty"`
+}
+
+// TermRecommendation is a term suggested as related to another, with the evidence behind it
+type TermRecommendation struct {
+	TermID         uuid.UUID `json:"term_id"`
+	Term           string    `json:"term"`
+	Category       *string   `json:"category,omitempty"`
+	Score          float64   `json:"score"`
+	ContentScore   float64   `json:"content_score"` // cosine similarity of the TF-IDF definition vectors
+	CoViewScore    float64   `json:"co_view_score"` // shared viewers relative to the viewers of both terms
+	CoViewers      int64     `json:"co_viewers"`
+	SharedKeywords []string  `json:"shared_keywords,omitempty"`
+	Reasons        []string  `json:"reasons"` // similar_definition, co_viewed
+}
+
+// CoViewedTerm is a term viewed by some of the same users as another term
+type CoViewedTerm struct {
+	TermID         uuid.UUID `json:"term_id"`
+	CoViewers      int64     `json:"co_viewers"`
+	TermViewers    int64     `json:"term_viewers"`    // distinct viewers of the original term
+	RelatedViewers int64     `json:"related_viewers"` // distinct viewers of this term
+}
+
+// AcceptRecommendationRequest represents a request to turn a recommendation into a relationship
+type AcceptRecommendationRequest struct {
+	RelatedTermID    uuid.UUID `json:"related_term_id" binding:"required"`
+	RelationshipType string    `json:"relationship_type" binding:"omitempty,oneof=synonym antonym related see_also parent child"` // default related
+}
+
+// ImportRowError is a problem with one row of an import sheet; row 1 is the header
+type ImportRowError struct {
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Subject string `json:"subject,omitempty"` // source resource of the row, for imports converted from RDF
+	Message string `json:"message"`
+}
+
+// ImportResult summarises a bulk import. When there are errors nothing was written.
+type ImportResult struct {
+	DryRun               bool             `json:"dry_run"`
+	TermsCreated         int              `json:"terms_created"`
+	TermsUpdated         int              `json:"terms_updated"`
+	ContextsCreated      int              `json:"contexts_created"`
+	ContextsUpdated      int              `json:"contexts_updated"`
+	ExamplesCreated      int              `json:"examples_created"`
+	ExamplesSkipped      int              `json:"examples_skipped"`      // identical example already exists
+	RelationshipsCreated int              `json:"relationships_created"`
+	RelationshipsSkipped int              `json:"relationships_skipped"` // relationship already exists
+	AliasesCreated       int              `json:"aliases_created"`
+	AliasesSkipped       int              `json:"aliases_skipped"` // term already has the alias
+	Errors               []ImportRowError `json:"errors"`
+}
+
+// GlossaryImportPlan is a validated import with every term reference resolved to an ID
+type GlossaryImportPlan struct {
+	Terms         []ImportTerm         `json:"terms"`
+	Contexts      []ImportContext      `json:"contexts"`
+	Examples      []ImportExample      `json:"examples"`
+	Relationships []ImportRelationship `json:"relationships"`
+	Aliases       []ImportAlias        `json:"aliases"`
+}
+
+// ImportTerm creates a term, or updates an existing one when Exists is set. Nil fields leave the
+// existing value unchanged.
+type ImportTerm struct {
+	ID                   uuid.UUID `json:"id"`
+	Exists               bool      `json:"exists"`
+	Term                 string    `json:"term"`
+	BaseDefinition       *string   `json:"base_definition,omitempty"`
+	Category             *string   `json:"category,omitempty"`
+	CodeName             *string   `json:"code_name,omitempty"`
+	Tags                 []string  `json:"tags,omitempty"`
+	ComplianceFrameworks []string  `json:"compliance_frameworks,omitempty"`
+	Status               *string   `json:"status,omitempty"`
+	VisibilityType       *string   `json:"visibility_type,omitempty"`
+	AllowedDepartments   []string  `json:"allowed_departments,omitempty"`
+}
+
+// ImportContext creates a context, or updates the contexts of the term with the same cluster,
+// system and product
+type ImportContext struct {
+	TermID             uuid.UUID `json:"term_id"`
+	Cluster            *string   `json:"cluster,omitempty"`
+	System             *string   `json:"system,omitempty"`
+	Product            *string   `json:"product,omitempty"`
+	ContextDefinition  string    `json:"context_definition"`
+	BusinessRules      []string  `json:"business_rules,omitempty"`
+	ComplianceRequired *bool     `json:"compliance_required,omitempty"`
+}
+
+// ImportExample adds an example unless the term already has one with the same text
+type ImportExample struct {
+	TermID      uuid.UUID `json:"term_id"`
+	ExampleText string    `json:"example_text"`
+	Source      *string   `json:"source,omitempty"`
+}
+
+// ImportRelationship adds a relationship unless it already exists
+type ImportRelationship struct {
+	TermID           uuid.UUID `json:"term_id"`
+	RelatedTermID    uuid.UUID `json:"related_term_id"`
+	RelationshipType string    `json:"relationship_type"`
+}
+
+// ImportAlias adds an alias unless the term already has it
+type ImportAlias struct {
+	TermID    uuid.UUID `json:"term_id"`
+	Alias     string    `json:"alias"`
+	AliasType string    `json:"alias_type"`
+}
+
 // CreateFlagRequest represents a request to create a flag
 type CreateFlagRequest struct {
 	FlagType    string `json:"flag_type" binding:"required"`
@@ -202,7 +517,10 @@ type GapAnalysis struct {
 	ID              uuid.UUID `json:"id"`
 	TermID          uuid.UUID `json:"term_id"`
 	GapType         string    `json:"gap_type"` // missing_context, conflicting_definition, outdated
+	Dimension       string    `json:"dimension"` // cluster, system, product
 	AffectedClusters []string  `json:"affected_clusters"`
+	AffectedSystems  []string  `json:"affected_systems,omitempty"`
+	AffectedProducts []string  `json:"affected_products,omitempty"`
 	Severity        string    `json:"severity"` // high, medium, low
 	Description     *string   `json:"description,omitempty"`
 	DetectedAt      time.Time `json:"detected_at"`
@@ -211,16 +529,202 @@ type GapAnalysis struct {
 	Term            *Term     `json:"term,omitempty"`
 }
 
+// GapDetectionStats reports the throughput of a gap detection run
+type GapDetectionStats struct {
+	TermsScanned    int64   `json:"terms_scanned"`
+	ContextsScanned int64   `json:"contexts_scanned"`
+	Batches         int64   `json:"batches"`
+	GapsDetected    int64   `json:"gaps_detected"`
+	GapsWritten     int64   `json:"gaps_written"`
+	Workers         int     `json:"workers"`
+	BatchSize       int     `json:"batch_size"`
+	DurationMs      int64   `json:"duration_ms"`
+	TermsPerSecond  float64 `json:"terms_per_second"`
+}
+
+// DefinitionMatrix represents a term × system (or product) grid of context definitions within a cluster
+type DefinitionMatrix struct {
+	Cluster   string                `json:"cluster"`
+	Dimension string                `json:"dimension"` // system, product
+	Columns   []string              `json:"columns"`
+	Rows      []DefinitionMatrixRow `json:"rows"`
+}
+
+// DefinitionMatrixRow represents one term in a definition matrix
+type DefinitionMatrixRow struct {
+	TermID      uuid.UUID                       `json:"term_id"`
+	Term        string                          `json:"term"`
+	Cells       map[string]DefinitionMatrixCell `json:"cells"` // keyed by column
+	Conflicting []string                        `json:"conflicting,omitempty"`
+}
+
+// DefinitionMatrixCell represents the definition of a term for a single system or product
+type DefinitionMatrixCell struct {
+	ContextID  *uuid.UUID `json:"context_id,omitempty"`
+	Definition *string    `json:"definition,omitempty"`
+	Status     string     `json:"status"` // defined, conflicting, missing
+}
+
 // TermUsageLog represents a usage log entry for analytics
 type TermUsageLog struct {
 	ID        uuid.UUID `json:"id"`
 	TermID    uuid.UUID `json:"term_id"`
 	Cluster   *string   `json:"cluster,omitempty"`
+	Department *string  `json:"department,omitempty"`
 	UserID    *uuid.UUID `json:"user_id,omitempty"`
 	Action    string    `json:"action"` // viewed, searched, referenced
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// SearchLog represents a logged search query
+type SearchLog struct {
+	ID            uuid.UUID              `json:"id"`
+	Query         string                 `json:"query"`
+	Filters       map[string]interface{} `json:"filters,omitempty"`
+	ResultCount   int                    `json:"result_count"`
+	ClickedTermID *uuid.UUID             `json:"clicked_term_id,omitempty"`
+	ClickedAt     *time.Time             `json:"clicked_at,omitempty"`
+	UserID        *uuid.UUID             `json:"user_id,omitempty"`
+	Department    *string                `json:"department,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// SearchClickRequest represents a click-through from a search result to a term
+type SearchClickRequest struct {
+	TermID  uuid.UUID `json:"term_id" binding:"required"`
+	Cluster *string   `json:"cluster,omitempty"`
+}
+
+// ZeroResultQuery represents a search query that returned no results, hinting at a missing term
+type ZeroResultQuery struct {
+	Query          string    `json:"query"`
+	SearchCount    int64     `json:"search_count"`
+	UniqueUsers    int64     `json:"unique_users"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+// TermReference represents an external system (report, dashboard, data pipeline) that references a term
+type TermReference struct {
+	ID            uuid.UUID `json:"id"`
+	TermID        uuid.UUID `json:"term_id"`
+	System        string    `json:"system"`
+	ReferenceType string    `json:"reference_type"` // report, dashboard, pipeline, application, other
+	ReferenceName string    `json:"reference_name"`
+	URL           *string   `json:"url,omitempty"`
+	Cluster       *string   `json:"cluster,omitempty"`
+	FirstSeenAt   time.Time `json:"first_seen_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+}
+
+// CreateTermReferenceRequest represents a declaration that an external system references a term
+type CreateTermReferenceRequest struct {
+	TermID        uuid.UUID `json:"term_id" binding:"required"`
+	System        string    `json:"system" binding:"required"`
+	ReferenceType *string   `json:"reference_type,omitempty"`
+	ReferenceName *string   `json:"reference_name,omitempty"`
+	URL           *string   `json:"url,omitempty"`
+	Cluster       *string   `json:"cluster,omitempty"`
+}
+
+// CreateTermReferencesRequest represents a batch of term reference declarations
+type CreateTermReferencesRequest struct {
+	References []CreateTermReferenceRequest `json:"references" binding:"required,min=1,dive"`
+}
+
+// TermReferenceReport represents a term with the external systems that reference it
+type TermReferenceReport struct {
+	TermID     uuid.UUID       `json:"term_id"`
+	Term       string          `json:"term"`
+	Systems    []string        `json:"systems"`
+	References []TermReference `json:"references"`
+}
+
+// UsagePipelineStats reports the state of the buffered usage logging pipeline
+type UsagePipelineStats struct {
+	Enqueued   int64 `json:"enqueued"`
+	Dropped    int64 `json:"dropped"`
+	Written    int64 `json:"written"`
+	Failed     int64 `json:"failed"`
+	Buffered   int   `json:"buffered"`
+	BufferSize int   `json:"buffer_size"`
+
+	SearchesDropped  int64 `json:"searches_dropped"`
+	SearchesWritten  int64 `json:"searches_written"`
+	SearchesFailed   int64 `json:"searches_failed"`
+	SearchesBuffered int   `json:"searches_buffered"`
+}
+
+// UsageRetentionResult reports the outcome of a usage rollup and retention run
+type UsageRetentionResult struct {
+	RollupRows        int64     `json:"rollup_rows"`
+	PseudonymisedRows int64     `json:"pseudonymised_rows"`
+	PurgedRows        int64     `json:"purged_rows"`
+	SearchLogsPseudonymised int64 `json:"search_logs_pseudonymised"`
+	SearchLogsPurged  int64     `json:"search_logs_purged"`
+	RetentionDays     int       `json:"retention_days"`
+	PseudonymiseDays  int       `json:"pseudonymise_after_days"`
+	RanAt             time.Time `json:"ran_at"`
+	DurationMs        int64     `json:"duration_ms"`
+}
+
+// UsageFilter restricts usage analytics to a period and optional action, cluster and department
+type UsageFilter struct {
+	From           time.Time
+	To             time.Time
+	Action         *string
+	Cluster        *string
+	Department     *string
+	UserDepartment *string // Caller's department, used for term visibility
+}
+
+// TermUsageCount represents how often a term was used in a period
+type TermUsageCount struct {
+	TermID   uuid.UUID `json:"term_id"`
+	Term     string    `json:"term"`
+	Category *string   `json:"category,omitempty"`
+	Count    int64     `json:"count"`
+}
+
+// UsageBreakdown represents usage grouped by a cluster or department
+type UsageBreakdown struct {
+	Key         string `json:"key"`
+	Count       int64  `json:"count"`
+	UniqueTerms int64  `json:"unique_terms"`
+}
+
+// UsageHeatmapCell represents usage for one department and cluster (or category) pair
+type UsageHeatmapCell struct {
+	Department string `json:"department"`
+	Column     string `json:"column"`
+	Count      int64  `json:"count"`
+}
+
+// UsageTimeBucket represents usage within one time bucket of a series
+type UsageTimeBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int64     `json:"count"`
+}
+
+// TrendingTerm represents a term whose recent usage rate rose compared to a baseline period
+type TrendingTerm struct {
+	TermID        uuid.UUID `json:"term_id"`
+	Term          string    `json:"term"`
+	RecentCount   int64     `json:"recent_count"`
+	BaselineCount int64     `json:"baseline_count"`
+	RecentRate    float64   `json:"recent_rate"`   // per day
+	BaselineRate  float64   `json:"baseline_rate"` // per day
+	Growth        float64   `json:"growth"`        // relative change of the daily rate
+}
+
+// UnusedTerm represents a term that has not been viewed recently
+type UnusedTerm struct {
+	TermID       uuid.UUID  `json:"term_id"`
+	Term         string     `json:"term"`
+	Category     *string    `json:"category,omitempty"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
 // CreateClusterRequest represents a request to create a cluster
 type CreateClusterRequest struct {
 	Name        string  `json:"name" binding:"required"`
@@ -236,12 +740,65 @@ type ResolveGapRequest struct {
 type Notification struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
-	Type      string    `json:"type"` // 'proposal', 'flag', 'gap_resolved', 'new_term'
+	Type      string    `json:"type"` // 'proposal', 'flag', 'gap_resolved', 'new_term', 'saved_search'
 	Message   string    `json:"message"`
+	ReferenceID *uuid.UUID `json:"reference_id,omitempty"` // e.g. the saved search whose results changed
 	Read      bool      `json:"read"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// SavedSearch represents a user's saved query and filters
+type SavedSearch struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	Name          string             `json:"name"`
+	Query         string             `json:"query"`
+	Filters       SavedSearchFilters `json:"filters"`
+	Notify        bool               `json:"notify"`
+	Department    *string            `json:"department,omitempty"` // department of the owner when the search was saved, for term visibility
+	LastResultIDs []uuid.UUID        `json:"-"`
+	LastCheckedAt *time.Time         `json:"last_checked_at,omitempty"`
+	LastRunAt     *time.Time         `json:"last_run_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// SavedSearchFilters holds the multi-select search filters of a saved search
+type SavedSearchFilters struct {
+	Categories           []string `json:"categories,omitempty"`
+	Clusters             []string `json:"clusters,omitempty"`
+	Systems              []string `json:"systems,omitempty"`
+	Products             []string `json:"products,omitempty"`
+	Tags                 []string `json:"tags,omitempty"`
+	ComplianceFrameworks []string `json:"compliance_frameworks,omitempty"`
+	Visibility           []string `json:"visibility,omitempty"`
+}
+
+// CreateSavedSearchRequest represents a request to save a search
+type CreateSavedSearchRequest struct {
+	Name    string             `json:"name" binding:"required,max=255"`
+	Query   string             `json:"query" binding:"required"`
+	Filters SavedSearchFilters `json:"filters"`
+	Notify  bool               `json:"notify"`
+}
+
+// UpdateSavedSearchRequest represents a request to update a saved search
+type UpdateSavedSearchRequest struct {
+	Name    *string             `json:"name,omitempty" binding:"omitempty,max=255"`
+	Query   *string             `json:"query,omitempty"`
+	Filters *SavedSearchFilters `json:"filters,omitempty"`
+	Notify  *bool               `json:"notify,omitempty"`
+}
+
+// SavedSearchAlertResult summarises a run of the saved search alert check
+type SavedSearchAlertResult struct {
+	Checked       int       `json:"checked"`
+	Notifications int       `json:"notifications"`
+	Failed        int       `json:"failed"`
+	RanAt         time.Time `json:"ran_at"`
+	DurationMs    int64     `json:"duration_ms"`
+}
+
 // TermVersion represents a version of a term for audit trail
 type TermVersion struct {
 	ID           uuid.UUID              `json:"id"`
@@ -263,3 +820,44 @@ type OnboardingPath struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+
+// BackupManifest describes a backup archive: its format, the schema version of the data and
+// each entity file with its record count and SHA-256 checksum
+type BackupManifest struct {
+	Format        string         `json:"format"`
+	SchemaVersion int            `json:"schema_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Entities      []BackupEntity `json:"entities"`
+}
+
+// BackupEntity is a JSON Lines file of a backup archive
+type BackupEntity struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Count  int    `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupData holds the records of a backup. Terms are stored without their nested contexts,
+// examples, relationships and aliases, which have their own lists.
+type BackupData struct {
+	Terms           []Term
+	Contexts        []TermContext
+	Examples        []TermExample
+	Relationships   []TermRelationship
+	Aliases         []TermAlias
+	Versions        []TermVersion
+	Proposals       []TermProposal
+	Flags           []TermFlag
+	Clusters        []Cluster
+	OnboardingPaths []OnboardingPath
+	Branding        []BrandingConfig
+}
+
+// BackupRestoreResult counts the records a restore wrote, by entity name
+type BackupRestoreResult struct {
+	DryRun        bool           `json:"dry_run"`
+	SchemaVersion int            `json:"schema_version"`
+	Restored      map[string]int `json:"restored"`
+	Skipped       map[string]int `json:"skipped"` // clusters that already exist by name
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type BackupRepository struct{}
+
+func NewBackupRepository() *BackupRepository {
+	return &BackupRepository{}
+}
+
+// LoadBackup reads every record a backup covers from one snapshot of the database
+func (r *BackupRepository) LoadBackup(ctx context.Context) (*models.BackupData, error) {
+	tx, err := database.DB.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	data := &models.BackupData{}
+
+	// each load scans the rows of one query into its list
+	load := func(name, query string, scan func(rows pgx.Rows) error) error {
+		rows, err := tx.Query(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				return fmt.Errorf("failed to scan %s: %w", name, err)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+		return nil
+	}
+
+	err = load("terms", `
+		SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, status, created_by, created_at, updated_at, updated_by
+		FROM terms
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var t models.Term
+		err := rows.Scan(&t.ID, &t.Term, &t.BaseDefinition, &t.Category, &t.CodeName, &t.Tags, &t.ComplianceFrameworks, &t.VisibilityType, &t.AllowedDepartments, &t.Status, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt, &t.UpdatedBy)
+		data.Terms = append(data.Terms, t)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("contexts", `
+		SELECT id, term_id, cluster, system, product, context_definition, business_rules, COALESCE(compliance_required, FALSE), created_by, created_at, updated_at, updated_by
+		FROM term_contexts
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var tc models.TermContext
+		err := rows.Scan(&tc.ID, &tc.TermID, &tc.Cluster, &tc.System, &tc.Product, &tc.ContextDefinition, &tc.BusinessRules, &tc.ComplianceRequired, &tc.CreatedBy, &tc.CreatedAt, &tc.UpdatedAt, &tc.UpdatedBy)
+		data.Contexts = append(data.Contexts, tc)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("examples", `
+		SELECT id, term_id, context_id, example_text, source, created_by, created_at
+		FROM term_examples
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var ex models.TermExample
+		err := rows.Scan(&ex.ID, &ex.TermID, &ex.ContextID, &ex.ExampleText, &ex.Source, &ex.CreatedBy, &ex.CreatedAt)
+		data.Examples = append(data.Examples, ex)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("relationships", `
+		SELECT id, term_id, related_term_id, relationship_type, created_by, created_at
+		FROM term_relationships
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var rel models.TermRelationship
+		err := rows.Scan(&rel.ID, &rel.TermID, &rel.RelatedTermID, &rel.RelationshipType, &rel.CreatedBy, &rel.CreatedAt)
+		data.Relationships = append(data.Relationships, rel)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("aliases", `
+		SELECT id, term_id, alias, alias_type, created_by, created_at
+		FROM term_aliases
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var alias models.TermAlias
+		err := rows.Scan(&alias.ID, &alias.TermID, &alias.Alias, &alias.AliasType, &alias.CreatedBy, &alias.CreatedAt)
+		data.Aliases = append(data.Aliases, alias)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("versions", `
+		SELECT id, term_id, version_number, term_data, changed_by, change_reason, created_at
+		FROM term_versions
+		ORDER BY term_id, version_number, id
+	`, func(rows pgx.Rows) error {
+		var v models.TermVersion
+		err := rows.Scan(&v.ID, &v.TermID, &v.VersionNumber, &v.TermData, &v.ChangedBy, &v.ChangeReason, &v.CreatedAt)
+		data.Versions = append(data.Versions, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("proposals", `
+		SELECT id, term_id, proposal_type, proposed_data, reason, COALESCE(status, 'pending'), proposed_by, reviewed_by, reviewed_at, created_at, updated_at
+		FROM term_proposals
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var p models.TermProposal
+		err := rows.Scan(&p.ID, &p.TermID, &p.ProposalType, &p.ProposedData, &p.Reason, &p.Status, &p.ProposedBy, &p.ReviewedBy, &p.ReviewedAt, &p.CreatedAt, &p.UpdatedAt)
+		data.Proposals = append(data.Proposals, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("flags", `
+		SELECT id, term_id, flag_type, description, COALESCE(status, 'open'), flagged_by, resolved_by, resolved_at, created_at, updated_at
+		FROM term_flags
+		ORDER BY created_at, id
+	`, func(rows pgx.Rows) error {
+		var f models.TermFlag
+		err := rows.Scan(&f.ID, &f.TermID, &f.FlagType, &f.Description, &f.Status, &f.FlaggedBy, &f.ResolvedBy, &f.ResolvedAt, &f.CreatedAt, &f.UpdatedAt)
+		data.Flags = append(data.Flags, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("clusters", `
+		SELECT id, name, description, owner_id, created_at, updated_at
+		FROM clusters
+		ORDER BY name
+	`, func(rows pgx.Rows) error {
+		var cluster models.Cluster
+		err := rows.Scan(&cluster.ID, &cluster.Name, &cluster.Description, &cluster.OwnerID, &cluster.CreatedAt, &cluster.UpdatedAt)
+		data.Clusters = append(data.Clusters, cluster)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("onboarding paths", `
+		SELECT id, role, cluster, term_ids, order_index, created_at
+		FROM onboarding_paths
+		ORDER BY role, order_index, id
+	`, func(rows pgx.Rows) error {
+		var path models.OnboardingPath
+		err := rows.Scan(&path.ID, &path.Role, &path.Cluster, &path.TermIDs, &path.OrderIndex, &path.CreatedAt)
+		data.OnboardingPaths = append(data.OnboardingPaths, path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load("branding", `
+		SELECT id, organization_id, light_color, pastel_color, primary_color, dark_color, created_at, updated_at
+		FROM branding_config
+		ORDER BY organization_id
+	`, func(rows pgx.Rows) error {
+		var config models.BrandingConfig
+		err := rows.Scan(&config.ID, &config.OrganizationID, &config.LightColor, &config.PastelColor, &config.PrimaryColor, &config.DarkColor, &config.CreatedAt, &config.UpdatedAt)
+		data.Branding = append(data.Branding, config)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CountTerms returns the number of terms in the glossary
+func (r *BackupRepository) CountTerms(ctx context.Context) (int, error) {
+	var count int
+	if err := database.DB.QueryRow(ctx, `SELECT COUNT(*) FROM terms`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count terms: %w", err)
+	}
+	return count, nil
+}
+
+// RestoreBackup writes the records of a backup in one transaction, keeping their IDs and
+// timestamps, so IDs must already be remapped to fresh ones. References to users that do not
+// exist in this database are cleared. Clusters that already exist by name are kept; branding
+// replaces the configuration of its organization. Without commit the transaction is rolled
+// back after all statements ran.
+func (r *BackupRepository) RestoreBackup(ctx context.Context, data *models.BackupData, commit bool) (*models.BackupRestoreResult, error) {
+	result := &models.BackupRestoreResult{DryRun: !commit, Restored: map[string]int{}, Skipped: map[string]int{}}
+
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// user IDs go through this expression so that unknown users become NULL
+	const user = "(SELECT id FROM users WHERE id = $%d)"
+	userArg := func(pos int) string { return fmt.Sprintf(user, pos) }
+
+	for _, cluster := range data.Clusters {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO clusters (id, name, description, owner_id, created_at, updated_at)
+			VALUES ($1, $2, $3, `+userArg(4)+`, $5, $6)
+			ON CONFLICT (name) DO NOTHING
+		`, cluster.ID, cluster.Name, cluster.Description, cluster.OwnerID, cluster.CreatedAt, cluster.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore cluster %q: %w", cluster.Name, err)
+		}
+		if tag.RowsAffected() == 0 {
+			result.Skipped["clusters"]++
+			continue
+		}
+		result.Restored["clusters"]++
+	}
+
+	for _, config := range data.Branding {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO branding_config (id, organization_id, light_color, pastel_color, primary_color, dark_color, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (organization_id) DO UPDATE SET
+				light_color = EXCLUDED.light_color,
+				pastel_color = EXCLUDED.pastel_color,
+				primary_color = EXCLUDED.primary_color,
+				dark_color = EXCLUDED.dark_color,
+				updated_at = EXCLUDED.updated_at
+		`, config.ID, config.OrganizationID, config.LightColor, config.PastelColor, config.PrimaryColor, config.DarkColor, config.CreatedAt, config.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore branding of %q: %w", config.OrganizationID, err)
+		}
+		result.Restored["branding"]++
+	}
+
+	for _, t := range data.Terms {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO terms (id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, status, created_by, created_at, updated_at, updated_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, 'public'), $9, COALESCE(NULLIF($10, ''), 'approved'), `+userArg(11)+`, $12, $13, `+userArg(14)+`)
+		`, t.ID, t.Term, t.BaseDefinition, t.Category, t.CodeName, t.Tags, t.ComplianceFrameworks, t.VisibilityType, t.AllowedDepartments, t.Status, t.CreatedBy, t.CreatedAt, t.UpdatedAt, t.UpdatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore term %q: %w", t.Term, err)
+		}
+		result.Restored["terms"]++
+	}
+
+	for _, tc := range data.Contexts {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_contexts (id, term_id, cluster, system, product, context_definition, business_rules, compliance_required, created_by, created_at, updated_at, updated_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, `+userArg(9)+`, $10, $11, `+userArg(12)+`)
+		`, tc.ID, tc.TermID, tc.Cluster, tc.System, tc.Product, tc.ContextDefinition, tc.BusinessRules, tc.ComplianceRequired, tc.CreatedBy, tc.CreatedAt, tc.UpdatedAt, tc.UpdatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore context: %w", err)
+		}
+		result.Restored["contexts"]++
+	}
+
+	for _, ex := range data.Examples {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_examples (id, term_id, context_id, example_text, source, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, `+userArg(6)+`, $7)
+		`, ex.ID, ex.TermID, ex.ContextID, ex.ExampleText, ex.Source, ex.CreatedBy, ex.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore example: %w", err)
+		}
+		result.Restored["examples"]++
+	}
+
+	for _, rel := range data.Relationships {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_relationships (id, term_id, related_term_id, relationship_type, created_by, created_at)
+			VALUES ($1, $2, $3, $4, `+userArg(5)+`, $6)
+		`, rel.ID, rel.TermID, rel.RelatedTermID, rel.RelationshipType, rel.CreatedBy, rel.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore relationship: %w", err)
+		}
+		result.Restored["relationships"]++
+	}
+
+	for _, alias := range data.Aliases {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_aliases (id, term_id, alias, alias_type, created_by, created_at)
+			VALUES ($1, $2, $3, $4, `+userArg(5)+`, $6)
+		`, alias.ID, alias.TermID, alias.Alias, alias.AliasType, alias.CreatedBy, alias.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore alias %q: %w", alias.Alias, err)
+		}
+		result.Restored["aliases"]++
+	}
+
+	for _, v := range data.Versions {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_versions (id, term_id, version_number, term_data, changed_by, change_reason, created_at)
+			VALUES ($1, $2, $3, $4, `+userArg(5)+`, $6, $7)
+		`, v.ID, v.TermID, v.VersionNumber, v.TermData, v.ChangedBy, v.ChangeReason, v.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore version: %w", err)
+		}
+		result.Restored["versions"]++
+	}
+
+	for _, p := range data.Proposals {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_proposals (id, term_id, proposal_type, proposed_data, reason, status, proposed_by, reviewed_by, reviewed_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, `+userArg(7)+`, `+userArg(8)+`, $9, $10, $11)
+		`, p.ID, p.TermID, p.ProposalType, p.ProposedData, p.Reason, p.Status, p.ProposedBy, p.ReviewedBy, p.ReviewedAt, p.CreatedAt, p.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore proposal: %w", err)
+		}
+		result.Restored["proposals"]++
+	}
+
+	for _, f := range data.Flags {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_flags (id, term_id, flag_type, description, status, flagged_by, resolved_by, resolved_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, `+userArg(6)+`, `+userArg(7)+`, $8, $9, $10)
+		`, f.ID, f.TermID, f.FlagType, f.Description, f.Status, f.FlaggedBy, f.ResolvedBy, f.ResolvedAt, f.CreatedAt, f.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore flag: %w", err)
+		}
+		result.Restored["flags"]++
+	}
+
+	for _, path := range data.OnboardingPaths {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO onboarding_paths (id, role, cluster, term_ids, order_index, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, path.ID, path.Role, path.Cluster, path.TermIDs, path.OrderIndex, path.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore onboarding path %q: %w", path.Role, err)
+		}
+		result.Restored["onboarding_paths"]++
+	}
+
+	if commit {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	return result, nil
+}
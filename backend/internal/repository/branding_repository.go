@@ -6,7 +6,6 @@ import (
 
 	"clarityconnect/internal/models"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"clarityconnect/pkg/database"
 )
@@ -101,7 +101,7 @@ func (r *ComplianceRepository) GetComplianceGaps(ctx context.Context, framework
 
 	if framework != nil {
 		query = `
-			SELECT ga.id, ga.term_id, ga.gap_type, ga.affected_clusters, ga.severity, ga.description, ga.detected_at, ga.resolved_at, ga.resolved_by
+			SELECT ga.id, ga.term_id, ga.gap_type, COALESCE(ga.dimension, 'cluster'), ga.affected_clusters, ga.affected_systems, ga.affected_products, ga.severity, ga.description, ga.detected_at, ga.resolved_at, ga.resolved_by
 			FROM gap_analyses ga
 			JOIN terms t ON ga.term_id = t.id
 			WHERE $1 = ANY(t.compliance_frameworks) AND ga.resolved_at IS NULL
@@ -110,7 +110,7 @@ func (r *ComplianceRepository) GetComplianceGaps(ctx context.Context, framework
 		args = []interface{}{*framework}
 	} else {
 		query = `
-			SELECT ga.id, ga.term_id, ga.gap_type, ga.affected_clusters, ga.severity, ga.description, ga.detected_at, ga.resolved_at, ga.resolved_by
+			SELECT ga.id, ga.term_id, ga.gap_type, COALESCE(ga.dimension, 'cluster'), ga.affected_clusters, ga.affected_systems, ga.affected_products, ga.severity, ga.description, ga.detected_at, ga.resolved_at, ga.resolved_by
 			FROM gap_analyses ga
 			JOIN terms t ON ga.term_id = t.id
 			WHERE t.compliance_frameworks IS NOT NULL AND array_length(t.compliance_frameworks, 1) > 0 AND ga.resolved_at IS NULL
@@ -129,7 +129,7 @@ func (r *ComplianceRepository) GetComplianceGaps(ctx context.Context, framework
 	for rows.Next() {
 		var gap models.GapAnalysis
 		err := rows.Scan(
-			&gap.ID, &gap.TermID, &gap.GapType, &gap.AffectedClusters, &gap.Severity, &gap.Description, &gap.DetectedAt, &gap.ResolvedAt, &gap.ResolvedBy,
+			&gap.ID, &gap.TermID, &gap.GapType, &gap.Dimension, &gap.AffectedClusters, &gap.AffectedSystems, &gap.AffectedProducts, &gap.Severity, &gap.Description, &gap.DetectedAt, &gap.ResolvedAt, &gap.ResolvedBy,
 		)
 		if err != nil {
 			if err == pgx.ErrNoRows {
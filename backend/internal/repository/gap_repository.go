@@ -22,14 +22,18 @@ func NewGapRepository() *GapRepository {
 // CreateGap creates a new gap analysis entry
 func (r *GapRepository) CreateGap(ctx context.Context, gap *models.GapAnalysis) error {
 	query := `
-		INSERT INTO gap_analyses (id, term_id, gap_type, affected_clusters, severity, description, detected_at, resolved_at, resolved_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO gap_analyses (id, term_id, gap_type, dimension, affected_clusters, affected_systems, affected_products, severity, description, detected_at, resolved_at, resolved_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
 
+	if gap.Dimension == "" {
+		gap.Dimension = "cluster"
+	}
+
 	err := database.DB.QueryRow(ctx, query,
-		gap.ID, gap.TermID, gap.GapType, gap.AffectedClusters, gap.Severity, gap.Description,
-		gap.DetectedAt, gap.ResolvedAt, gap.ResolvedBy,
+		gap.ID, gap.TermID, gap.GapType, gap.Dimension, gap.AffectedClusters, gap.AffectedSystems, gap.AffectedProducts,
+		gap.Severity, gap.Description, gap.DetectedAt, gap.ResolvedAt, gap.ResolvedBy,
 	).Scan(&gap.ID)
 
 	if err != nil {
@@ -39,18 +43,63 @@ func (r *GapRepository) CreateGap(ctx context.Context, gap *models.GapAnalysis)
 	return nil
 }
 
+// ReplaceOpenGaps replaces the unresolved gaps of the given terms with the gaps of a new detection
+// run, written with COPY, in one transaction. It returns the number of rows written.
+func (r *GapRepository) ReplaceOpenGaps(ctx context.Context, termIDs []uuid.UUID, gaps []models.GapAnalysis) (int64, error) {
+	if len(termIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM gap_analyses WHERE resolved_at IS NULL AND term_id = ANY($1)`, termIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete open gaps: %w", err)
+	}
+
+	rows := make([][]interface{}, len(gaps))
+	for i, gap := range gaps {
+		dimension := gap.Dimension
+		if dimension == "" {
+			dimension = "cluster"
+		}
+		rows[i] = []interface{}{
+			gap.ID, gap.TermID, gap.GapType, dimension, gap.AffectedClusters, gap.AffectedSystems, gap.AffectedProducts,
+			gap.Severity, gap.Description, gap.DetectedAt, gap.ResolvedAt, gap.ResolvedBy,
+		}
+	}
+
+	count, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"gap_analyses"},
+		[]string{"id", "term_id", "gap_type", "dimension", "affected_clusters", "affected_systems", "affected_products", "severity", "description", "detected_at", "resolved_at", "resolved_by"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy gaps: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return count, nil
+}
+
 // GetGapByID retrieves a gap by ID
 func (r *GapRepository) GetGapByID(ctx context.Context, id uuid.UUID) (*models.GapAnalysis, error) {
 	gap := &models.GapAnalysis{}
 
 	query := `
-		SELECT id, term_id, gap_type, affected_clusters, severity, description, detected_at, resolved_at, resolved_by
+		SELECT id, term_id, gap_type, COALESCE(dimension, 'cluster'), affected_clusters, affected_systems, affected_products, severity, description, detected_at, resolved_at, resolved_by
 		FROM gap_analyses
 		WHERE id = $1
 	`
 
 	err := database.DB.QueryRow(ctx, query, id).Scan(
-		&gap.ID, &gap.TermID, &gap.GapType, &gap.AffectedClusters, &gap.Severity,
+		&gap.ID, &gap.TermID, &gap.GapType, &gap.Dimension, &gap.AffectedClusters, &gap.AffectedSystems, &gap.AffectedProducts, &gap.Severity,
 		&gap.Description, &gap.DetectedAt, &gap.ResolvedAt, &gap.ResolvedBy,
 	)
 
@@ -65,7 +114,7 @@ func (r *GapRepository) GetGapByID(ctx context.Context, id uuid.UUID) (*models.G
 }
 
 // ListGaps retrieves gaps with filters
-func (r *GapRepository) ListGaps(ctx context.Context, limit, offset int, gapType *string, cluster *string, severity *string, resolved *bool) ([]models.GapAnalysis, int, error) {
+func (r *GapRepository) ListGaps(ctx context.Context, limit, offset int, gapType *string, cluster *string, severity *string, resolved *bool, dimension *string) ([]models.GapAnalysis, int, error) {
 	var gaps []models.GapAnalysis
 	var total int
 
@@ -99,6 +148,12 @@ func (r *GapRepository) ListGaps(ctx context.Context, limit, offset int, gapType
 		argPos++
 	}
 
+	if dimension != nil {
+		baseQuery += fmt.Sprintf(" AND COALESCE(dimension, 'cluster') = $%d", argPos)
+		args = append(args, *dimension)
+		argPos++
+	}
+
 	// Get total count
 	countQuery := "SELECT COUNT(*) " + baseQuery
 	err := database.DB.QueryRow(ctx, countQuery, args...).Scan(&total)
@@ -108,9 +163,9 @@ func (r *GapRepository) ListGaps(ctx context.Context, limit, offset int, gapType
 
 	// Get gaps
 	query := `
-		SELECT id, term_id, gap_type, affected_clusters, severity, description, detected_at, resolved_at, resolved_by
+		SELECT id, term_id, gap_type, COALESCE(dimension, 'cluster'), affected_clusters, affected_systems, affected_products, severity, description, detected_at, resolved_at, resolved_by
 		` + baseQuery + `
-		ORDER BY detected_at DESC
+		ORDER BY detected_at DESC, id
 		LIMIT $` + fmt.Sprintf("%d", argPos) + ` OFFSET $` + fmt.Sprintf("%d", argPos+1)
 
 	args = append(args, limit, offset)
@@ -123,7 +178,7 @@ func (r *GapRepository) ListGaps(ctx context.Context, limit, offset int, gapType
 	for rows.Next() {
 		var gap models.GapAnalysis
 		err := rows.Scan(
-			&gap.ID, &gap.TermID, &gap.GapType, &gap.AffectedClusters, &gap.Severity,
+			&gap.ID, &gap.TermID, &gap.GapType, &gap.Dimension, &gap.AffectedClusters, &gap.AffectedSystems, &gap.AffectedProducts, &gap.Severity,
 			&gap.Description, &gap.DetectedAt, &gap.ResolvedAt, &gap.ResolvedBy,
 		)
 		if err != nil {
@@ -272,41 +327,117 @@ func (r *GapRepository) GetTermsByCluster(ctx context.Context, clusterName strin
 	return terms, nil
 }
 
+// contextDimensionColumns maps comparison dimensions to term_contexts columns
+var contextDimensionColumns = map[string]string{
+	"cluster": "cluster",
+	"system":  "system",
+	"product": "product",
+}
+
 // GetTermClusterComparison retrieves a term with all its contexts grouped by cluster
 func (r *GapRepository) GetTermClusterComparison(ctx context.Context, termID uuid.UUID) (map[string][]models.TermContext, error) {
-	query := `
-		SELECT id, term_id, cluster, system, product, context_definition, created_by, created_at, updated_at, updated_by
+	return r.GetTermContextComparison(ctx, termID, "cluster", nil)
+}
+
+// GetTermContextComparison retrieves a term's contexts grouped by cluster, system or product,
+// optionally restricted to a single cluster
+func (r *GapRepository) GetTermContextComparison(ctx context.Context, termID uuid.UUID, dimension string, cluster *string) (map[string][]models.TermContext, error) {
+	column, ok := contextDimensionColumns[dimension]
+	if !ok {
+		return nil, fmt.Errorf("invalid dimension")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, term_id, cluster, system, product, context_definition, business_rules, created_by, created_at, updated_at, updated_by
 		FROM term_contexts
-		WHERE term_id = $1 AND cluster IS NOT NULL
-		ORDER BY cluster, created_at DESC
-	`
+		WHERE term_id = $1 AND %s IS NOT NULL
+	`, column)
+	args := []interface{}{termID}
 
-	rows, err := database.DB.Query(ctx, query, termID)
+	if cluster != nil {
+		query += " AND cluster = $2"
+		args = append(args, *cluster)
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s, created_at DESC", column)
+
+	rows, err := database.DB.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get term cluster comparison: %w", err)
+		return nil, fmt.Errorf("failed to get term %s comparison: %w", dimension, err)
 	}
 	defer rows.Close()
 
 	comparison := make(map[string][]models.TermContext)
 	for rows.Next() {
-		var ctx models.TermContext
+		var tc models.TermContext
 		err := rows.Scan(
-			&ctx.ID, &ctx.TermID, &ctx.Cluster, &ctx.System, &ctx.Product,
-			&ctx.ContextDefinition, &ctx.CreatedBy, &ctx.CreatedAt, &ctx.UpdatedAt, &ctx.UpdatedBy,
+			&tc.ID, &tc.TermID, &tc.Cluster, &tc.System, &tc.Product,
+			&tc.ContextDefinition, &tc.BusinessRules, &tc.CreatedBy, &tc.CreatedAt, &tc.UpdatedAt, &tc.UpdatedBy,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan context: %w", err)
 		}
 
-		if ctx.Cluster != nil {
-			clusterName := *ctx.Cluster
-			comparison[clusterName] = append(comparison[clusterName], ctx)
+		if key := ContextDimensionValue(tc, dimension); key != nil {
+			comparison[*key] = append(comparison[*key], tc)
 		}
 	}
 
 	return comparison, nil
 }
 
+// GetClusterContexts retrieves all terms with contexts in a cluster, with only that cluster's contexts loaded
+func (r *GapRepository) GetClusterContexts(ctx context.Context, clusterName string) ([]models.Term, error) {
+	query := `
+		SELECT t.id, t.term, tc.id, tc.term_id, tc.cluster, tc.system, tc.product, tc.context_definition, tc.business_rules, tc.created_at, tc.updated_at
+		FROM terms t
+		INNER JOIN term_contexts tc ON t.id = tc.term_id
+		WHERE tc.cluster = $1
+		ORDER BY t.term ASC, t.id, tc.created_at DESC
+	`
+
+	rows, err := database.DB.Query(ctx, query, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster contexts: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []models.Term
+	for rows.Next() {
+		var termID uuid.UUID
+		var termName string
+		var tc models.TermContext
+		err := rows.Scan(
+			&termID, &termName, &tc.ID, &tc.TermID, &tc.Cluster, &tc.System, &tc.Product,
+			&tc.ContextDefinition, &tc.BusinessRules, &tc.CreatedAt, &tc.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan context: %w", err)
+		}
+
+		if len(terms) == 0 || terms[len(terms)-1].ID != termID {
+			terms = append(terms, models.Term{ID: termID, Term: termName})
+		}
+		last := &terms[len(terms)-1]
+		last.Contexts = append(last.Contexts, tc)
+	}
+
+	return terms, nil
+}
+
+// ContextDimensionValue returns the cluster, system or product of a context
+func ContextDimensionValue(tc models.TermContext, dimension string) *string {
+	switch dimension {
+	case "cluster":
+		return tc.Cluster
+	case "system":
+		return tc.System
+	case "product":
+		return tc.Product
+	}
+	return nil
+}
+
 // GetAllClustersFromContexts retrieves all unique cluster names from term_contexts
 func (r *GapRepository) GetAllClustersFromContexts(ctx context.Context) ([]string, error) {
 	query := `
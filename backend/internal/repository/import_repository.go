@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+// importChangeReason is recorded on the version snapshots of terms updated by an import
+const importChangeReason = "Bulk import"
+
+type ImportRepository struct{}
+
+func NewImportRepository() *ImportRepository {
+	return &ImportRepository{}
+}
+
+// ApplyGlossaryImport writes a validated import plan in one transaction. Terms are written first,
+// so contexts, examples, relationships and aliases can refer to new terms. Updated terms get a
+// version snapshot of their previous state. Without commit the transaction is rolled back after all
+// statements ran, which checks the plan against the database without changing it. A userID that
+// matches no user is recorded as no user.
+func (r *ImportRepository) ApplyGlossaryImport(ctx context.Context, plan *models.GlossaryImportPlan, userID *uuid.UUID, commit bool) (*models.ImportResult, error) {
+	result := &models.ImportResult{DryRun: !commit, Errors: []models.ImportRowError{}}
+	now := time.Now()
+
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, term := range plan.Terms {
+		if !term.Exists {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO terms (id, term, base_definition, category, code_name, tags, compliance_frameworks, status, visibility_type, allowed_departments, created_by, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, 'approved'), COALESCE($9, 'public'), $10, (SELECT id FROM users WHERE id = $11), $12, $12)
+			`, term.ID, term.Term, term.BaseDefinition, term.Category, term.CodeName, term.Tags, term.ComplianceFrameworks, term.Status, term.VisibilityType, term.AllowedDepartments, userID, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import term %q: %w", term.Term, err)
+			}
+			result.TermsCreated++
+			continue
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO term_versions (id, term_id, version_number, term_data, changed_by, change_reason, created_at)
+			SELECT $1, t.id,
+			       COALESCE((SELECT MAX(version_number) FROM term_versions WHERE term_id = t.id), 0) + 1,
+			       jsonb_strip_nulls(jsonb_build_object(
+			           'id', t.id, 'term', t.term, 'base_definition', t.base_definition, 'category', t.category,
+			           'code_name', t.code_name, 'tags', t.tags, 'compliance_frameworks', t.compliance_frameworks,
+			           'visibility_type', t.visibility_type, 'allowed_departments', t.allowed_departments,
+			           'status', t.status, 'created_by', t.created_by, 'created_at', t.created_at,
+			           'updated_at', t.updated_at, 'updated_by', t.updated_by
+			       )),
+			       (SELECT id FROM users WHERE id = $2), $3, CURRENT_TIMESTAMP
+			FROM terms t
+			WHERE t.id = $4
+		`, uuid.New(), userID, importChangeReason, term.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create version of term %q: %w", term.Term, err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE terms
+			SET term = $2,
+			    base_definition = COALESCE($3, base_definition),
+			    category = COALESCE($4, category),
+			    code_name = COALESCE($5, code_name),
+			    tags = COALESCE($6, tags),
+			    compliance_frameworks = COALESCE($7, compliance_frameworks),
+			    status = COALESCE($8, status),
+			    visibility_type = COALESCE($9, visibility_type),
+			    allowed_departments = COALESCE($10, allowed_departments),
+			    updated_at = $11,
+			    updated_by = (SELECT id FROM users WHERE id = $12)
+			WHERE id = $1
+		`, term.ID, term.Term, term.BaseDefinition, term.Category, term.CodeName, term.Tags, term.ComplianceFrameworks, term.Status, term.VisibilityType, term.AllowedDepartments, now, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import term %q: %w", term.Term, err)
+		}
+		result.TermsUpdated++
+	}
+
+	for _, tc := range plan.Contexts {
+		tag, err := tx.Exec(ctx, `
+			UPDATE term_contexts
+			SET context_definition = $5,
+			    business_rules = COALESCE($6, business_rules),
+			    compliance_required = COALESCE($7, compliance_required),
+			    updated_at = $8,
+			    updated_by = (SELECT id FROM users WHERE id = $9)
+			WHERE term_id = $1 AND cluster IS NOT DISTINCT FROM $2 AND system IS NOT DISTINCT FROM $3 AND product IS NOT DISTINCT FROM $4
+		`, tc.TermID, tc.Cluster, tc.System, tc.Product, tc.ContextDefinition, tc.BusinessRules, tc.ComplianceRequired, now, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import context: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			result.ContextsUpdated++
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO term_contexts (id, term_id, cluster, system, product, context_definition, business_rules, compliance_required, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, FALSE), (SELECT id FROM users WHERE id = $9), $10, $10)
+		`, uuid.New(), tc.TermID, tc.Cluster, tc.System, tc.Product, tc.ContextDefinition, tc.BusinessRules, tc.ComplianceRequired, userID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import context: %w", err)
+		}
+		result.ContextsCreated++
+	}
+
+	for _, ex := range plan.Examples {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO term_examples (id, term_id, example_text, source, created_by, created_at)
+			SELECT $1::uuid, $2::uuid, $3::text, $4::text, (SELECT id FROM users WHERE id = $5::uuid), $6::timestamp
+			WHERE NOT EXISTS (SELECT 1 FROM term_examples WHERE term_id = $2::uuid AND example_text = $3::text)
+		`, uuid.New(), ex.TermID, ex.ExampleText, ex.Source, userID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import example: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			result.ExamplesCreated++
+		} else {
+			result.ExamplesSkipped++
+		}
+	}
+
+	for _, rel := range plan.Relationships {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO term_relationships (id, term_id, related_term_id, relationship_type, created_by, created_at)
+			VALUES ($1, $2, $3, $4, (SELECT id FROM users WHERE id = $5), $6)
+			ON CONFLICT (term_id, related_term_id, relationship_type) DO NOTHING
+		`, uuid.New(), rel.TermID, rel.RelatedTermID, rel.RelationshipType, userID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import relationship: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			result.RelationshipsCreated++
+		} else {
+			result.RelationshipsSkipped++
+		}
+	}
+
+	for _, alias := range plan.Aliases {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO term_aliases (id, term_id, alias, alias_type, created_by, created_at)
+			VALUES ($1, $2, $3, $4, (SELECT id FROM users WHERE id = $5), $6)
+			ON CONFLICT (term_id, lower(alias)) DO NOTHING
+		`, uuid.New(), alias.TermID, alias.Alias, alias.AliasType, userID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import alias: %w", err)
+		}
+		if tag.RowsAffected() > 0 {
+			result.AliasesCreated++
+		} else {
+			result.AliasesSkipped++
+		}
+	}
+
+	if commit {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository struct{}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{}
+}
+
+// ListNotifications retrieves the notifications of a user, newest first
+func (r *NotificationRepository) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int, error) {
+	where := "WHERE user_id = $1"
+	if unreadOnly {
+		where += " AND read = FALSE"
+	}
+
+	var total int
+	if err := database.DB.QueryRow(ctx, "SELECT COUNT(*) FROM notifications "+where, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	rows, err := database.DB.Query(ctx, `
+		SELECT id, user_id, type, message, reference_id, read, created_at
+		FROM notifications `+where+`
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Message, &n.ReferenceID, &n.Read, &n.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, nil
+}
+
+// MarkNotificationRead marks a notification of a user as read
+func (r *NotificationRepository) MarkNotificationRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	result, err := database.DB.Exec(ctx, `UPDATE notifications SET read = TRUE WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type SavedSearchRepository struct{}
+
+func NewSavedSearchRepository() *SavedSearchRepository {
+	return &SavedSearchRepository{}
+}
+
+const savedSearchColumns = `id, user_id, name, query, filters, notify, department, last_result_ids, last_checked_at, last_run_at, created_at, updated_at`
+
+func scanSavedSearch(row pgx.Row) (*models.SavedSearch, error) {
+	search := &models.SavedSearch{}
+	var filtersJSON []byte
+	err := row.Scan(
+		&search.ID, &search.UserID, &search.Name, &search.Query, &filtersJSON, &search.Notify, &search.Department, &search.LastResultIDs, &search.LastCheckedAt, &search.LastRunAt, &search.CreatedAt, &search.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filtersJSON, &search.Filters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved search filters: %w", err)
+	}
+	return search, nil
+}
+
+// CreateSavedSearch saves a search for a user. Names are unique per user, ignoring case. The
+// department decides which terms the alert check can see. The user must exist, as saved
+// searches are deleted with their owner.
+func (r *SavedSearchRepository) CreateSavedSearch(ctx context.Context, userID uuid.UUID, department *string, req models.CreateSavedSearchRequest) (*models.SavedSearch, error) {
+	filtersJSON, err := json.Marshal(req.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal saved search filters: %w", err)
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO saved_searches (id, user_id, name, query, filters, notify, department, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, lower(name)) DO NOTHING
+		RETURNING ` + savedSearchColumns
+
+	search, err := scanSavedSearch(database.DB.QueryRow(ctx, query,
+		uuid.New(), userID, strings.TrimSpace(req.Name), req.Query, filtersJSON, req.Notify, department, now, now,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("saved search name already exists")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return search, nil
+}
+
+// GetSavedSearch retrieves a saved search of a user
+func (r *SavedSearchRepository) GetSavedSearch(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE id = $1 AND user_id = $2`
+
+	search, err := scanSavedSearch(database.DB.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("saved search not found")
+		}
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return search, nil
+}
+
+// ListSavedSearches retrieves the saved searches of a user, by name
+func (r *SavedSearchRepository) ListSavedSearches(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE user_id = $1 ORDER BY lower(name)`
+	return r.querySavedSearches(ctx, query, userID)
+}
+
+// ListNotifyingSavedSearches retrieves all saved searches with change alerts enabled
+func (r *SavedSearchRepository) ListNotifyingSavedSearches(ctx context.Context) ([]models.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE notify = TRUE ORDER BY created_at`
+	return r.querySavedSearches(ctx, query)
+}
+
+func (r *SavedSearchRepository) querySavedSearches(ctx context.Context, query string, args ...interface{}) ([]models.SavedSearch, error) {
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, *search)
+	}
+
+	return searches, nil
+}
+
+// UpdateSavedSearch updates a saved search of a user. Changing the query or filters also records
+// the user's current department and resets the alert baseline, so the next check records the new
+// results without notifying.
+func (r *SavedSearchRepository) UpdateSavedSearch(ctx context.Context, id uuid.UUID, userID uuid.UUID, department *string, req models.UpdateSavedSearchRequest) (*models.SavedSearch, error) {
+	updates := []string{}
+	args := []interface{}{}
+	argPos := 1
+
+	if req.Name != nil {
+		updates = append(updates, fmt.Sprintf("name = $%d", argPos))
+		args = append(args, strings.TrimSpace(*req.Name))
+		argPos++
+	}
+
+	if req.Query != nil {
+		updates = append(updates, fmt.Sprintf("query = $%d", argPos))
+		args = append(args, *req.Query)
+		argPos++
+	}
+
+	if req.Filters != nil {
+		filtersJSON, err := json.Marshal(req.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal saved search filters: %w", err)
+		}
+		updates = append(updates, fmt.Sprintf("filters = $%d", argPos))
+		args = append(args, filtersJSON)
+		argPos++
+	}
+
+	if req.Query != nil || req.Filters != nil {
+		updates = append(updates, fmt.Sprintf("department = $%d", argPos), "last_result_ids = '{}'", "last_checked_at = NULL")
+		args = append(args, department)
+		argPos++
+	}
+
+	if req.Notify != nil {
+		updates = append(updates, fmt.Sprintf("notify = $%d", argPos))
+		args = append(args, *req.Notify)
+		argPos++
+	}
+
+	if len(updates) == 0 {
+		return r.GetSavedSearch(ctx, id, userID)
+	}
+
+	updates = append(updates, fmt.Sprintf("updated_at = $%d", argPos))
+	args = append(args, time.Now())
+	argPos++
+
+	args = append(args, id, userID)
+	query := fmt.Sprintf(`
+		UPDATE saved_searches
+		SET %s
+		WHERE id = $%d AND user_id = $%d
+		RETURNING `+savedSearchColumns, strings.Join(updates, ", "), argPos, argPos+1)
+
+	search, err := scanSavedSearch(database.DB.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("saved search not found")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("saved search name already exists")
+		}
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+
+	return search, nil
+}
+
+// DeleteSavedSearch deletes a saved search of a user
+func (r *SavedSearchRepository) DeleteSavedSearch(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	result, err := database.DB.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("saved search not found")
+	}
+	return nil
+}
+
+// MarkSavedSearchRun records that a user reran a saved search
+func (r *SavedSearchRepository) MarkSavedSearchRun(ctx context.Context, id uuid.UUID, runAt time.Time) error {
+	_, err := database.DB.Exec(ctx, `UPDATE saved_searches SET last_run_at = $1 WHERE id = $2`, runAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to record saved search run: %w", err)
+	}
+	return nil
+}
+
+// RecordSavedSearchResults stores the result set of an alert check and, when results changed,
+// the notification about it, in one transaction
+func (r *SavedSearchRepository) RecordSavedSearchResults(ctx context.Context, id uuid.UUID, termIDs []uuid.UUID, checkedAt time.Time, notification *models.Notification) error {
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE saved_searches SET last_result_ids = $1, last_checked_at = $2 WHERE id = $3
+	`, termIDs, checkedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to record saved search results: %w", err)
+	}
+
+	if notification != nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO notifications (id, user_id, type, message, reference_id, read, created_at)
+			VALUES ($1, $2, $3, $4, $5, FALSE, $6)
+		`, notification.ID, notification.UserID, notification.Type, notification.Message, notification.ReferenceID, notification.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
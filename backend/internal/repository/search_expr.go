@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"clarityconnect/internal/models"
+)
+
+// exprContextFilters marks where searchQuery.where puts the context filters in a compiled query.
+// It is an SQL comment, so a query without context filters needs no replacement.
+const exprContextFilters = "/* context filters */"
+
+// compileExpr compiles a parsed advanced query into a boolean condition over terms t. Every value
+// is bound as a parameter. The tsqueries of text that is not negated are collected in positive
+// for ranking and highlighting. When searching contexts, text also matches the definitions of
+// contexts passing the context filters, so field filters and negations still apply to such hits.
+func (q *searchQuery) compileExpr(e *models.SearchExpr, negated bool, positive *[]string) string {
+	switch e.Op {
+	case "and", "or":
+		parts := make([]string, 0, len(e.Children))
+		for _, child := range e.Children {
+			parts = append(parts, q.compileExpr(child, negated, positive))
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(e.Op)+" ") + ")"
+	case "not":
+		// NULL columns (e.g. no category) count as not matching, so negation includes them
+		return "(NOT COALESCE(" + q.compileExpr(e.Children[0], !negated, positive) + ", false))"
+	case "text", "phrase":
+		function := "plainto_tsquery"
+		if e.Op == "phrase" {
+			function = "phraseto_tsquery"
+		}
+		tsquery := fmt.Sprintf("%s('english', %s)", function, q.bind(e.Value))
+		if !negated {
+			*positive = append(*positive, tsquery)
+		}
+		match := q.document() + " @@ " + tsquery
+		if q.withContexts {
+			match += ` OR EXISTS (
+				SELECT 1 FROM term_contexts tc
+				WHERE tc.term_id = t.id` + exprContextFilters + `
+				AND ` + contextTextMatch("tc.", tsquery) + `
+			)`
+		}
+		return "(" + match + ")"
+	case "field":
+		return q.compileField(e)
+	}
+	return "false"
+}
+
+// compileField compiles a field:value filter. Values are case-insensitive; a trailing * matches a prefix.
+func (q *searchQuery) compileField(e *models.SearchExpr) string {
+	if e.Field == "updated" || e.Field == "created" {
+		column := "t." + e.Field + "_at"
+		day, _ := time.Parse("2006-01-02", e.Value)
+		param := q.bind(day) + "::date"
+		switch e.Operator {
+		case ">":
+			return fmt.Sprintf("(%s >= %s + 1)", column, param)
+		case ">=":
+			return fmt.Sprintf("(%s >= %s)", column, param)
+		case "<":
+			return fmt.Sprintf("(%s < %s)", column, param)
+		case "<=":
+			return fmt.Sprintf("(%s < %s + 1)", column, param)
+		default:
+			return fmt.Sprintf("(%[1]s >= %[2]s AND %[1]s < %[2]s + 1)", column, param)
+		}
+	}
+
+	operator := "="
+	value := strings.ToLower(e.Value)
+	if strings.HasSuffix(value, "*") {
+		operator = "LIKE"
+		value = escapeLike(strings.TrimSuffix(value, "*")) + "%"
+	}
+	param := q.bind(value)
+
+	switch e.Field {
+	case "category":
+		return fmt.Sprintf("(lower(t.category) %s %s)", operator, param)
+	case "code":
+		return fmt.Sprintf("(lower(t.code_name) %s %s)", operator, param)
+	case "tag":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(t.tags) AS v WHERE lower(v) %s %s)", operator, param)
+	case "framework":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(t.compliance_frameworks) AS v WHERE lower(v) %s %s)", operator, param)
+	case "cluster", "system", "product":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM term_contexts fx WHERE fx.term_id = t.id AND lower(fx.%s) %s %s)", e.Field, operator, param)
+	}
+	return "false"
+}
+
+// bind adds a query argument and returns its placeholder
+func (q *searchQuery) bind(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// searchExprText returns the free text of an advanced query that is not negated, used for
+// trigram ranking and search logs
+func searchExprText(e *models.SearchExpr) string {
+	switch e.Op {
+	case "text", "phrase":
+		return e.Value
+	case "and", "or":
+		parts := []string{}
+		for _, child := range e.Children {
+			if text := searchExprText(child); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return ""
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"html"
+	"strings"
 	"time"
+	"unicode"
 
 	"clarityconnect/internal/models"
 
@@ -35,15 +37,15 @@ func (r *TermRepository) CreateTerm(ctx context.Context, req models.CreateTermRe
 	}
 
 	query := `
-		INSERT INTO terms (id, term, base_definition, category, code_name, tags, compliance_frameworks, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, term, base_definition, category, code_name, tags, compliance_frameworks, created_by, created_at, updated_at, updated_by
+		INSERT INTO terms (id, term, base_definition, category, code_name, tags, compliance_frameworks, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, 'approved'), $9, $10, $11)
+		RETURNING id, term, base_definition, category, code_name, tags, compliance_frameworks, status, created_by, created_at, updated_at, updated_by
 	`
 
 	err := database.DB.QueryRow(ctx, query,
-		term.ID, term.Term, term.BaseDefinition, term.Category, term.CodeName, term.Tags, term.ComplianceFrameworks, term.CreatedBy, term.CreatedAt, term.UpdatedAt,
+		term.ID, term.Term, term.BaseDefinition, term.Category, term.CodeName, term.Tags, term.ComplianceFrameworks, req.Status, term.CreatedBy, term.CreatedAt, term.UpdatedAt,
 	).Scan(
-		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
+		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.Status, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
 	)
 
 	if err != nil {
@@ -58,13 +60,13 @@ func (r *TermRepository) GetTermByID(ctx context.Context, id uuid.UUID) (*models
 	term := &models.Term{}
 
 	query := `
-		SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, created_by, created_at, updated_at, updated_by
+		SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, status, created_by, created_at, updated_at, updated_by
 		FROM terms
 		WHERE id = $1
 	`
 
 	err := database.DB.QueryRow(ctx, query, id).Scan(
-		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.VisibilityType, &term.AllowedDepartments, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
+		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.VisibilityType, &term.AllowedDepartments, &term.Status, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
 	)
 
 	if err != nil {
@@ -78,6 +80,7 @@ func (r *TermRepository) GetTermByID(ctx context.Context, id uuid.UUID) (*models
 	term.Contexts, _ = r.GetContextsByTermID(ctx, id)
 	term.Examples, _ = r.GetExamplesByTermID(ctx, id)
 	term.Relationships, _ = r.GetRelationshipsByTermID(ctx, id)
+	term.Aliases, _ = r.GetAliasesByTermID(ctx, id)
 
 	return term, nil
 }
@@ -104,10 +107,7 @@ func (r *TermRepository) ListTerms(ctx context.Context, limit, offset int, categ
 		// 1. visibility_type is 'public' (or NULL, defaulting to public)
 		// 2. visibility_type is 'department_restricted' AND user's department is in allowed_departments
 		// 3. Term has contexts matching user's department (checked separately after loading contexts)
-		baseQuery += fmt.Sprintf(` AND (
-			COALESCE(visibility_type, 'public') = 'public' OR
-			(visibility_type = 'department_restricted' AND $%d = ANY(allowed_departments))
-		)`, argPos)
+		baseQuery += " AND " + termVisibilityClause("", argPos)
 		args = append(args, *userDepartment)
 		argPos++
 	}
@@ -121,7 +121,7 @@ func (r *TermRepository) ListTerms(ctx context.Context, limit, offset int, categ
 
 	// Get terms
 	query := `
-		SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, created_by, created_at, updated_at, updated_by
+		SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, status, created_by, created_at, updated_at, updated_by
 		` + baseQuery + `
 		ORDER BY created_at DESC
 		LIMIT $` + fmt.Sprintf("%d", argPos) + ` OFFSET $` + fmt.Sprintf("%d", argPos+1)
@@ -136,40 +136,105 @@ func (r *TermRepository) ListTerms(ctx context.Context, limit, offset int, categ
 	for rows.Next() {
 		var term models.Term
 		err := rows.Scan(
-			&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.VisibilityType, &term.AllowedDepartments, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
+			&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.VisibilityType, &term.AllowedDepartments, &term.Status, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan term: %w", err)
 		}
 		
-		// Load contexts to check for department match (additional filtering)
+		// Load contexts
 		contexts, _ := r.GetContextsByTermID(ctx, term.ID)
 		term.Contexts = contexts
 		
-		// If user department is provided, also check if term has contexts matching department
-		// This allows public terms with relevant contexts to be shown
-		if userDepartment != nil && *userDepartment != "" {
-			// Check if term has contexts matching user's department
-			hasMatchingContext := false
-			for _, ctx := range contexts {
-				if ctx.Cluster != nil && *ctx.Cluster == *userDepartment {
-					hasMatchingContext = true
-					break
-				}
-			}
-			
-			// If term is public (or NULL) and has matching context, include it
-			// If term is department_restricted, it's already filtered by SQL
-			// If term is public but has no matching context, still include it (public means visible to all)
-			// Only skip if it's department_restricted and doesn't match (already handled by SQL)
-		}
-		
 		terms = append(terms, term)
 	}
 
 	return terms, total, nil
 }
 
+// ListTermsWithContextsAfter retrieves the next batch of terms ordered by ID, with all their contexts
+// loaded through a single joined query. Pass the last ID of the previous batch as afterID to continue.
+func (r *TermRepository) ListTermsWithContextsAfter(ctx context.Context, afterID *uuid.UUID, limit int) ([]models.Term, error) {
+	query := `
+		SELECT t.id, t.term, t.base_definition, t.category, t.code_name, t.tags, t.compliance_frameworks, t.visibility_type, t.allowed_departments, t.status, t.created_at, t.updated_at,
+		       tc.id, tc.cluster, tc.system, tc.product, tc.context_definition, tc.business_rules, tc.compliance_required, tc.created_at, tc.updated_at
+		FROM (
+			SELECT id, term, base_definition, category, code_name, tags, compliance_frameworks, visibility_type, allowed_departments, status, created_at, updated_at
+			FROM terms
+			WHERE $1::uuid IS NULL OR id > $1
+			ORDER BY id
+			LIMIT $2
+		) t
+		LEFT JOIN term_contexts tc ON tc.term_id = t.id
+		ORDER BY t.id, tc.created_at DESC
+	`
+
+	rows, err := database.DB.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list term batch: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []models.Term
+	for rows.Next() {
+		var term models.Term
+		var contextID *uuid.UUID
+		var tc models.TermContext
+		var contextDefinition *string
+		var complianceRequired *bool
+		var contextCreatedAt, contextUpdatedAt *time.Time
+
+		err := rows.Scan(
+			&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.VisibilityType, &term.AllowedDepartments, &term.Status, &term.CreatedAt, &term.UpdatedAt,
+			&contextID, &tc.Cluster, &tc.System, &tc.Product, &contextDefinition, &tc.BusinessRules, &complianceRequired, &contextCreatedAt, &contextUpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan term batch: %w", err)
+		}
+
+		if len(terms) == 0 || terms[len(terms)-1].ID != term.ID {
+			terms = append(terms, term)
+		}
+
+		// Terms without contexts come back with a single row of NULL context columns
+		if contextID == nil {
+			continue
+		}
+		tc.ID = *contextID
+		tc.TermID = term.ID
+		if contextDefinition != nil {
+			tc.ContextDefinition = *contextDefinition
+		}
+		if complianceRequired != nil {
+			tc.ComplianceRequired = *complianceRequired
+		}
+		if contextCreatedAt != nil {
+			tc.CreatedAt = *contextCreatedAt
+		}
+		if contextUpdatedAt != nil {
+			tc.UpdatedAt = *contextUpdatedAt
+		}
+
+		last := &terms[len(terms)-1]
+		last.Contexts = append(last.Contexts, tc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read term batch: %w", err)
+	}
+
+	return terms, nil
+}
+
+// termVisibilityClause returns a SQL condition that keeps terms visible to the department bound at $argPos.
+// alias is the terms table alias including the trailing dot (e.g. "t."), or empty.
+func termVisibilityClause(alias string, argPos int) string {
+	return fmt.Sprintf(`(
+			COALESCE(%[1]svisibility_type, 'public') = 'public' OR
+			(%[1]svisibility_type = 'department_restricted' AND $%[2]d = ANY(%[1]sallowed_departments))
+		)`, alias, argPos)
+}
+
 // UpdateTerm updates an existing term
 func (r *TermRepository) UpdateTerm(ctx context.Context, id uuid.UUID, req models.UpdateTermRequest, userID *uuid.UUID) (*models.Term, error) {
 	// Build dynamic update query
@@ -213,6 +278,12 @@ func (r *TermRepository) UpdateTerm(ctx context.Context, id uuid.UUID, req model
 		argPos++
 	}
 
+	if req.Status != nil {
+		updates = append(updates, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, *req.Status)
+		argPos++
+	}
+
 	if len(updates) == 0 {
 		return r.GetTermByID(ctx, id)
 	}
@@ -242,12 +313,12 @@ func (r *TermRepository) UpdateTerm(ctx context.Context, id uuid.UUID, req model
 		UPDATE terms
 		SET %s
 		WHERE id = $%d
-		RETURNING id, term, base_definition, category, code_name, tags, compliance_frameworks, created_by, created_at, updated_at, updated_by
+		RETURNING id, term, base_definition, category, code_name, tags, compliance_frameworks, status, created_by, created_at, updated_at, updated_by
 	`, setClause, argPos)
 
 	term := &models.Term{}
 	err := database.DB.QueryRow(ctx, query, args...).Scan(
-		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
+		&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.ComplianceFrameworks, &term.Status, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
 	)
 
 	if err != nil {
@@ -476,149 +547,918 @@ func (r *TermRepository) CreateRelationship(ctx context.Context, termID uuid.UUI
 	return relationship, nil
 }
 
-// SearchTerms performs full-text search on terms
-func (r *TermRepository) SearchTerms(ctx context.Context, req models.SearchRequest) ([]models.Term, int, error) {
-	var terms []models.Term
-	var total int
+// GetRelatedTermIDs returns the IDs of all terms related to a term in either direction, of any type
+func (r *TermRepository) GetRelatedTermIDs(ctx context.Context, termID uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT related_term_id FROM term_relationships WHERE term_id = $1
+		UNION
+		SELECT term_id FROM term_relationships WHERE related_term_id = $1
+	`
 
-	// Build search query
-	baseQuery := `
-		FROM terms
-		WHERE to_tsvector('english', term || ' ' || COALESCE(base_definition, '')) @@ plainto_tsquery('english', $1)
+	rows, err := database.DB.Query(ctx, query, termID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related terms: %w", err)
+	}
+	defer rows.Close()
+
+	related := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan related term: %w", err)
+		}
+		related[id] = true
+	}
+
+	return related, nil
+}
+
+// GetAliasesByTermID retrieves all aliases for a term
+func (r *TermRepository) GetAliasesByTermID(ctx context.Context, termID uuid.UUID) ([]models.TermAlias, error) {
+	query := `
+		SELECT id, term_id, alias, alias_type, created_by, created_at
+		FROM term_aliases
+		WHERE term_id = $1
+		ORDER BY alias ASC
 	`
-	args := []interface{}{req.Query}
-	argPos := 2
 
-	if req.Category != nil {
-		baseQuery += fmt.Sprintf(" AND category = $%d", argPos)
-		args = append(args, *req.Category)
-		argPos++
+	rows, err := database.DB.Query(ctx, query, termID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases: %w", err)
 	}
+	defer rows.Close()
 
-	if len(req.Tags) > 0 {
-		baseQuery += fmt.Sprintf(" AND tags && $%d", argPos)
-		args = append(args, req.Tags)
-		argPos++
+	var aliases []models.TermAlias
+	for rows.Next() {
+		var alias models.TermAlias
+		err := rows.Scan(&alias.ID, &alias.TermID, &alias.Alias, &alias.AliasType, &alias.CreatedBy, &alias.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, alias)
 	}
 
-	// Get total count
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	err := database.DB.QueryRow(ctx, countQuery, args...).Scan(&total)
+	return aliases, nil
+}
+
+// ListAliases retrieves the aliases of all terms
+func (r *TermRepository) ListAliases(ctx context.Context) ([]models.TermAlias, error) {
+	query := `
+		SELECT id, term_id, alias, alias_type, created_by, created_at
+		FROM term_aliases
+	`
+
+	rows, err := database.DB.Query(ctx, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := []models.TermAlias{}
+	for rows.Next() {
+		var alias models.TermAlias
+		err := rows.Scan(&alias.ID, &alias.TermID, &alias.Alias, &alias.AliasType, &alias.CreatedBy, &alias.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+// CreateAlias adds an alias to a term
+func (r *TermRepository) CreateAlias(ctx context.Context, termID uuid.UUID, req models.CreateAliasRequest, userID *uuid.UUID) (*models.TermAlias, error) {
+	alias := &models.TermAlias{
+		ID:        uuid.New(),
+		TermID:    termID,
+		Alias:     strings.TrimSpace(req.Alias),
+		AliasType: req.AliasType,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if alias.AliasType == "" {
+		alias.AliasType = "other"
 	}
 
-	// Get terms with ranking
 	query := `
-		SELECT id, term, base_definition, category, code_name, tags, created_by, created_at, updated_at, updated_by,
-		       ts_rank(to_tsvector('english', term || ' ' || COALESCE(base_definition, '')), plainto_tsquery('english', $1)) as rank
-		` + baseQuery + `
-		ORDER BY rank DESC, created_at DESC
-		LIMIT $` + fmt.Sprintf("%d", argPos) + ` OFFSET $` + fmt.Sprintf("%d", argPos+1)
+		INSERT INTO term_aliases (id, term_id, alias, alias_type, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (term_id, lower(alias)) DO NOTHING
+		RETURNING id, term_id, alias, alias_type, created_by, created_at
+	`
 
-	if req.Limit == 0 {
-		req.Limit = 20
+	err := database.DB.QueryRow(ctx, query,
+		alias.ID, alias.TermID, alias.Alias, alias.AliasType, alias.CreatedBy, alias.CreatedAt,
+	).Scan(
+		&alias.ID, &alias.TermID, &alias.Alias, &alias.AliasType, &alias.CreatedBy, &alias.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("alias already exists")
+		}
+		return nil, fmt.Errorf("failed to create alias: %w", err)
 	}
-	args = append(args, req.Limit, req.Offset)
 
-	rows, err := database.DB.Query(ctx, query, args...)
+	return alias, nil
+}
+
+// DeleteAlias removes an alias from a term
+func (r *TermRepository) DeleteAlias(ctx context.Context, termID uuid.UUID, aliasID uuid.UUID) error {
+	result, err := database.DB.Exec(ctx, "DELETE FROM term_aliases WHERE id = $1 AND term_id = $2", aliasID, termID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alias: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alias not found")
+	}
+
+	return nil
+}
+
+// ExpandSearchQuery finds terms a query refers to without containing it: terms with an alias equal
+// to the query or one of its words ("NIM", "KYC"), and synonyms of terms whose name or alias matches.
+func (r *TermRepository) ExpandSearchQuery(ctx context.Context, query string) (map[uuid.UUID][]models.QueryExpansion, error) {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	candidates := append(words, strings.ToLower(strings.TrimSpace(query)))
+
+	sqlQuery := `
+		WITH alias_matches AS (
+			SELECT a.term_id, a.alias
+			FROM term_aliases a
+			WHERE lower(a.alias) = ANY($1)
+		),
+		seeds AS (
+			SELECT term_id, alias AS name FROM alias_matches
+			UNION
+			SELECT t.id, t.term FROM terms t WHERE lower(t.term) = $2
+		)
+		SELECT term_id, 'alias', alias FROM alias_matches
+		UNION
+		SELECT CASE WHEN tr.term_id = s.term_id THEN tr.related_term_id ELSE tr.term_id END, 'synonym', st.term
+		FROM seeds s
+		JOIN term_relationships tr ON tr.relationship_type = 'synonym' AND s.term_id IN (tr.term_id, tr.related_term_id)
+		JOIN terms st ON st.id = s.term_id
+	`
+
+	rows, err := database.DB.Query(ctx, sqlQuery, candidates, strings.ToLower(strings.TrimSpace(query)))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to search terms: %w", err)
+		return nil, fmt.Errorf("failed to expand search query: %w", err)
 	}
 	defer rows.Close()
 
+	expansions := map[uuid.UUID][]models.QueryExpansion{}
+	for rows.Next() {
+		var termID uuid.UUID
+		var expansion models.QueryExpansion
+		if err := rows.Scan(&termID, &expansion.Type, &expansion.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan query expansion: %w", err)
+		}
+		expansions[termID] = append(expansions[termID], expansion)
+	}
+
+	return expansions, nil
+}
+
+// ListTermNames retrieves the names, code names and visibility of all terms, without definitions
+func (r *TermRepository) ListTermNames(ctx context.Context) ([]models.Term, error) {
+	query := `
+		SELECT id, term, code_name, visibility_type, allowed_departments
+		FROM terms
+	`
+
+	rows, err := database.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list term names: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.Term{}
 	for rows.Next() {
 		var term models.Term
-		var rank float64
-		err := rows.Scan(
-			&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy, &rank,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan term: %w", err)
+		if err := rows.Scan(&term.ID, &term.Term, &term.CodeName, &term.VisibilityType, &term.AllowedDepartments); err != nil {
+			return nil, fmt.Errorf("failed to scan term name: %w", err)
 		}
 		terms = append(terms, term)
 	}
 
-	return terms, total, nil
+	return terms, nil
 }
 
-// SearchTermsWithContext performs search including context filtering
-func (r *TermRepository) SearchTermsWithContext(ctx context.Context, req models.SearchRequest) ([]models.Term, int, error) {
-	var terms []models.Term
-	var total int
+// ListRelationships retrieves the relationships of all terms
+func (r *TermRepository) ListRelationships(ctx context.Context) ([]models.TermRelationship, error) {
+	query := `
+		SELECT id, term_id, related_term_id, relationship_type, created_by, created_at
+		FROM term_relationships
+		ORDER BY created_at, id
+	`
 
-	// Build search query with context join
-	baseQuery := `
-		FROM terms t
-		LEFT JOIN term_contexts tc ON t.id = tc.term_id
-		WHERE (
-			to_tsvector('english', t.term || ' ' || COALESCE(t.base_definition, '')) @@ plainto_tsquery('english', $1)
-			OR to_tsvector('english', COALESCE(tc.context_definition, '')) @@ plainto_tsquery('english', $1)
-		)
+	rows, err := database.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
+	}
+	defer rows.Close()
+
+	relationships := []models.TermRelationship{}
+	for rows.Next() {
+		var rel models.TermRelationship
+		if err := rows.Scan(&rel.ID, &rel.TermID, &rel.RelatedTermID, &rel.RelationshipType, &rel.CreatedBy, &rel.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan relationship: %w", err)
+		}
+		relationships = append(relationships, rel)
+	}
+
+	return relationships, nil
+}
+
+// ListExamples retrieves the examples of all terms
+func (r *TermRepository) ListExamples(ctx context.Context) ([]models.TermExample, error) {
+	query := `
+		SELECT id, term_id, context_id, example_text, source, created_by, created_at
+		FROM term_examples
+		ORDER BY created_at, id
 	`
-	args := []interface{}{req.Query}
-	argPos := 2
 
-	if req.Category != nil {
-		baseQuery += fmt.Sprintf(" AND t.category = $%d", argPos)
-		args = append(args, *req.Category)
-		argPos++
+	rows, err := database.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
 	}
+	defer rows.Close()
 
-	if req.Cluster != nil {
-		baseQuery += fmt.Sprintf(" AND tc.cluster = $%d", argPos)
-		args = append(args, *req.Cluster)
-		argPos++
+	examples := []models.TermExample{}
+	for rows.Next() {
+		var ex models.TermExample
+		if err := rows.Scan(&ex.ID, &ex.TermID, &ex.ContextID, &ex.ExampleText, &ex.Source, &ex.CreatedBy, &ex.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan example: %w", err)
+		}
+		examples = append(examples, ex)
 	}
 
-	if req.System != nil {
-		baseQuery += fmt.Sprintf(" AND tc.system = $%d", argPos)
-		args = append(args, *req.System)
-		argPos++
+	return examples, nil
+}
+
+// ListRelationshipsByType retrieves all relationships of one type (e.g. synonym)
+func (r *TermRepository) ListRelationshipsByType(ctx context.Context, relationshipType string) ([]models.TermRelationship, error) {
+	query := `
+		SELECT id, term_id, related_term_id, relationship_type, created_by, created_at
+		FROM term_relationships
+		WHERE relationship_type = $1
+	`
+
+	rows, err := database.DB.Query(ctx, query, relationshipType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
 	}
+	defer rows.Close()
 
-	if len(req.Tags) > 0 {
-		baseQuery += fmt.Sprintf(" AND t.tags && $%d", argPos)
-		args = append(args, req.Tags)
-		argPos++
+	relationships := []models.TermRelationship{}
+	for rows.Next() {
+		var rel models.TermRelationship
+		if err := rows.Scan(&rel.ID, &rel.TermID, &rel.RelatedTermID, &rel.RelationshipType, &rel.CreatedBy, &rel.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan relationship: %w", err)
+		}
+		relationships = append(relationships, rel)
 	}
 
-	// Get total count (distinct terms)
-	countQuery := "SELECT COUNT(DISTINCT t.id) " + baseQuery
-	err := database.DB.QueryRow(ctx, countQuery, args...).Scan(&total)
+	return relationships, nil
+}
+
+// fuzzySearchMinResults is the number of full-text hits below which search falls back to
+// trigram matching and offers "did you mean" suggestions
+const fuzzySearchMinResults = 3
+
+// searchPopularityWindow is how far back usage counts towards personalized ranking
+const searchPopularityWindow = 90 * 24 * time.Hour
+
+// searchDocumentExpr returns the weighted full-text document of a term, maintained by triggers
+// (see term_search_vector in schema.sql). Searches filtered by cluster, system or product use the
+// document without context text and match the filtered contexts separately (contextTextMatch),
+// so text of other clusters' contexts does not match.
+func searchDocumentExpr(alias string, contextFiltered bool) string {
+	if contextFiltered {
+		return alias + "search_vector_base"
+	}
+	return alias + "search_vector"
+}
+
+// contextTextMatch matches the definition and business rules of a context against tsquery
+func contextTextMatch(alias string, tsquery string) string {
+	return fmt.Sprintf(`to_tsvector('english', COALESCE(%[1]scontext_definition, '') || ' ' ||
+				COALESCE(array_to_string(%[1]sbusiness_rules, ' '), '')) @@ %[2]s`, alias, tsquery)
+}
+
+// searchMatchClause matches a term's document against the query in $1. Fuzzy matching also accepts
+// trigram matches on term, code_name and tags, which catches typos ("liquidty") and partial words.
+func searchMatchClause(alias string, document string, fuzzy bool) string {
+	clause := document + " @@ plainto_tsquery('english', $1)"
+	if fuzzy {
+		clause += fmt.Sprintf(`
+			OR lower(%[1]sterm) %% lower($1)
+			OR lower($1) <%% lower(%[1]sterm)
+			OR lower(COALESCE(%[1]scode_name, '')) %% lower($1)
+			OR lower($1) <%% tags_text(%[1]stags)`, alias)
+	}
+	return "(" + clause + ")"
+}
+
+// searchRankExpr blends the full-text rank with trigram similarity, so exact matches still rank
+// above near misses while near misses are ordered by how close they are
+func searchRankExpr(alias string, document string, tsquery string) string {
+	return fmt.Sprintf(`(ts_rank(%[2]s, %[3]s)
+		+ 0.5 * GREATEST(
+			similarity(lower(%[1]sterm), lower($1)),
+			word_similarity(lower($1), lower(%[1]sterm)),
+			similarity(lower(COALESCE(%[1]scode_name, '')), lower($1)),
+			word_similarity(lower($1), tags_text(%[1]stags))
+		))`, alias, document, tsquery)
+}
+
+// searchMatchFieldsExpr lists which parts of the search document matched tsquery (or fuzzily
+// matched $1 when fuzzy). contextFilters restricts the contexts considered, aliased mf.
+func searchMatchFieldsExpr(alias string, fuzzy bool, tsquery string, contextFilters string) string {
+	termMatch := fmt.Sprintf("to_tsvector('english', %[1]sterm) @@ %[2]s", alias, tsquery)
+	codeNameMatch := fmt.Sprintf("to_tsvector('english', COALESCE(%[1]scode_name, '')) @@ %[2]s", alias, tsquery)
+	tagsField := ""
+	if fuzzy {
+		termMatch += fmt.Sprintf(" OR lower(%[1]sterm) %% lower($1) OR lower($1) <%% lower(%[1]sterm)", alias)
+		codeNameMatch += fmt.Sprintf(" OR lower(COALESCE(%[1]scode_name, '')) %% lower($1)", alias)
+		tagsField = fmt.Sprintf(",\n\t\tCASE WHEN lower($1) <%% tags_text(%[1]stags) THEN 'tags' END", alias)
+	}
+
+	fields := fmt.Sprintf(`
+		CASE WHEN %[3]s THEN 'term' END,
+		CASE WHEN %[4]s THEN 'code_name' END,
+		CASE WHEN EXISTS (SELECT 1 FROM term_aliases mf WHERE mf.term_id = %[1]sid AND to_tsvector('english', mf.alias) @@ %[2]s) THEN 'alias' END,
+		CASE WHEN to_tsvector('english', COALESCE(%[1]sbase_definition, '')) @@ %[2]s THEN 'base_definition' END,
+		CASE WHEN EXISTS (SELECT 1 FROM term_contexts mf WHERE mf.term_id = %[1]sid%[6]s AND to_tsvector('english', mf.context_definition) @@ %[2]s) THEN 'context_definition' END,
+		CASE WHEN EXISTS (SELECT 1 FROM term_examples mf WHERE mf.term_id = %[1]sid AND to_tsvector('english', mf.example_text) @@ %[2]s) THEN 'examples' END,
+		CASE WHEN EXISTS (SELECT 1 FROM term_contexts mf WHERE mf.term_id = %[1]sid%[6]s AND to_tsvector('english', array_to_string(mf.business_rules, ' ')) @@ %[2]s) THEN 'business_rules' END%[5]s`,
+		alias, tsquery, termMatch, codeNameMatch, tagsField, contextFilters)
+	return "array_remove(ARRAY[" + fields + "]::text[], NULL)"
+}
+
+// ts_headline marks highlighted words with control characters instead of markup, since it does
+// not escape the text around them. highlightSnippet escapes the text and then puts the markers in.
+const (
+	highlightStartSel = "\x02"
+	highlightStopSel  = "\x03"
+	headlineOptions   = `StartSel="` + highlightStartSel + `", StopSel="` + highlightStopSel + `"`
+)
+
+// highlightSnippet HTML-escapes a ts_headline result and replaces its selection characters with
+// the highlight markers, defaulting to <mark>
+func highlightSnippet(headline, pre, post string) string {
+	if pre == "" {
+		pre = "<mark>"
+	}
+	if post == "" {
+		post = "</mark>"
+	}
+	return strings.NewReplacer(highlightStartSel, pre, highlightStopSel, post).Replace(html.EscapeString(headline))
+}
+
+// searchFacets lists the facets counted for search results
+var searchFacets = []string{"category", "cluster", "system", "product", "tags", "compliance_frameworks", "visibility"}
+
+// searchFacetValueLimit caps the number of values returned per facet
+const searchFacetValueLimit = 50
+
+// searchFilter is a filter on one facet. Selected values within a facet are ORed; facets are ANDed.
+type searchFilter struct {
+	facet   string
+	context bool // filters term_contexts rather than terms
+	clause  func(alias string) string
+}
+
+// searchQuery builds the WHERE clauses shared by the hit, count and facet queries so that
+// SearchTerms and SearchTermsWithContext rank and count the same way. The query text is $1; for
+// advanced queries it is the positive free text, used for ranking.
+type searchQuery struct {
+	filters      []searchFilter
+	withContexts bool // context definitions count as matches
+	expandedPos  int  // argument position of the term IDs found via aliases and synonyms, 0 if none
+	visiblePos   int  // argument position of the caller's department for term visibility, 0 if none
+	args         []interface{}
+
+	// Compiled advanced query (see compileExpr); empty for plain text queries
+	exprMatch   string
+	exprTSQuery string
+}
+
+func newSearchQuery(req models.SearchRequest, withContexts bool) *searchQuery {
+	q := &searchQuery{withContexts: withContexts, args: []interface{}{req.Query}}
+	if req.Expr != nil {
+		q.args[0] = searchExprText(req.Expr)
+		positive := []string{}
+		q.exprMatch = q.compileExpr(req.Expr, false, &positive)
+		q.exprTSQuery = strings.Join(positive, " || ")
+	}
+	q.addFilter("category", false, "%scategory = ANY($%d)", req.Categories)
+	q.addFilter("cluster", true, "%scluster = ANY($%d)", req.Clusters)
+	q.addFilter("system", true, "%ssystem = ANY($%d)", req.Systems)
+	q.addFilter("product", true, "%sproduct = ANY($%d)", req.Products)
+	q.addFilter("tags", false, "%stags && $%d", req.Tags)
+	q.addFilter("compliance_frameworks", false, "%scompliance_frameworks && $%d", req.ComplianceFrameworks)
+	q.addFilter("visibility", false, "COALESCE(%svisibility_type, 'public') = ANY($%d)", req.Visibility)
+	if req.UserDepartment != nil && *req.UserDepartment != "" {
+		q.args = append(q.args, *req.UserDepartment)
+		q.visiblePos = len(q.args)
+	}
+	return q
+}
+
+func (q *searchQuery) addFilter(facet string, context bool, format string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	q.args = append(q.args, values)
+	argPos := len(q.args)
+	q.filters = append(q.filters, searchFilter{
+		facet:   facet,
+		context: context,
+		clause: func(alias string) string {
+			return fmt.Sprintf(format, alias, argPos)
+		},
+	})
+}
+
+// advanced reports whether the query uses the advanced syntax
+func (q *searchQuery) advanced() bool {
+	return q.exprMatch != ""
+}
+
+// tsquery returns the tsquery used for ranking and highlighting
+func (q *searchQuery) tsquery() string {
+	if q.exprTSQuery != "" {
+		return "(" + q.exprTSQuery + ")"
+	}
+	return "plainto_tsquery('english', $1)"
+}
+
+// addExpansions makes terms found via aliases and synonyms match the query
+func (q *searchQuery) addExpansions(termIDs []uuid.UUID) {
+	if len(termIDs) == 0 {
+		return
+	}
+	q.args = append(q.args, termIDs)
+	q.expandedPos = len(q.args)
+}
+
+// document returns the search document the query matches against
+func (q *searchQuery) document() string {
+	return searchDocumentExpr("t.", q.withContexts)
+}
+
+// rankExpr ranks hits, boosting terms found via an alias or synonym as if they matched exactly
+func (q *searchQuery) rankExpr() string {
+	rank := searchRankExpr("t.", q.document(), q.tsquery())
+	if q.expandedPos > 0 {
+		rank = fmt.Sprintf("(%s + CASE WHEN t.id = ANY($%d) THEN 0.5 ELSE 0 END)", rank, q.expandedPos)
+	}
+	return rank
+}
+
+// scoreColumns returns the score components of the hit query and the join they need. Without
+// personalization only the text score counts. Personalized ranking adds ln(1 + uses) of the term
+// over searchPopularityWindow, overall and from the caller's cluster or department, and a
+// boost or penalty for the term status.
+func scoreColumns(req models.SearchRequest, textScore string, args []interface{}) (string, string, []interface{}) {
+	if !req.Personalized {
+		return "", textScore + ` AS text_score, 0::float8 AS popularity_score, 0::float8 AS affinity_score,
+			0::float8 AS status_score, 0::bigint AS usage_count, 0::bigint AS local_usage_count`, args
+	}
+
+	w := req.RankWeights
+	pos := len(args) + 1
+	args = append(args, time.Now().Add(-searchPopularityWindow), req.UserCluster, req.UserDepartment,
+		w.Popularity, w.Affinity, w.Certified, w.Approved, w.Deprecated)
+
+	join := fmt.Sprintf(`
+			LEFT JOIN LATERAL (
+				SELECT COALESCE(SUM(e.event_count), 0)::bigint AS usage_count,
+				       COALESCE(SUM(e.event_count) FILTER (WHERE e.cluster = $%[2]d::text OR e.department = $%[3]d::text), 0)::bigint AS local_usage_count
+				FROM term_usage_events e
+				WHERE e.term_id = t.id AND e.created_at >= $%[1]d
+			) term_usage ON true`, pos, pos+1, pos+2)
+	columns := fmt.Sprintf(`%[1]s AS text_score,
+			$%[2]d::float8 * ln(1 + term_usage.usage_count) AS popularity_score,
+			$%[3]d::float8 * ln(1 + term_usage.local_usage_count) AS affinity_score,
+			CASE t.status WHEN 'certified' THEN $%[4]d::float8 WHEN 'approved' THEN $%[5]d::float8
+				WHEN 'deprecated' THEN -$%[6]d::float8 ELSE 0 END AS status_score,
+			term_usage.usage_count, term_usage.local_usage_count`, textScore, pos+3, pos+4, pos+5, pos+6, pos+7)
+
+	return join, columns, args
+}
+
+// filterClauses returns the term or context filters for alias, skipping the filter on facet except
+func (q *searchQuery) filterClauses(context bool, alias string, except string) string {
+	clauses := ""
+	for _, filter := range q.filters {
+		if filter.context == context && filter.facet != except {
+			clauses += " AND " + filter.clause(alias)
+		}
+	}
+	return clauses
+}
+
+// where builds the WHERE clause over terms t. The filter on facet except is left out, which gives
+// multi-select facet counts: other values of a selected facet are still counted. Terms the
+// caller's department cannot see are never matched or counted.
+func (q *searchQuery) where(fuzzy bool, except string) string {
+	contextFilters := q.filterClauses(true, "tc.", except)
+
+	// Advanced queries match context definitions per text term (see compileExpr)
+	match := strings.ReplaceAll(q.exprMatch, exprContextFilters, contextFilters)
+	if !q.advanced() {
+		match = searchMatchClause("t.", q.document(), fuzzy)
+		if q.expandedPos > 0 {
+			match = fmt.Sprintf("(%s OR t.id = ANY($%d))", match, q.expandedPos)
+		}
+		if q.withContexts {
+			match = `(` + match + ` OR EXISTS (
+			SELECT 1 FROM term_contexts tc
+			WHERE tc.term_id = t.id` + contextFilters + `
+			AND ` + contextTextMatch("tc.", q.tsquery()) + `
+		))`
+		}
+	}
+
+	where := match + q.filterClauses(false, "t.", except)
+	if q.visiblePos > 0 {
+		where += " AND " + termVisibilityClause("t.", q.visiblePos)
+	}
+	if contextFilters != "" {
+		where += " AND EXISTS (SELECT 1 FROM term_contexts tc WHERE tc.term_id = t.id" + contextFilters + ")"
+	}
+	return where
+}
+
+// facetQuery counts matching terms per facet value, each facet ignoring its own filter
+func (q *searchQuery) facetQuery(fuzzy bool) string {
+	branches := make([]string, 0, len(searchFacets))
+	for _, facet := range searchFacets {
+		where := q.where(fuzzy, facet)
+		switch facet {
+		case "category":
+			branches = append(branches, `SELECT 'category' AS facet, t.category::text AS value, COUNT(*) AS count
+				FROM terms t WHERE `+where+` AND t.category IS NOT NULL GROUP BY 2`)
+		case "visibility":
+			branches = append(branches, `SELECT 'visibility', COALESCE(t.visibility_type, 'public')::text, COUNT(*)
+				FROM terms t WHERE `+where+` GROUP BY 2`)
+		case "tags", "compliance_frameworks":
+			branches = append(branches, fmt.Sprintf(`SELECT '%[1]s', v::text, COUNT(DISTINCT t.id)
+				FROM terms t CROSS JOIN LATERAL unnest(t.%[1]s) AS v WHERE %[2]s GROUP BY 2`, facet, where))
+		default:
+			// Context facets count the values of contexts that also pass the other context filters
+			branches = append(branches, fmt.Sprintf(`SELECT '%[1]s', fc.%[1]s::text, COUNT(DISTINCT t.id)
+				FROM terms t JOIN term_contexts fc ON fc.term_id = t.id
+				WHERE %[2]s AND fc.%[1]s IS NOT NULL%[3]s GROUP BY 2`, facet, where, q.filterClauses(true, "fc.", facet)))
+		}
+	}
+
+	return fmt.Sprintf(`
+		SELECT facet, value, count FROM (
+			SELECT facet, value, count, row_number() OVER (PARTITION BY facet ORDER BY count DESC, value ASC) AS rn
+			FROM (%s) f
+		) ranked
+		WHERE rn <= %d
+		ORDER BY facet, count DESC, value ASC`, strings.Join(branches, "\n\t\t\tUNION ALL\n\t\t\t"), searchFacetValueLimit)
+}
+
+// SearchTerms performs full-text search on terms, falling back to fuzzy matching
+func (r *TermRepository) SearchTerms(ctx context.Context, req models.SearchRequest) (*models.SearchResults, error) {
+	return r.runSearch(ctx, req, newSearchQuery(req, false))
+}
+
+// SearchTermsWithContext performs search where context definitions also match. A term matches
+// when the term or the definition or business rules of a context passing the cluster/system/product
+// filters match; text of the term's other contexts does not count.
+func (r *TermRepository) SearchTermsWithContext(ctx context.Context, req models.SearchRequest) (*models.SearchResults, error) {
+	return r.runSearch(ctx, req, newSearchQuery(req, true))
+}
+
+// runSearch counts and fetches ranked hits and facet counts. When full text finds fewer than
+// fuzzySearchMinResults terms the search is repeated with fuzzy matching and "did you mean"
+// suggestions are added. When searching contexts, the matching context definitions are returned per hit.
+func (r *TermRepository) runSearch(ctx context.Context, req models.SearchRequest, q *searchQuery) (*models.SearchResults, error) {
+	results := &models.SearchResults{Hits: []models.SearchHit{}}
+
+	// Advanced queries are taken literally: no alias expansion or fuzzy fallback
+	expansions := map[uuid.UUID][]models.QueryExpansion{}
+	if !q.advanced() {
+		var err error
+		expansions, err = r.ExpandSearchQuery(ctx, req.Query)
+		if err != nil {
+			return nil, err
+		}
+		expandedIDs := make([]uuid.UUID, 0, len(expansions))
+		for termID := range expansions {
+			expandedIDs = append(expandedIDs, termID)
+		}
+		q.addExpansions(expandedIDs)
+	}
+	args := q.args
+
+	countQuery := "SELECT COUNT(*) FROM terms t WHERE " + q.where(false, "")
+	err := database.DB.QueryRow(ctx, countQuery, args...).Scan(&results.Total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+		return nil, fmt.Errorf("failed to count search results: %w", err)
 	}
 
-	// Get distinct terms
-	query := `
-		SELECT DISTINCT ON (t.id) t.id, t.term, t.base_definition, t.category, t.code_name, t.tags, t.created_by, t.created_at, t.updated_at, t.updated_by
-		` + baseQuery + `
-		ORDER BY t.id, t.created_at DESC
-		LIMIT $` + fmt.Sprintf("%d", argPos) + ` OFFSET $` + fmt.Sprintf("%d", argPos+1)
+	if results.Total < fuzzySearchMinResults && !q.advanced() {
+		results.Fuzzy = true
+		countQuery = "SELECT COUNT(*) FROM terms t WHERE " + q.where(true, "")
+		err := database.DB.QueryRow(ctx, countQuery, args...).Scan(&results.Total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count search results: %w", err)
+		}
+
+		results.Suggestions, err = r.GetSearchSuggestions(ctx, req.Query, req.UserDepartment, 5)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !req.SkipFacets {
+		results.Facets, err = r.getSearchFacets(ctx, q, results.Fuzzy)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
+
+	termOptsPos := len(args) + 1
+	snippetOptsPos := termOptsPos + 1
+	limitPos := snippetOptsPos + 1
+	args = append(args,
+		headlineOptions+", HighlightAll=true",
+		headlineOptions+", MaxWords=35, MinWords=15, MaxFragments=2",
+	)
+
+	// Matching context definitions with a snippet each, when searching within contexts
+	contextMatches := "'[]'::json"
+	if q.withContexts {
+		contextMatches = fmt.Sprintf(`(
+			SELECT COALESCE(json_agg(json_build_object(
+				'context_id', tc.id, 'cluster', tc.cluster, 'system', tc.system, 'product', tc.product,
+				'snippet', ts_headline('english', tc.context_definition, %[1]s, $%[2]d)
+			) ORDER BY tc.cluster, tc.system), '[]'::json)
+			FROM term_contexts tc
+			WHERE tc.term_id = t.id%[3]s
+			AND %[4]s
+		)`, q.tsquery(), snippetOptsPos, q.filterClauses(true, "tc.", ""), contextTextMatch("tc.", q.tsquery()))
+	}
+
 	args = append(args, req.Limit, req.Offset)
+	scoreJoin, scores, args := scoreColumns(req, q.rankExpr(), args)
+
+	// The score components are computed per match; snippets and match fields only for the page
+	query := fmt.Sprintf(`
+		SELECT id, term, base_definition, category, code_name, tags, status, created_by, created_at, updated_at, updated_by, rank,
+		       ts_headline('english', term, %[9]s, $%[1]d),
+		       ts_headline('english', base_definition, %[9]s, $%[2]d),
+		       match_fields, context_matches,
+		       text_score, popularity_score, affinity_score, status_score, usage_count, local_usage_count
+		FROM (
+			SELECT t.*, scored.text_score, scored.popularity_score, scored.affinity_score, scored.status_score,
+			       scored.usage_count, scored.local_usage_count, scored.rank,
+			       %[4]s AS match_fields, %[5]s AS context_matches
+			FROM (
+				SELECT t.id, s.*, s.text_score + s.popularity_score + s.affinity_score + s.status_score AS rank
+				FROM terms t%[10]s
+				CROSS JOIN LATERAL (SELECT %[3]s) s
+				WHERE %[6]s
+			) scored
+			JOIN terms t ON t.id = scored.id
+			ORDER BY scored.rank DESC, t.created_at DESC
+			LIMIT $%[7]d OFFSET $%[8]d
+		) hits
+		ORDER BY rank DESC, created_at DESC`,
+		termOptsPos, snippetOptsPos, scores, searchMatchFieldsExpr("t.", results.Fuzzy, q.tsquery(), q.filterClauses(true, "mf.", "")), contextMatches,
+		q.where(results.Fuzzy, ""), limitPos, limitPos+1, q.tsquery(), scoreJoin)
 
 	rows, err := database.DB.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to search terms: %w", err)
+		return nil, fmt.Errorf("failed to search terms: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var term models.Term
+		var hit models.SearchHit
+		var breakdown models.ScoreBreakdown
 		err := rows.Scan(
-			&term.ID, &term.Term, &term.BaseDefinition, &term.Category, &term.CodeName, &term.Tags, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt, &term.UpdatedBy,
+			&hit.ID, &hit.Term.Term, &hit.BaseDefinition, &hit.Category, &hit.CodeName, &hit.Tags, &hit.Status, &hit.CreatedBy, &hit.CreatedAt, &hit.UpdatedAt, &hit.UpdatedBy, &hit.Score,
+			&hit.Highlights.Term, &hit.Highlights.BaseDefinition, &hit.MatchFields, &hit.ContextMatches,
+			&breakdown.Text, &breakdown.Popularity, &breakdown.Affinity, &breakdown.Status, &breakdown.UsageCount, &breakdown.LocalUsageCount,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan term: %w", err)
+			return nil, fmt.Errorf("failed to scan term: %w", err)
 		}
-		terms = append(terms, term)
+		if req.Debug {
+			hit.ScoreBreakdown = &breakdown
+		}
+		hit.Highlights.Term = highlightSnippet(hit.Highlights.Term, req.HighlightPre, req.HighlightPost)
+		hit.Highlights.BaseDefinition = highlightSnippet(hit.Highlights.BaseDefinition, req.HighlightPre, req.HighlightPost)
+		for i := range hit.ContextMatches {
+			hit.ContextMatches[i].Snippet = highlightSnippet(hit.ContextMatches[i].Snippet, req.HighlightPre, req.HighlightPost)
+		}
+		if len(hit.ContextMatches) > 0 && !containsString(hit.MatchFields, "context_definition") {
+			hit.MatchFields = append(hit.MatchFields, "context_definition")
+		}
+		hit.MatchedVia = expansions[hit.ID]
+		for _, expansion := range hit.MatchedVia {
+			if !containsString(hit.MatchFields, expansion.Type) {
+				hit.MatchFields = append(hit.MatchFields, expansion.Type)
+			}
+		}
+		results.Hits = append(results.Hits, hit)
 	}
 
-	return terms, total, nil
+	return results, nil
 }
 
+// SearchResultCap bounds the result set tracked for saved search alerts
+const SearchResultCap = 5000
+
+// SearchTermIDs returns the IDs of all terms matching a search, without fuzzy fallback, in ID
+// order. It matches the way SearchTerms/SearchTermsWithContext count results. Searches matching
+// more than SearchResultCap terms are rejected rather than truncated.
+func (r *TermRepository) SearchTermIDs(ctx context.Context, req models.SearchRequest, withContexts bool) ([]uuid.UUID, error) {
+	q := newSearchQuery(req, withContexts)
+	if !q.advanced() {
+		expansions, err := r.ExpandSearchQuery(ctx, req.Query)
+		if err != nil {
+			return nil, err
+		}
+		expandedIDs := make([]uuid.UUID, 0, len(expansions))
+		for termID := range expansions {
+			expandedIDs = append(expandedIDs, termID)
+		}
+		q.addExpansions(expandedIDs)
+	}
+
+	query := fmt.Sprintf("SELECT t.id FROM terms t WHERE %s ORDER BY t.id LIMIT %d", q.where(false, ""), SearchResultCap+1)
+	rows, err := database.DB.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search term IDs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan term ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > SearchResultCap {
+		return nil, fmt.Errorf("search matches more than %d terms", SearchResultCap)
+	}
+
+	return ids, nil
+}
+
+// GetTermNamesByIDs returns the names of the given terms visible to userDepartment; deleted and
+// hidden terms are missing from the map
+func (r *TermRepository) GetTermNamesByIDs(ctx context.Context, ids []uuid.UUID, userDepartment *string) (map[uuid.UUID]string, error) {
+	query := `SELECT id, term FROM terms WHERE id = ANY($1)`
+	args := []interface{}{ids}
+	if userDepartment != nil && *userDepartment != "" {
+		args = append(args, *userDepartment)
+		query += " AND " + termVisibilityClause("", len(args))
+	}
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get term names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[uuid.UUID]string, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan term name: %w", err)
+		}
+		names[id] = name
+	}
+
+	return names, nil
+}
+
+// ResolveDefinitions returns the definition of each term for cluster: the cluster's context
+// definition when there is one, preferring contexts without system or product, else the base
+// definition. Deleted terms are missing from the map.
+func (r *TermRepository) ResolveDefinitions(ctx context.Context, ids []uuid.UUID, cluster *string) (map[uuid.UUID]*models.AnnotatedTerm, error) {
+	query := `
+		SELECT t.id, t.term, t.code_name, COALESCE(cd.context_definition, t.base_definition), cd.cluster
+		FROM terms t
+		LEFT JOIN LATERAL (
+			SELECT tc.context_definition, tc.cluster
+			FROM term_contexts tc
+			WHERE tc.term_id = t.id AND tc.cluster = $2
+			ORDER BY (tc.system IS NULL AND tc.product IS NULL) DESC, tc.created_at ASC
+			LIMIT 1
+		) cd ON true
+		WHERE t.id = ANY($1)
+	`
+
+	rows, err := database.DB.Query(ctx, query, ids, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve definitions: %w", err)
+	}
+	defer rows.Close()
+
+	terms := make(map[uuid.UUID]*models.AnnotatedTerm, len(ids))
+	for rows.Next() {
+		term := &models.AnnotatedTerm{}
+		if err := rows.Scan(&term.TermID, &term.Term, &term.CodeName, &term.Definition, &term.Cluster); err != nil {
+			return nil, fmt.Errorf("failed to scan definition: %w", err)
+		}
+		terms[term.TermID] = term
+	}
+
+	return terms, nil
+}
+
+// getSearchFacets returns the value counts of every facet for a search
+func (r *TermRepository) getSearchFacets(ctx context.Context, q *searchQuery, fuzzy bool) (map[string][]models.FacetValue, error) {
+	rows, err := database.DB.Query(ctx, q.facetQuery(fuzzy), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search facets: %w", err)
+	}
+	defer rows.Close()
+
+	facets := make(map[string][]models.FacetValue, len(searchFacets))
+	for _, facet := range searchFacets {
+		facets[facet] = []models.FacetValue{}
+	}
+
+	for rows.Next() {
+		var facet string
+		var value models.FacetValue
+		if err := rows.Scan(&facet, &value.Value, &value.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan search facet: %w", err)
+		}
+		facets[facet] = append(facets[facet], value)
+	}
+
+	return facets, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSearchSuggestions returns the names of the terms visible to userDepartment most similar to a
+// query, for "did you mean"
+func (r *TermRepository) GetSearchSuggestions(ctx context.Context, query string, userDepartment *string, limit int) ([]string, error) {
+	args := []interface{}{query, limit}
+	visibility := ""
+	if userDepartment != nil && *userDepartment != "" {
+		args = append(args, *userDepartment)
+		visibility = " AND " + termVisibilityClause("", len(args))
+	}
+
+	sqlQuery := `
+		SELECT term FROM (
+			SELECT DISTINCT ON (lower(term)) term, similarity(lower(term), lower($1)) AS score
+			FROM terms
+			WHERE lower(term) % lower($1) AND lower(term) <> lower($1)` + visibility + `
+			ORDER BY lower(term), score DESC
+		) s
+		ORDER BY score DESC, term ASC
+		LIMIT $2
+	`
+
+	rows, err := database.DB.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []string{}
+	for rows.Next() {
+		var term string
+		if err := rows.Scan(&term); err != nil {
+			return nil, fmt.Errorf("failed to scan search suggestion: %w", err)
+		}
+		suggestions = append(suggestions, term)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,819 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"clarityconnect/pkg/database"
+)
+
+type UsageRepository struct{}
+
+func NewUsageRepository() *UsageRepository {
+	return &UsageRepository{}
+}
+
+var usageLogColumns = []string{"term_id", "cluster", "department", "user_id", "action", "created_at"}
+
+// CreateUsageLogs bulk-inserts usage log entries using COPY and returns the number of rows written.
+// User IDs are taken from a request header, so those without a user are written as NULL rather
+// than failing the whole COPY on the foreign key.
+func (r *UsageRepository) CreateUsageLogs(ctx context.Context, logs []models.TermUsageLog) (int64, error) {
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	userIDs := []uuid.UUID{}
+	for _, entry := range logs {
+		if entry.UserID != nil {
+			userIDs = append(userIDs, *entry.UserID)
+		}
+	}
+	knownUsers, err := r.existingUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := make([][]interface{}, len(logs))
+	for i, entry := range logs {
+		userID := entry.UserID
+		if userID != nil && !knownUsers[*userID] {
+			userID = nil
+		}
+		rows[i] = []interface{}{entry.TermID, entry.Cluster, entry.Department, userID, entry.Action, entry.CreatedAt}
+	}
+
+	count, err := database.DB.CopyFrom(ctx, pgx.Identifier{"term_usage_logs"}, usageLogColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return count, fmt.Errorf("failed to copy usage logs: %w", err)
+	}
+
+	return count, nil
+}
+
+// existingUserIDs returns which of the given user IDs belong to a user
+func (r *UsageRepository) existingUserIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := map[uuid.UUID]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	rows, err := database.DB.Query(ctx, `SELECT id FROM users WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// CreateUsageLog inserts a single usage log entry, clearing a user ID without a user
+func (r *UsageRepository) CreateUsageLog(ctx context.Context, entry models.TermUsageLog) error {
+	query := `
+		INSERT INTO term_usage_logs (term_id, cluster, department, user_id, action, created_at)
+		VALUES ($1, $2, $3, (SELECT id FROM users WHERE id = $4), $5, $6)
+	`
+
+	_, err := database.DB.Exec(ctx, query, entry.TermID, entry.Cluster, entry.Department, entry.UserID, entry.Action, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create usage log: %w", err)
+	}
+
+	return nil
+}
+
+// usageBuckets lists the supported time series bucket sizes
+var usageBuckets = map[string]bool{"hour": true, "day": true, "week": true, "month": true}
+
+// usageFilterClause builds the WHERE conditions shared by the usage analytics queries.
+// The term_usage_events view is aliased l and the terms table t. From and To are always bound first.
+func usageFilterClause(filter models.UsageFilter, args []interface{}) (string, []interface{}) {
+	args = append(args, filter.From, filter.To)
+	where := fmt.Sprintf("l.created_at >= $%d AND l.created_at < $%d", len(args)-1, len(args))
+
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		where += fmt.Sprintf(" AND l.action = $%d", len(args))
+	}
+
+	if filter.Cluster != nil {
+		args = append(args, *filter.Cluster)
+		where += fmt.Sprintf(" AND l.cluster = $%d", len(args))
+	}
+
+	if filter.Department != nil {
+		args = append(args, *filter.Department)
+		where += fmt.Sprintf(" AND l.department = $%d", len(args))
+	}
+
+	if filter.UserDepartment != nil {
+		args = append(args, *filter.UserDepartment)
+		where += " AND " + termVisibilityClause("t.", len(args))
+	}
+
+	return where, args
+}
+
+// GetTopTerms retrieves the most used terms in a period
+func (r *UsageRepository) GetTopTerms(ctx context.Context, filter models.UsageFilter, limit int) ([]models.TermUsageCount, error) {
+	where, args := usageFilterClause(filter, nil)
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.term, t.category, SUM(l.event_count)::bigint AS usage_count
+		FROM term_usage_events l
+		JOIN terms t ON t.id = l.term_id
+		WHERE %s
+		GROUP BY t.id, t.term, t.category
+		ORDER BY usage_count DESC, t.term ASC
+		LIMIT $%d
+	`, where, len(args))
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.TermUsageCount{}
+	for rows.Next() {
+		var tc models.TermUsageCount
+		if err := rows.Scan(&tc.TermID, &tc.Term, &tc.Category, &tc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan term usage: %w", err)
+		}
+		terms = append(terms, tc)
+	}
+
+	return terms, nil
+}
+
+// GetUsageBreakdown retrieves usage grouped by cluster or department
+func (r *UsageRepository) GetUsageBreakdown(ctx context.Context, filter models.UsageFilter, by string) ([]models.UsageBreakdown, error) {
+	if by != "cluster" && by != "department" {
+		return nil, fmt.Errorf("invalid breakdown")
+	}
+
+	where, args := usageFilterClause(filter, nil)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(l.%[1]s, 'unknown') AS key, SUM(l.event_count)::bigint AS usage_count, COUNT(DISTINCT l.term_id)
+		FROM term_usage_events l
+		JOIN terms t ON t.id = l.term_id
+		WHERE %[2]s
+		GROUP BY key
+		ORDER BY usage_count DESC, key ASC
+	`, by, where)
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	breakdown := []models.UsageBreakdown{}
+	for rows.Next() {
+		var b models.UsageBreakdown
+		if err := rows.Scan(&b.Key, &b.Count, &b.UniqueTerms); err != nil {
+			return nil, fmt.Errorf("failed to scan usage breakdown: %w", err)
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	return breakdown, nil
+}
+
+// GetDepartmentHeatmap retrieves usage per department against cluster or term category
+func (r *UsageRepository) GetDepartmentHeatmap(ctx context.Context, filter models.UsageFilter, column string) ([]models.UsageHeatmapCell, error) {
+	var columnExpr string
+	switch column {
+	case "cluster":
+		columnExpr = "l.cluster"
+	case "category":
+		columnExpr = "t.category"
+	default:
+		return nil, fmt.Errorf("invalid heatmap column")
+	}
+
+	where, args := usageFilterClause(filter, nil)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(l.department, 'unknown') AS department, COALESCE(%s, 'unknown') AS col, SUM(l.event_count)::bigint
+		FROM term_usage_events l
+		JOIN terms t ON t.id = l.term_id
+		WHERE %s
+		GROUP BY department, col
+		ORDER BY department ASC, col ASC
+	`, columnExpr, where)
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	cells := []models.UsageHeatmapCell{}
+	for rows.Next() {
+		var cell models.UsageHeatmapCell
+		if err := rows.Scan(&cell.Department, &cell.Column, &cell.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan usage heatmap: %w", err)
+		}
+		cells = append(cells, cell)
+	}
+
+	return cells, nil
+}
+
+// GetUsageTimeSeries retrieves usage counts per time bucket, including empty buckets, optionally for a single term
+func (r *UsageRepository) GetUsageTimeSeries(ctx context.Context, filter models.UsageFilter, bucket string, termID *uuid.UUID) ([]models.UsageTimeBucket, error) {
+	if !usageBuckets[bucket] {
+		return nil, fmt.Errorf("invalid bucket")
+	}
+
+	where, args := usageFilterClause(filter, nil)
+	if termID != nil {
+		args = append(args, *termID)
+		where += fmt.Sprintf(" AND l.term_id = $%d", len(args))
+	}
+
+	// $1 and $2 are the period bounds bound by usageFilterClause
+	query := fmt.Sprintf(`
+		WITH usage AS (
+			SELECT date_trunc('%[1]s', l.created_at) AS bucket, SUM(l.event_count)::bigint AS usage_count
+			FROM term_usage_events l
+			JOIN terms t ON t.id = l.term_id
+			WHERE %[2]s
+			GROUP BY bucket
+		)
+		SELECT s.bucket, COALESCE(u.usage_count, 0)
+		FROM generate_series(date_trunc('%[1]s', $1::timestamp), $2::timestamp, interval '1 %[1]s') AS s(bucket)
+		LEFT JOIN usage u ON u.bucket = s.bucket
+		WHERE s.bucket < $2::timestamp
+		ORDER BY s.bucket ASC
+	`, bucket, where)
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage time series: %w", err)
+	}
+	defer rows.Close()
+
+	series := []models.UsageTimeBucket{}
+	for rows.Next() {
+		var b models.UsageTimeBucket
+		if err := rows.Scan(&b.Bucket, &b.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan usage bucket: %w", err)
+		}
+		series = append(series, b)
+	}
+
+	return series, nil
+}
+
+// GetTrendingTerms compares usage in the recent window [recentStart, filter.To) with the baseline
+// window [filter.From, recentStart) and returns the terms whose daily rate grew the most
+func (r *UsageRepository) GetTrendingTerms(ctx context.Context, filter models.UsageFilter, recentStart time.Time, minCount int, limit int) ([]models.TrendingTerm, error) {
+	where, args := usageFilterClause(filter, nil)
+	args = append(args, recentStart)
+	recentPos := len(args)
+	args = append(args, minCount)
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.term,
+		       COALESCE(SUM(l.event_count) FILTER (WHERE l.created_at >= $%[2]d), 0)::bigint AS recent_count,
+		       COALESCE(SUM(l.event_count) FILTER (WHERE l.created_at < $%[2]d), 0)::bigint AS baseline_count
+		FROM term_usage_events l
+		JOIN terms t ON t.id = l.term_id
+		WHERE %[1]s
+		GROUP BY t.id, t.term
+		HAVING COALESCE(SUM(l.event_count) FILTER (WHERE l.created_at >= $%[2]d), 0) >= $%[3]d
+	`, where, recentPos, len(args))
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending terms: %w", err)
+	}
+	defer rows.Close()
+
+	recentDays := filter.To.Sub(recentStart).Hours() / 24
+	baselineDays := recentStart.Sub(filter.From).Hours() / 24
+
+	trending := []models.TrendingTerm{}
+	for rows.Next() {
+		var tt models.TrendingTerm
+		if err := rows.Scan(&tt.TermID, &tt.Term, &tt.RecentCount, &tt.BaselineCount); err != nil {
+			return nil, fmt.Errorf("failed to scan trending term: %w", err)
+		}
+
+		if recentDays > 0 {
+			tt.RecentRate = float64(tt.RecentCount) / recentDays
+		}
+		if baselineDays > 0 {
+			tt.BaselineRate = float64(tt.BaselineCount) / baselineDays
+		}
+
+		// Smooth the baseline so terms that were never used before do not dominate with infinite growth
+		baselineRate := tt.BaselineRate
+		if baselineDays > 0 && baselineRate < 1/baselineDays {
+			baselineRate = 1 / baselineDays
+		}
+		if baselineRate > 0 {
+			tt.Growth = (tt.RecentRate - tt.BaselineRate) / baselineRate
+		}
+
+		if tt.Growth > 0 {
+			trending = append(trending, tt)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trending terms: %w", err)
+	}
+
+	sort.SliceStable(trending, func(i, j int) bool {
+		if trending[i].Growth != trending[j].Growth {
+			return trending[i].Growth > trending[j].Growth
+		}
+		return trending[i].RecentCount > trending[j].RecentCount
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+
+	return trending, nil
+}
+
+// GetUnusedTerms retrieves terms, older than the cutoff, that have not been viewed since the cutoff
+func (r *UsageRepository) GetUnusedTerms(ctx context.Context, cutoff time.Time, userDepartment *string, limit int) ([]models.UnusedTerm, error) {
+	args := []interface{}{cutoff}
+	where := "t.created_at < $1"
+
+	if userDepartment != nil {
+		args = append(args, *userDepartment)
+		where += " AND " + termVisibilityClause("t.", len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.term, t.category, t.created_at, MAX(l.created_at) AS last_viewed_at
+		FROM terms t
+		LEFT JOIN term_usage_events l ON l.term_id = t.id AND l.action = 'viewed'
+		WHERE %s
+		GROUP BY t.id, t.term, t.category, t.created_at
+		HAVING MAX(l.created_at) IS NULL OR MAX(l.created_at) < $1
+		ORDER BY last_viewed_at ASC NULLS FIRST, t.term ASC
+		LIMIT $%d
+	`, where, len(args))
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unused terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.UnusedTerm{}
+	for rows.Next() {
+		var ut models.UnusedTerm
+		if err := rows.Scan(&ut.TermID, &ut.Term, &ut.Category, &ut.CreatedAt, &ut.LastViewedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan unused term: %w", err)
+		}
+		terms = append(terms, ut)
+	}
+
+	return terms, nil
+}
+
+// GetTermPopularity returns the number of views and search click-throughs per term since `since`
+func (r *UsageRepository) GetTermPopularity(ctx context.Context, since time.Time) (map[uuid.UUID]int64, error) {
+	query := `
+		SELECT term_id, SUM(event_count)::bigint
+		FROM term_usage_events
+		WHERE created_at >= $1 AND action IN ('viewed', 'searched')
+		GROUP BY term_id
+	`
+
+	rows, err := database.DB.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get term popularity: %w", err)
+	}
+	defer rows.Close()
+
+	popularity := map[uuid.UUID]int64{}
+	for rows.Next() {
+		var termID uuid.UUID
+		var count int64
+		if err := rows.Scan(&termID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan term popularity: %w", err)
+		}
+		popularity[termID] = count
+	}
+
+	return popularity, nil
+}
+
+// GetCoViewedTerms returns the terms viewed by the same users as termID since `since` ("people who
+// viewed this also viewed"), with at least minCoViewers shared viewers, most shared first.
+// Viewers are users or their pseudonyms; views by excludeUserID (the anonymous default user) are
+// ignored. Only raw logs are considered, as daily rollups no longer know the user.
+func (r *UsageRepository) GetCoViewedTerms(ctx context.Context, termID uuid.UUID, since time.Time, excludeUserID uuid.UUID, minCoViewers int, limit int) ([]models.CoViewedTerm, error) {
+	query := `
+		WITH views AS (
+			SELECT DISTINCT term_id, COALESCE(user_id::text, user_pseudonym) AS viewer
+			FROM term_usage_logs
+			WHERE action = 'viewed' AND created_at >= $2 AND term_id IS NOT NULL
+			  AND COALESCE(user_id::text, user_pseudonym) IS NOT NULL
+			  AND user_id IS DISTINCT FROM $3
+		),
+		target AS (
+			SELECT viewer FROM views WHERE term_id = $1
+		)
+		SELECT v.term_id, COUNT(*) AS co_viewers,
+		       (SELECT COUNT(*) FROM target) AS term_viewers,
+		       (SELECT COUNT(*) FROM views o WHERE o.term_id = v.term_id) AS related_viewers
+		FROM views v
+		JOIN target t ON t.viewer = v.viewer
+		WHERE v.term_id <> $1
+		GROUP BY v.term_id
+		HAVING COUNT(*) >= $4
+		ORDER BY co_viewers DESC, v.term_id
+		LIMIT $5
+	`
+
+	rows, err := database.DB.Query(ctx, query, termID, since, excludeUserID, minCoViewers, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get co-viewed terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.CoViewedTerm{}
+	for rows.Next() {
+		var term models.CoViewedTerm
+		if err := rows.Scan(&term.TermID, &term.CoViewers, &term.TermViewers, &term.RelatedViewers); err != nil {
+			return nil, fmt.Errorf("failed to scan co-viewed term: %w", err)
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+// RollupDailyUsage aggregates raw usage logs for complete days before `before` into term_usage_daily.
+// The latest rolled-up day is recomputed so events written after the previous run are included.
+func (r *UsageRepository) RollupDailyUsage(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		INSERT INTO term_usage_daily (day, term_id, cluster, department, action, event_count)
+		SELECT created_at::date, term_id, COALESCE(cluster, ''), COALESCE(department, ''), action, COUNT(*)
+		FROM term_usage_logs
+		WHERE term_id IS NOT NULL
+		  AND created_at >= COALESCE((SELECT MAX(day) FROM term_usage_daily), '-infinity'::date)
+		  AND created_at < $1::date
+		GROUP BY 1, 2, 3, 4, 5
+		ON CONFLICT (day, term_id, cluster, department, action)
+		DO UPDATE SET event_count = EXCLUDED.event_count
+	`
+
+	result, err := database.DB.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up usage logs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PseudonymiseUsageLogs replaces user_id with a keyed SHA-256 pseudonym on raw logs older than `before`.
+// With an empty key the user reference is removed entirely.
+func (r *UsageRepository) PseudonymiseUsageLogs(ctx context.Context, before time.Time, key string) (int64, error) {
+	query := `
+		UPDATE term_usage_logs
+		SET user_pseudonym = CASE WHEN $2 = '' THEN NULL
+		                          ELSE encode(sha256(convert_to($2 || ':' || user_id::text, 'UTF8')), 'hex') END,
+		    user_id = NULL
+		WHERE user_id IS NOT NULL AND created_at < $1
+	`
+
+	result, err := database.DB.Exec(ctx, query, before, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pseudonymise usage logs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PurgeUsageLogs deletes raw usage logs older than `before`, keeping any day that has not been rolled up yet
+func (r *UsageRepository) PurgeUsageLogs(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM term_usage_logs
+		WHERE created_at < $1::date
+		  AND created_at < COALESCE((SELECT MAX(day) + 1 FROM term_usage_daily), '-infinity'::date)
+	`
+
+	result, err := database.DB.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge usage logs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PseudonymiseSearchLogs replaces user_id with a keyed SHA-256 pseudonym on search logs older than
+// `before`, in the same way as PseudonymiseUsageLogs
+func (r *UsageRepository) PseudonymiseSearchLogs(ctx context.Context, before time.Time, key string) (int64, error) {
+	query := `
+		UPDATE search_query_logs
+		SET user_pseudonym = CASE WHEN $2 = '' THEN NULL
+		                          ELSE encode(sha256(convert_to($2 || ':' || user_id::text, 'UTF8')), 'hex') END,
+		    user_id = NULL
+		WHERE user_id IS NOT NULL AND created_at < $1
+	`
+
+	result, err := database.DB.Exec(ctx, query, before, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pseudonymise search logs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PurgeSearchLogs deletes search logs older than `before`. Search logs have no rollup, so
+// analytics over them only cover the retention period.
+func (r *UsageRepository) PurgeSearchLogs(ctx context.Context, before time.Time) (int64, error) {
+	result, err := database.DB.Exec(ctx, `DELETE FROM search_query_logs WHERE created_at < $1::date`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge search logs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+var searchLogColumns = []string{"id", "query", "filters", "result_count", "clicked_term_id", "clicked_at", "user_id", "department", "created_at"}
+
+// CreateSearchLogs bulk-inserts search logs using COPY and returns the number of rows written.
+// As with usage logs, searches without a user are written as NULL.
+func (r *UsageRepository) CreateSearchLogs(ctx context.Context, logs []models.SearchLog) (int64, error) {
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	userIDs := []uuid.UUID{}
+	for _, entry := range logs {
+		if entry.UserID != nil {
+			userIDs = append(userIDs, *entry.UserID)
+		}
+	}
+	knownUsers, err := r.existingUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := make([][]interface{}, len(logs))
+	for i, entry := range logs {
+		filtersJSON, err := json.Marshal(entry.Filters)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal search filters: %w", err)
+		}
+		userID := entry.UserID
+		if userID != nil && !knownUsers[*userID] {
+			userID = nil
+		}
+		rows[i] = []interface{}{entry.ID, entry.Query, filtersJSON, entry.ResultCount, entry.ClickedTermID, entry.ClickedAt, userID, entry.Department, entry.CreatedAt}
+	}
+
+	count, err := database.DB.CopyFrom(ctx, pgx.Identifier{"search_query_logs"}, searchLogColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return count, fmt.Errorf("failed to copy search logs: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateSearchLog records a search query, its result count and click-through, if any. A clicked
+// term deleted meanwhile is written as NULL.
+func (r *UsageRepository) CreateSearchLog(ctx context.Context, searchLog *models.SearchLog) error {
+	filtersJSON, err := json.Marshal(searchLog.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search filters: %w", err)
+	}
+
+	query := `
+		INSERT INTO search_query_logs (id, query, filters, result_count, clicked_term_id, clicked_at, user_id, department, created_at)
+		VALUES ($1, $2, $3, $4, (SELECT id FROM terms WHERE id = $5), $6, (SELECT id FROM users WHERE id = $7), $8, $9)
+	`
+
+	_, err = database.DB.Exec(ctx, query,
+		searchLog.ID, searchLog.Query, filtersJSON, searchLog.ResultCount, searchLog.ClickedTermID, searchLog.ClickedAt, searchLog.UserID, searchLog.Department, searchLog.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create search log: %w", err)
+	}
+
+	return nil
+}
+
+// TermExists reports whether a term with the given ID exists
+func (r *UsageRepository) TermExists(ctx context.Context, termID uuid.UUID) (bool, error) {
+	var exists bool
+	err := database.DB.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM terms WHERE id = $1)`, termID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up term: %w", err)
+	}
+
+	return exists, nil
+}
+
+// RecordSearchClick stores the term a user clicked through to from a search. A term deleted
+// meanwhile is stored as NULL rather than failing the foreign key.
+func (r *UsageRepository) RecordSearchClick(ctx context.Context, searchID uuid.UUID, termID uuid.UUID) (*models.SearchLog, error) {
+	query := `
+		UPDATE search_query_logs
+		SET clicked_term_id = (SELECT id FROM terms WHERE id = $1), clicked_at = $2
+		WHERE id = $3
+		RETURNING id, query, result_count, clicked_term_id, clicked_at, user_id, department, created_at
+	`
+
+	searchLog := &models.SearchLog{}
+	err := database.DB.QueryRow(ctx, query, termID, time.Now(), searchID).Scan(
+		&searchLog.ID, &searchLog.Query, &searchLog.ResultCount, &searchLog.ClickedTermID, &searchLog.ClickedAt, &searchLog.UserID, &searchLog.Department, &searchLog.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("search not found")
+		}
+		return nil, fmt.Errorf("failed to record search click: %w", err)
+	}
+
+	return searchLog, nil
+}
+
+// GetZeroResultQueries retrieves searches since `since` that returned nothing, grouped by normalised query
+func (r *UsageRepository) GetZeroResultQueries(ctx context.Context, since time.Time, limit int) ([]models.ZeroResultQuery, error) {
+	query := `
+		SELECT lower(trim(query)) AS normalised_query, COUNT(*), COUNT(DISTINCT user_id), MAX(created_at)
+		FROM search_query_logs
+		WHERE result_count = 0 AND created_at >= $1
+		GROUP BY normalised_query
+		ORDER BY COUNT(*) DESC, MAX(created_at) DESC
+		LIMIT $2
+	`
+
+	rows, err := database.DB.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zero-result queries: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []models.ZeroResultQuery{}
+	for rows.Next() {
+		var q models.ZeroResultQuery
+		if err := rows.Scan(&q.Query, &q.SearchCount, &q.UniqueUsers, &q.LastSearchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan zero-result query: %w", err)
+		}
+		queries = append(queries, q)
+	}
+
+	return queries, nil
+}
+
+// UpsertTermReferences records that external systems reference terms. Existing references are
+// refreshed (last_seen_at).
+func (r *UsageRepository) UpsertTermReferences(ctx context.Context, refs []models.CreateTermReferenceRequest) ([]models.TermReference, error) {
+	termIDs := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		termIDs = append(termIDs, ref.TermID)
+	}
+
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Reject the whole batch if any term does not exist
+	existing := make(map[uuid.UUID]bool, len(termIDs))
+	rows, err := tx.Query(ctx, "SELECT id FROM terms WHERE id = ANY($1)", termIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check terms: %w", err)
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan term: %w", err)
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	for _, id := range termIDs {
+		if !existing[id] {
+			return nil, fmt.Errorf("term not found: %s", id)
+		}
+	}
+
+	query := `
+		INSERT INTO term_references (term_id, system, reference_type, reference_name, url, cluster, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (term_id, system, reference_name)
+		DO UPDATE SET
+			reference_type = EXCLUDED.reference_type,
+			url = COALESCE(EXCLUDED.url, term_references.url),
+			cluster = COALESCE(EXCLUDED.cluster, term_references.cluster),
+			last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id, term_id, system, reference_type, reference_name, url, cluster, first_seen_at, last_seen_at
+	`
+
+	now := time.Now()
+	references := make([]models.TermReference, 0, len(refs))
+	for _, ref := range refs {
+		referenceType := "other"
+		if ref.ReferenceType != nil && *ref.ReferenceType != "" {
+			referenceType = *ref.ReferenceType
+		}
+		referenceName := ""
+		if ref.ReferenceName != nil {
+			referenceName = *ref.ReferenceName
+		}
+
+		var tr models.TermReference
+		err := tx.QueryRow(ctx, query, ref.TermID, ref.System, referenceType, referenceName, ref.URL, ref.Cluster, now).Scan(
+			&tr.ID, &tr.TermID, &tr.System, &tr.ReferenceType, &tr.ReferenceName, &tr.URL, &tr.Cluster, &tr.FirstSeenAt, &tr.LastSeenAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert term reference: %w", err)
+		}
+		references = append(references, tr)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit term references: %w", err)
+	}
+
+	return references, nil
+}
+
+// GetTermReferenceReport retrieves visible terms with the external systems that reference them
+func (r *UsageRepository) GetTermReferenceReport(ctx context.Context, system *string, termID *uuid.UUID, userDepartment *string) ([]models.TermReferenceReport, error) {
+	where := "1=1"
+	args := []interface{}{}
+
+	if system != nil {
+		args = append(args, *system)
+		where += fmt.Sprintf(" AND tr.system = $%d", len(args))
+	}
+
+	if termID != nil {
+		args = append(args, *termID)
+		where += fmt.Sprintf(" AND tr.term_id = $%d", len(args))
+	}
+
+	if userDepartment != nil {
+		args = append(args, *userDepartment)
+		where += " AND " + termVisibilityClause("t.", len(args))
+	}
+
+	query := `
+		SELECT t.id, t.term, tr.id, tr.system, tr.reference_type, tr.reference_name, tr.url, tr.cluster, tr.first_seen_at, tr.last_seen_at
+		FROM term_references tr
+		JOIN terms t ON t.id = tr.term_id
+		WHERE ` + where + `
+		ORDER BY t.term ASC, t.id, tr.system ASC, tr.reference_name ASC
+	`
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get term references: %w", err)
+	}
+	defer rows.Close()
+
+	report := []models.TermReferenceReport{}
+	for rows.Next() {
+		var id uuid.UUID
+		var term string
+		var tr models.TermReference
+		err := rows.Scan(&id, &term, &tr.ID, &tr.System, &tr.ReferenceType, &tr.ReferenceName, &tr.URL, &tr.Cluster, &tr.FirstSeenAt, &tr.LastSeenAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan term reference: %w", err)
+		}
+		tr.TermID = id
+
+		if len(report) == 0 || report[len(report)-1].TermID != id {
+			report = append(report, models.TermReferenceReport{TermID: id, Term: term, Systems: []string{}})
+		}
+		entry := &report[len(report)-1]
+		if len(entry.Systems) == 0 || entry.Systems[len(entry.Systems)-1] != tr.System {
+			entry.Systems = append(entry.Systems, tr.System)
+		}
+		entry.References = append(entry.References, tr)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+// ahoCorasick is a multi-pattern matcher over runes. It finds all occurrences of all patterns in
+// one pass over the text, independent of the number of patterns.
+type ahoCorasick struct {
+	nodes   []acNode
+	lengths []int // rune length per pattern
+}
+
+type acNode struct {
+	next    map[rune]int32
+	fail    int32
+	outputs []int32 // patterns ending here, including those of fail links
+}
+
+// acMatch is an occurrence of pattern in the text, at rune offsets [start, end)
+type acMatch struct {
+	pattern int
+	start   int
+	end     int
+}
+
+// newAhoCorasick builds the automaton. Pattern i is reported as acMatch.pattern i.
+func newAhoCorasick(patterns [][]rune) *ahoCorasick {
+	ac := &ahoCorasick{nodes: []acNode{{next: map[rune]int32{}}}, lengths: make([]int, len(patterns))}
+
+	for i, pattern := range patterns {
+		ac.lengths[i] = len(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+		node := int32(0)
+		for _, r := range pattern {
+			child, ok := ac.nodes[node].next[r]
+			if !ok {
+				child = int32(len(ac.nodes))
+				ac.nodes = append(ac.nodes, acNode{next: map[rune]int32{}})
+				ac.nodes[node].next[r] = child
+			}
+			node = child
+		}
+		ac.nodes[node].outputs = append(ac.nodes[node].outputs, int32(i))
+	}
+
+	// Breadth-first, so fail links point to nodes whose outputs are already complete
+	queue := []int32{}
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range ac.nodes[node].next {
+			// The fail link is the longest proper suffix of child's path that is also a path
+			fail := ac.nodes[node].fail
+			for fail != 0 {
+				if _, ok := ac.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			if next, ok := ac.nodes[fail].next[r]; ok && next != child {
+				ac.nodes[child].fail = next
+			}
+			ac.nodes[child].outputs = append(ac.nodes[child].outputs, ac.nodes[ac.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return ac
+}
+
+// findAll returns every occurrence of every pattern in text, ordered by end offset
+func (ac *ahoCorasick) findAll(text []rune) []acMatch {
+	matches := []acMatch{}
+	node := int32(0)
+	for i, r := range text {
+		for {
+			if next, ok := ac.nodes[node].next[r]; ok {
+				node = next
+				break
+			}
+			if node == 0 {
+				break
+			}
+			node = ac.nodes[node].fail
+		}
+		for _, pattern := range ac.nodes[node].outputs {
+			matches = append(matches, acMatch{pattern: int(pattern), start: i + 1 - ac.lengths[pattern], end: i + 1})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// BackupFormat identifies backup archives in their manifest
+	BackupFormat = "clarityconnect-backup"
+
+	// BackupSchemaVersion is the version of the data layout backups are written in. Raise it when
+	// a change to the schema changes the records; restores accept archives from
+	// backupMinSchemaVersion up to this version.
+	BackupSchemaVersion    = 1
+	backupMinSchemaVersion = 1
+
+	backupManifestFile = "manifest.json"
+
+	// backupMaxEntryBytes bounds the uncompressed size of one archive file on restore
+	backupMaxEntryBytes = 1 << 30
+)
+
+// backupEntities are the entity files of an archive in restore order: records refer only to
+// records of earlier entities
+var backupEntities = []struct {
+	name    string
+	records func(data *models.BackupData) interface{} // pointer to the slice of the entity
+}{
+	{"clusters", func(d *models.BackupData) interface{} { return &d.Clusters }},
+	{"branding", func(d *models.BackupData) interface{} { return &d.Branding }},
+	{"terms", func(d *models.BackupData) interface{} { return &d.Terms }},
+	{"contexts", func(d *models.BackupData) interface{} { return &d.Contexts }},
+	{"examples", func(d *models.BackupData) interface{} { return &d.Examples }},
+	{"relationships", func(d *models.BackupData) interface{} { return &d.Relationships }},
+	{"aliases", func(d *models.BackupData) interface{} { return &d.Aliases }},
+	{"versions", func(d *models.BackupData) interface{} { return &d.Versions }},
+	{"proposals", func(d *models.BackupData) interface{} { return &d.Proposals }},
+	{"flags", func(d *models.BackupData) interface{} { return &d.Flags }},
+	{"onboarding_paths", func(d *models.BackupData) interface{} { return &d.OnboardingPaths }},
+}
+
+// BackupService writes the glossary to a portable archive and restores archives into another
+// database. An archive is a zip with a JSON Lines file per entity and a manifest holding the
+// schema version and each file's record count and SHA-256 checksum.
+//
+// Restored records get new IDs, with every reference between them remapped, so an archive can
+// be restored next to existing data or more than once. Users are not part of the archive:
+// references to users missing from the target database are cleared.
+type BackupService struct {
+	backupRepo *repository.BackupRepository
+}
+
+func NewBackupService() *BackupService {
+	return &BackupService{
+		backupRepo: repository.NewBackupRepository(),
+	}
+}
+
+// BackupArchive is a read and verified backup
+type BackupArchive struct {
+	Manifest models.BackupManifest
+	Data     *models.BackupData
+}
+
+// Backup writes an archive of the whole glossary to w
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) (*models.BackupManifest, error) {
+	data, err := s.backupRepo.LoadBackup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return writeBackup(w, data, time.Now().UTC())
+}
+
+func writeBackup(w io.Writer, data *models.BackupData, createdAt time.Time) (*models.BackupManifest, error) {
+	manifest := &models.BackupManifest{
+		Format:        BackupFormat,
+		SchemaVersion: BackupSchemaVersion,
+		CreatedAt:     createdAt,
+		Entities:      []models.BackupEntity{},
+	}
+
+	files := [][]byte{}
+	for _, entity := range backupEntities {
+		var out bytes.Buffer
+		encoder := json.NewEncoder(&out)
+		count := 0
+		records, err := json.Marshal(entity.records(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", entity.name, err)
+		}
+		var lines []json.RawMessage
+		if err := json.Unmarshal(records, &lines); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", entity.name, err)
+		}
+		for _, line := range lines {
+			if err := encoder.Encode(line); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", entity.name, err)
+			}
+			count++
+		}
+		sum := sha256.Sum256(out.Bytes())
+		manifest.Entities = append(manifest.Entities, models.BackupEntity{
+			Name:   entity.name,
+			File:   entity.name + ".jsonl",
+			Count:  count,
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		files = append(files, out.Bytes())
+	}
+
+	archive := zip.NewWriter(w)
+	add := func(name string, content []byte) error {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: createdAt})
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := file.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		return nil
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := add(backupManifestFile, manifestJSON); err != nil {
+		return nil, err
+	}
+	for i, entity := range manifest.Entities {
+		if err := add(entity.File, files[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadBackup reads and verifies an archive: the format and schema version of the manifest, and
+// the record count and checksum of every entity file. Entities an older archive lacks are empty.
+func ReadBackup(r io.ReaderAt, size int64) (*BackupArchive, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		file, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("backup archive has no %s", name)
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		defer content.Close()
+		data, err := io.ReadAll(io.LimitReader(content, backupMaxEntryBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(data) > backupMaxEntryBytes {
+			return nil, fmt.Errorf("%s is larger than %d bytes", name, backupMaxEntryBytes)
+		}
+		return data, nil
+	}
+
+	manifestJSON, err := readFile(backupManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	result := &BackupArchive{Data: &models.BackupData{}}
+	if err := json.Unmarshal(manifestJSON, &result.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if err := checkBackupCompatibility(&result.Manifest); err != nil {
+		return nil, err
+	}
+
+	entities := map[string]models.BackupEntity{}
+	for _, entity := range result.Manifest.Entities {
+		entities[entity.Name] = entity
+	}
+	known := map[string]bool{}
+	for _, entity := range backupEntities {
+		known[entity.name] = true
+		manifestEntity, ok := entities[entity.name]
+		if !ok {
+			continue
+		}
+		content, err := readFile(manifestEntity.File)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != manifestEntity.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s: the archive is damaged or was modified", manifestEntity.File)
+		}
+		count, err := decodeBackupLines(content, entity.records(result.Data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", manifestEntity.File, err)
+		}
+		if count != manifestEntity.Count {
+			return nil, fmt.Errorf("%s has %d records, the manifest lists %d", manifestEntity.File, count, manifestEntity.Count)
+		}
+	}
+	for _, entity := range result.Manifest.Entities {
+		if !known[entity.Name] {
+			return nil, fmt.Errorf("backup archive has unknown entity %q", entity.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// checkBackupCompatibility rejects archives of another format or of a schema version this
+// build cannot restore
+func checkBackupCompatibility(manifest *models.BackupManifest) error {
+	if manifest.Format != BackupFormat {
+		return fmt.Errorf("not a backup archive: manifest format is %q, expected %q", manifest.Format, BackupFormat)
+	}
+	if manifest.SchemaVersion > BackupSchemaVersion {
+		return fmt.Errorf("backup schema version %d is newer than the supported version %d; restore it with a newer release", manifest.SchemaVersion, BackupSchemaVersion)
+	}
+	if manifest.SchemaVersion < backupMinSchemaVersion {
+		return fmt.Errorf("backup schema version %d is older than the oldest supported version %d", manifest.SchemaVersion, backupMinSchemaVersion)
+	}
+	return nil
+}
+
+// decodeBackupLines appends each JSON line of content to the slice records points to and
+// returns the number of lines
+func decodeBackupLines(content []byte, records interface{}) (int, error) {
+	var lines bytes.Buffer
+	lines.WriteByte('[')
+	count := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), backupMaxEntryBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return 0, fmt.Errorf("line %d is not valid JSON", count+1)
+		}
+		if count > 0 {
+			lines.WriteByte(',')
+		}
+		lines.Write(line)
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	lines.WriteByte(']')
+	if err := json.Unmarshal(lines.Bytes(), records); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// BackupRestoreOptions control a restore
+type BackupRestoreOptions struct {
+	DryRun bool // run every statement, then roll back
+	Merge  bool // restore into a glossary that already has terms
+}
+
+// Restore writes a read archive into the database with new IDs. Unless merging, the glossary
+// must be empty, since restoring next to existing terms duplicates any term both have.
+func (s *BackupService) Restore(ctx context.Context, archive *BackupArchive, options BackupRestoreOptions) (*models.BackupRestoreResult, error) {
+	if !options.Merge {
+		count, err := s.backupRepo.CountTerms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("glossary already has %d terms; restore into an empty database or merge explicitly", count)
+		}
+	}
+
+	data, err := remapBackup(archive.Data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.backupRepo.RestoreBackup(ctx, data, !options.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	result.SchemaVersion = archive.Manifest.SchemaVersion
+	return result, nil
+}
+
+// remapBackup gives every record a new ID and rewrites the references between records. Term
+// references must resolve within the archive; onboarding paths drop term IDs that do not, as
+// their term lists are not kept consistent with deleted terms.
+func remapBackup(source *models.BackupData) (*models.BackupData, error) {
+	data := &models.BackupData{}
+	terms := map[uuid.UUID]uuid.UUID{}
+	contexts := map[uuid.UUID]uuid.UUID{}
+	term := func(id uuid.UUID, record string) (uuid.UUID, error) {
+		mapped, ok := terms[id]
+		if !ok {
+			return uuid.Nil, fmt.Errorf("%s refers to term %s, which is not in the archive", record, id)
+		}
+		return mapped, nil
+	}
+
+	for _, cluster := range source.Clusters {
+		cluster.ID = uuid.New()
+		data.Clusters = append(data.Clusters, cluster)
+	}
+	for _, config := range source.Branding {
+		config.ID = uuid.New()
+		data.Branding = append(data.Branding, config)
+	}
+	for _, t := range source.Terms {
+		if _, ok := terms[t.ID]; ok {
+			return nil, fmt.Errorf("term %s appears twice in the archive", t.ID)
+		}
+		terms[t.ID] = uuid.New()
+		t.ID = terms[t.ID]
+		t.Contexts, t.Examples, t.Relationships, t.Aliases = nil, nil, nil, nil
+		data.Terms = append(data.Terms, t)
+	}
+
+	var err error
+	for _, tc := range source.Contexts {
+		oldID := tc.ID
+		if tc.TermID, err = term(tc.TermID, "context "+oldID.String()); err != nil {
+			return nil, err
+		}
+		tc.ID = uuid.New()
+		contexts[oldID] = tc.ID
+		data.Contexts = append(data.Contexts, tc)
+	}
+	for _, ex := range source.Examples {
+		if ex.TermID, err = term(ex.TermID, "example "+ex.ID.String()); err != nil {
+			return nil, err
+		}
+		if ex.ContextID != nil {
+			mapped, ok := contexts[*ex.ContextID]
+			if !ok {
+				return nil, fmt.Errorf("example %s refers to context %s, which is not in the archive", ex.ID, *ex.ContextID)
+			}
+			ex.ContextID = &mapped
+		}
+		ex.ID = uuid.New()
+		data.Examples = append(data.Examples, ex)
+	}
+	for _, rel := range source.Relationships {
+		record := "relationship " + rel.ID.String()
+		if rel.TermID, err = term(rel.TermID, record); err != nil {
+			return nil, err
+		}
+		if rel.RelatedTermID, err = term(rel.RelatedTermID, record); err != nil {
+			return nil, err
+		}
+		rel.ID = uuid.New()
+		rel.RelatedTerm = nil
+		data.Relationships = append(data.Relationships, rel)
+	}
+	for _, alias := range source.Aliases {
+		if alias.TermID, err = term(alias.TermID, "alias "+alias.ID.String()); err != nil {
+			return nil, err
+		}
+		alias.ID = uuid.New()
+		data.Aliases = append(data.Aliases, alias)
+	}
+	for _, v := range source.Versions {
+		if v.TermID, err = term(v.TermID, "version "+v.ID.String()); err != nil {
+			return nil, err
+		}
+		// the snapshot names the term it was taken of
+		if v.TermData != nil {
+			snapshot := make(map[string]interface{}, len(v.TermData))
+			for key, value := range v.TermData {
+				snapshot[key] = value
+			}
+			if _, ok := snapshot["id"]; ok {
+				snapshot["id"] = v.TermID.String()
+			}
+			v.TermData = snapshot
+		}
+		v.ID = uuid.New()
+		data.Versions = append(data.Versions, v)
+	}
+	for _, p := range source.Proposals {
+		if p.TermID != nil {
+			mapped, err := term(*p.TermID, "proposal "+p.ID.String())
+			if err != nil {
+				return nil, err
+			}
+			p.TermID = &mapped
+		}
+		p.ID = uuid.New()
+		data.Proposals = append(data.Proposals, p)
+	}
+	for _, f := range source.Flags {
+		if f.TermID, err = term(f.TermID, "flag "+f.ID.String()); err != nil {
+			return nil, err
+		}
+		f.ID = uuid.New()
+		data.Flags = append(data.Flags, f)
+	}
+	for _, path := range source.OnboardingPaths {
+		termIDs := []uuid.UUID{}
+		for _, id := range path.TermIDs {
+			if mapped, ok := terms[id]; ok {
+				termIDs = append(termIDs, mapped)
+			}
+		}
+		path.TermIDs = termIDs
+		path.ID = uuid.New()
+		data.OnboardingPaths = append(data.OnboardingPaths, path)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// testBackupData returns an archive's records with a reference of every kind
+func testBackupData() *models.BackupData {
+	margin := uuid.New()
+	income := uuid.New()
+	retail := uuid.New()
+	version := 3
+	return &models.BackupData{
+		Clusters: []models.Cluster{{ID: uuid.New(), Name: "Retail"}},
+		Terms: []models.Term{
+			{ID: margin, Term: "Margin", BaseDefinition: "Income minus expense", Status: "approved", Contexts: []models.TermContext{{ID: retail}}},
+			{ID: income, Term: "Income", BaseDefinition: "Money received", Status: "draft"},
+		},
+		Contexts:      []models.TermContext{{ID: retail, TermID: margin, Cluster: strPtr("Retail"), ContextDefinition: "Retail margin"}},
+		Examples:      []models.TermExample{{ID: uuid.New(), TermID: margin, ContextID: &retail, ExampleText: "2%"}},
+		Relationships: []models.TermRelationship{{ID: uuid.New(), TermID: margin, RelatedTermID: income, RelationshipType: "related"}},
+		Aliases:       []models.TermAlias{{ID: uuid.New(), TermID: margin, Alias: "NIM", AliasType: "acronym"}},
+		Versions: []models.TermVersion{{
+			ID: uuid.New(), TermID: margin, VersionNumber: version,
+			TermData: map[string]interface{}{"id": margin.String(), "term": "Margin"},
+		}},
+		Proposals: []models.TermProposal{
+			{ID: uuid.New(), TermID: &income, ProposalType: "update", Status: "pending"},
+			{ID: uuid.New(), ProposalType: "create", Status: "pending"},
+		},
+		Flags:           []models.TermFlag{{ID: uuid.New(), TermID: income, FlagType: "outdated", Status: "open"}},
+		OnboardingPaths: []models.OnboardingPath{{ID: uuid.New(), Role: "Analyst", TermIDs: []uuid.UUID{income, uuid.New(), margin}, OrderIndex: &version}},
+		Branding:        []models.BrandingConfig{},
+	}
+}
+
+func TestRemapBackup(t *testing.T) {
+	source := testBackupData()
+	original := testBackupDataCopy(t, source)
+
+	data, err := remapBackup(source)
+	if err != nil {
+		t.Fatalf("remapBackup returned error: %v", err)
+	}
+	if !reflect.DeepEqual(source, original) {
+		t.Error("remapBackup modified its source")
+	}
+
+	oldIDs := map[uuid.UUID]bool{}
+	for _, term := range source.Terms {
+		oldIDs[term.ID] = true
+	}
+	oldIDs[source.Contexts[0].ID] = true
+	for _, id := range []uuid.UUID{
+		data.Clusters[0].ID, data.Contexts[0].ID, data.Examples[0].ID, data.Relationships[0].ID,
+		data.Aliases[0].ID, data.Versions[0].ID, data.Proposals[0].ID, data.Flags[0].ID, data.OnboardingPaths[0].ID,
+	} {
+		if id == uuid.Nil || oldIDs[id] {
+			t.Errorf("record ID %s was not replaced", id)
+		}
+	}
+
+	margin, income := data.Terms[0].ID, data.Terms[1].ID
+	if margin == source.Terms[0].ID || income == source.Terms[1].ID || margin == income {
+		t.Fatalf("term IDs were not replaced: %s, %s", margin, income)
+	}
+	if data.Terms[0].Contexts != nil {
+		t.Error("restored term keeps its nested contexts")
+	}
+
+	checks := []struct {
+		name      string
+		got, want uuid.UUID
+	}{
+		{"context term", data.Contexts[0].TermID, margin},
+		{"example term", data.Examples[0].TermID, margin},
+		{"example context", *data.Examples[0].ContextID, data.Contexts[0].ID},
+		{"relationship term", data.Relationships[0].TermID, margin},
+		{"relationship related term", data.Relationships[0].RelatedTermID, income},
+		{"alias term", data.Aliases[0].TermID, margin},
+		{"version term", data.Versions[0].TermID, margin},
+		{"proposal term", *data.Proposals[0].TermID, income},
+		{"flag term", data.Flags[0].TermID, income},
+	}
+	for _, check := range checks {
+		if check.got != check.want {
+			t.Errorf("%s = %s, want %s", check.name, check.got, check.want)
+		}
+	}
+
+	if got := data.Versions[0].TermData["id"]; got != margin.String() {
+		t.Errorf("version snapshot id = %v, want %s", got, margin)
+	}
+	if data.Proposals[1].TermID != nil {
+		t.Errorf("create proposal term = %s, want none", data.Proposals[1].TermID)
+	}
+	if got := data.OnboardingPaths[0].TermIDs; !reflect.DeepEqual(got, []uuid.UUID{income, margin}) {
+		t.Errorf("onboarding path terms = %v, want %v without the deleted term", got, []uuid.UUID{income, margin})
+	}
+}
+
+func TestRemapBackupErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(data *models.BackupData)
+		want   string
+	}{
+		{"duplicate term", func(d *models.BackupData) { d.Terms = append(d.Terms, d.Terms[0]) }, "appears twice"},
+		{"context of missing term", func(d *models.BackupData) { d.Contexts[0].TermID = uuid.New() }, "context"},
+		{"example of missing context", func(d *models.BackupData) { id := uuid.New(); d.Examples[0].ContextID = &id }, "refers to context"},
+		{"relationship to missing term", func(d *models.BackupData) { d.Relationships[0].RelatedTermID = uuid.New() }, "relationship"},
+		{"alias of missing term", func(d *models.BackupData) { d.Aliases[0].TermID = uuid.New() }, "alias"},
+		{"version of missing term", func(d *models.BackupData) { d.Versions[0].TermID = uuid.New() }, "version"},
+		{"proposal of missing term", func(d *models.BackupData) { id := uuid.New(); d.Proposals[0].TermID = &id }, "proposal"},
+		{"flag of missing term", func(d *models.BackupData) { d.Flags[0].TermID = uuid.New() }, "flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testBackupData()
+			tt.modify(data)
+			_, err := remapBackup(data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("remapBackup error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackupArchiveRoundTrip(t *testing.T) {
+	data := testBackupData()
+	var archive bytes.Buffer
+	manifest, err := writeBackup(&archive, data, time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("writeBackup returned error: %v", err)
+	}
+	if len(manifest.Entities) != len(backupEntities) || manifest.Entities[2].Name != "terms" || manifest.Entities[2].Count != 2 {
+		t.Errorf("manifest entities = %+v", manifest.Entities)
+	}
+
+	read, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("ReadBackup returned error: %v", err)
+	}
+	if read.Manifest.SchemaVersion != BackupSchemaVersion || !read.Manifest.CreatedAt.Equal(manifest.CreatedAt) {
+		t.Errorf("manifest read back as %+v", read.Manifest)
+	}
+	if !reflect.DeepEqual(testBackupDataCopy(t, read.Data), testBackupDataCopy(t, data)) {
+		t.Errorf("records read back differ:\n%+v\nwant %+v", read.Data, data)
+	}
+}
+
+func TestReadBackupErrors(t *testing.T) {
+	var archive bytes.Buffer
+	if _, err := writeBackup(&archive, testBackupData(), time.Now().UTC()); err != nil {
+		t.Fatalf("writeBackup returned error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(files map[string][]byte)
+		want   string
+	}{
+		{"modified entity file", func(f map[string][]byte) {
+			f["terms.jsonl"] = bytes.Replace(f["terms.jsonl"], []byte("Margin"), []byte("Margon"), 1)
+		}, "checksum mismatch for terms.jsonl"},
+		{"missing entity file", func(f map[string][]byte) { delete(f, "aliases.jsonl") }, "backup archive has no aliases.jsonl"},
+		{"missing manifest", func(f map[string][]byte) { delete(f, backupManifestFile) }, "backup archive has no manifest.json"},
+		{"other format", func(f map[string][]byte) { editManifest(t, f, func(m *models.BackupManifest) { m.Format = "other" }) }, "not a backup archive"},
+		{"newer schema", func(f map[string][]byte) {
+			editManifest(t, f, func(m *models.BackupManifest) { m.SchemaVersion = BackupSchemaVersion + 1 })
+		}, "newer than the supported version"},
+		{"older schema", func(f map[string][]byte) {
+			editManifest(t, f, func(m *models.BackupManifest) { m.SchemaVersion = backupMinSchemaVersion - 1 })
+		}, "older than the oldest supported version"},
+		{"wrong count", func(f map[string][]byte) {
+			editManifest(t, f, func(m *models.BackupManifest) { m.Entities[2].Count++ })
+		}, "terms.jsonl has 2 records, the manifest lists 3"},
+		{"unknown entity", func(f map[string][]byte) {
+			editManifest(t, f, func(m *models.BackupManifest) {
+				m.Entities = append(m.Entities, models.BackupEntity{Name: "widgets", File: "widgets.jsonl"})
+			})
+		}, `unknown entity "widgets"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := readZip(t, archive.Bytes())
+			tt.modify(files)
+			modified := writeZip(t, files)
+			_, err := ReadBackup(bytes.NewReader(modified), int64(len(modified)))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadBackup error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadBackup(bytes.NewReader([]byte("not a zip")), 9); err == nil || !strings.Contains(err.Error(), "invalid backup archive") {
+		t.Errorf("ReadBackup of a non-zip error = %v", err)
+	}
+}
+
+// testBackupDataCopy returns a deep copy of data as it reads back from JSON
+func testBackupDataCopy(t *testing.T, data *models.BackupData) *models.BackupData {
+	t.Helper()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := &models.BackupData{}
+	if err := json.Unmarshal(encoded, clone); err != nil {
+		t.Fatal(err)
+	}
+	return clone
+}
+
+func editManifest(t *testing.T, files map[string][]byte, edit func(m *models.BackupManifest)) {
+	t.Helper()
+	var manifest models.BackupManifest
+	if err := json.Unmarshal(files[backupManifestFile], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	edit(&manifest)
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[backupManifestFile] = encoded
+}
+
+func readZip(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		content, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name], err = io.ReadAll(content)
+		content.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+func writeZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"strings"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+)
+
+const (
+	// catalogGlossaryName names the glossary in catalogs that group terms in glossaries
+	catalogGlossaryName        = "BusinessGlossary"
+	catalogGlossaryDisplayName = "Business Glossary"
+	catalogGlossaryDescription = "Business terms exported from ClarityConnect"
+)
+
+// CatalogService exports the glossary to data catalog formats (dbt docs, OpenMetadata and
+// DataHub glossaries) and converts their export files to bulk imports.
+//
+// Each format carries what it has fields for natively; the rest of a term (code name, category,
+// status, compliance frameworks, visibility, contexts, examples) goes in the format's extension data (dbt
+// column meta, OpenMetadata extension, DataHub custom properties), so exports import back
+// without loss except where noted per format.
+type CatalogService struct {
+	termRepo *repository.TermRepository
+}
+
+func NewCatalogService() *CatalogService {
+	return &CatalogService{
+		termRepo: repository.NewTermRepository(),
+	}
+}
+
+// catalogContext is a term context in extension data
+type catalogContext struct {
+	Cluster            string   `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	System             string   `json:"system,omitempty" yaml:"system,omitempty"`
+	Product            string   `json:"product,omitempty" yaml:"product,omitempty"`
+	Definition         string   `json:"definition" yaml:"definition"`
+	BusinessRules      []string `json:"business_rules,omitempty" yaml:"business_rules,omitempty"`
+	ComplianceRequired bool     `json:"compliance_required,omitempty" yaml:"compliance_required,omitempty"`
+}
+
+func catalogContexts(term models.Term) []catalogContext {
+	contexts := []catalogContext{}
+	for _, tc := range term.Contexts {
+		contexts = append(contexts, catalogContext{
+			Cluster:            valueOrEmpty(tc.Cluster),
+			System:             valueOrEmpty(tc.System),
+			Product:            valueOrEmpty(tc.Product),
+			Definition:         tc.ContextDefinition,
+			BusinessRules:      tc.BusinessRules,
+			ComplianceRequired: tc.ComplianceRequired,
+		})
+	}
+	return contexts
+}
+
+func (e *glossaryExport) aliasNames(term models.Term) []string {
+	names := []string{}
+	for _, alias := range e.aliases[term.ID] {
+		names = append(names, alias.Alias)
+	}
+	return names
+}
+
+func (e *glossaryExport) exampleTexts(term models.Term) []string {
+	texts := []string{}
+	for _, example := range e.examples[term.ID] {
+		texts = append(texts, example.ExampleText)
+	}
+	return texts
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// catalogTerm is a term read from a catalog file, before conversion to import rows
+type catalogTerm struct {
+	subject       string   // source record, named in row errors
+	keys          []string // identifiers other records of the file refer to the term by
+	name          string
+	codeName      string
+	definition    string
+	category      string
+	status        string
+	tags          []string
+	frameworks    []string
+	visibility    string
+	departments   []string
+	aliases       []string
+	examples      []string
+	contexts      []catalogContext
+	relationships []catalogRelationship
+}
+
+// catalogRelationship refers to another term of the file by one of its keys
+type catalogRelationship struct {
+	relationshipType string
+	target           string
+}
+
+// catalogImportSource converts catalog terms to import sheets. Terms are referred to by code name,
+// or else name; relationships to terms outside the file are skipped.
+func catalogImportSource(terms []*catalogTerm) *ImportSource {
+	source := newImportSource()
+
+	byKey := map[string]*catalogTerm{}
+	ambiguous := map[string]bool{}
+	for _, term := range terms {
+		for _, key := range term.keys {
+			key = strings.ToLower(strings.TrimSpace(key))
+			if key == "" {
+				continue
+			}
+			if other, ok := byKey[key]; ok && other != term {
+				ambiguous[key] = true
+			}
+			byKey[key] = term
+		}
+	}
+
+	reference := func(term *catalogTerm) string {
+		if term.codeName != "" {
+			return term.codeName
+		}
+		return term.name
+	}
+
+	for _, term := range terms {
+		source.add("terms", term.subject, map[string]string{
+			"term":                  term.name,
+			"code_name":             term.codeName,
+			"base_definition":       term.definition,
+			"category":              term.category,
+			"status":                term.status,
+			"tags":                  strings.Join(uniqueFold(term.tags), importListSeparator),
+			"compliance_frameworks": strings.Join(uniqueFold(term.frameworks), importListSeparator),
+			"visibility_type":       term.visibility,
+			"allowed_departments":   strings.Join(uniqueFold(term.departments), importListSeparator),
+		})
+		ref := reference(term)
+		if ref == "" {
+			continue
+		}
+
+		for _, tc := range term.contexts {
+			compliance := "false"
+			if tc.ComplianceRequired {
+				compliance = "true"
+			}
+			source.add("contexts", term.subject, map[string]string{
+				"term":                ref,
+				"cluster":             tc.Cluster,
+				"system":              tc.System,
+				"product":             tc.Product,
+				"context_definition":  tc.Definition,
+				"business_rules":      strings.Join(tc.BusinessRules, importListSeparator),
+				"compliance_required": compliance,
+			})
+		}
+		for _, example := range uniqueFold(term.examples) {
+			source.add("examples", term.subject, map[string]string{"term": ref, "example_text": example})
+		}
+		for _, alias := range uniqueFold(term.aliases) {
+			if strings.EqualFold(alias, term.name) {
+				continue
+			}
+			aliasType := "other"
+			if isAcronymPattern(alias) {
+				aliasType = "acronym"
+			}
+			source.add("aliases", term.subject, map[string]string{"term": ref, "alias": alias, "alias_type": aliasType})
+		}
+
+		seen := map[string]bool{}
+		for _, rel := range term.relationships {
+			key := strings.ToLower(strings.TrimSpace(rel.target))
+			target, ok := byKey[key]
+			if !ok || ambiguous[key] || target == term || reference(target) == "" {
+				continue
+			}
+			if seen[rel.relationshipType+"\x00"+reference(target)] {
+				continue
+			}
+			seen[rel.relationshipType+"\x00"+reference(target)] = true
+			source.add("relationships", term.subject, map[string]string{"term": ref, "related_term": reference(target), "relationship_type": rel.relationshipType})
+		}
+	}
+
+	return source
+}
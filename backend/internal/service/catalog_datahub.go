@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dataHubTermURNPrefix = "urn:li:glossaryTerm:"
+	dataHubNodeURNPrefix = "urn:li:glossaryNode:"
+	dataHubTagURNPrefix  = "urn:li:tag:"
+)
+
+// dataHubProposal is a metadata change proposal in the JSON of DataHub's file source
+type dataHubProposal struct {
+	EntityType string        `json:"entityType"`
+	EntityURN  string        `json:"entityUrn"`
+	ChangeType string        `json:"changeType"`
+	AspectName string        `json:"aspectName"`
+	Aspect     dataHubAspect `json:"aspect"`
+}
+
+type dataHubAspect struct {
+	JSON interface{} `json:"json"`
+}
+
+type dataHubTermInfo struct {
+	Name             string            `json:"name,omitempty"`
+	Definition       string            `json:"definition"`
+	TermSource       string            `json:"termSource"`
+	ParentNode       string            `json:"parentNode,omitempty"`
+	CustomProperties map[string]string `json:"customProperties,omitempty"`
+}
+
+type dataHubNodeInfo struct {
+	Name       string `json:"name,omitempty"`
+	Definition string `json:"definition"`
+	ParentNode string `json:"parentNode,omitempty"`
+}
+
+// dataHubRelatedTerms: isRelatedTerms are the terms a term inherits from (its parents),
+// hasRelatedTerms those it contains (its children)
+type dataHubRelatedTerms struct {
+	IsRelatedTerms  []string `json:"isRelatedTerms,omitempty"`
+	HasRelatedTerms []string `json:"hasRelatedTerms,omitempty"`
+	RelatedTerms    []string `json:"relatedTerms,omitempty"`
+}
+
+type dataHubGlobalTags struct {
+	Tags []dataHubTagAssociation `json:"tags"`
+}
+
+type dataHubTagAssociation struct {
+	Tag string `json:"tag"`
+}
+
+type dataHubDeprecation struct {
+	Deprecated bool   `json:"deprecated"`
+	Note       string `json:"note"`
+	Actor      string `json:"actor"`
+}
+
+// dataHubListProperties are the custom properties holding JSON lists
+var dataHubListProperties = []string{"compliance_frameworks", "allowed_departments", "aliases", "contexts", "examples", "antonyms"}
+
+func dataHubNodeURN(category string) string {
+	return dataHubNodeURNPrefix + strings.Trim(dbtNameInvalid.ReplaceAllString(strings.ToLower(category), "_"), "_")
+}
+
+// ExportDataHub writes the glossary as DataHub metadata change proposals: a glossary node per
+// category holding its terms, and per term its info, related terms, tags and deprecation.
+// Relationships map to inherits (parent), contains (child) and related terms; the rest of the
+// term is in custom properties, lists as JSON.
+func (s *CatalogService) ExportDataHub(ctx context.Context, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+	return writeDataHub(glossary)
+}
+
+func writeDataHub(glossary *glossaryExport) ([]byte, error) {
+	termURN := func(id uuid.UUID) string { return dataHubTermURNPrefix + id.String() }
+	related := map[uuid.UUID]*dataHubRelatedTerms{}
+	antonyms := map[uuid.UUID][]string{}
+	for _, rel := range glossary.relationships {
+		terms, ok := related[rel.TermID]
+		if !ok {
+			terms = &dataHubRelatedTerms{}
+			related[rel.TermID] = terms
+		}
+		switch rel.RelationshipType {
+		case "parent":
+			terms.IsRelatedTerms = append(terms.IsRelatedTerms, termURN(rel.RelatedTermID))
+		case "child":
+			terms.HasRelatedTerms = append(terms.HasRelatedTerms, termURN(rel.RelatedTermID))
+		case "antonym":
+			antonyms[rel.TermID] = append(antonyms[rel.TermID], termURN(rel.RelatedTermID))
+		default:
+			terms.RelatedTerms = append(terms.RelatedTerms, termURN(rel.RelatedTermID))
+		}
+	}
+
+	proposals := []dataHubProposal{}
+	propose := func(entityType string, urn string, aspectName string, aspect interface{}) {
+		proposals = append(proposals, dataHubProposal{EntityType: entityType, EntityURN: urn, ChangeType: "UPSERT", AspectName: aspectName, Aspect: dataHubAspect{JSON: aspect}})
+	}
+
+	nodes := map[string]bool{}
+	for _, term := range glossary.terms {
+		if term.Category == nil || *term.Category == "" || nodes[dataHubNodeURN(*term.Category)] {
+			continue
+		}
+		nodes[dataHubNodeURN(*term.Category)] = true
+		propose("glossaryNode", dataHubNodeURN(*term.Category), "glossaryNodeInfo", dataHubNodeInfo{
+			Name:       *term.Category,
+			Definition: fmt.Sprintf("%s terms of the %s", *term.Category, catalogGlossaryDisplayName),
+		})
+	}
+
+	for _, term := range glossary.terms {
+		properties := map[string]string{}
+		if term.CodeName != nil && *term.CodeName != "" {
+			properties["code_name"] = *term.CodeName
+		}
+		if term.Category != nil && *term.Category != "" {
+			properties["category"] = *term.Category
+		}
+		if term.Status != "" {
+			properties["status"] = term.Status
+		}
+		if term.VisibilityType != nil && *term.VisibilityType != "" {
+			properties["visibility_type"] = *term.VisibilityType
+		}
+		lists := map[string]interface{}{
+			"compliance_frameworks": term.ComplianceFrameworks,
+			"allowed_departments":   term.AllowedDepartments,
+			"aliases":               glossary.aliasNames(term),
+			"contexts":              catalogContexts(term),
+			"examples":              glossary.exampleTexts(term),
+			"antonyms":              antonyms[term.ID],
+		}
+		for _, key := range dataHubListProperties {
+			encoded, err := json.Marshal(lists[key])
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s of term %s: %w", key, term.ID, err)
+			}
+			if string(encoded) != "null" && string(encoded) != "[]" {
+				properties[key] = string(encoded)
+			}
+		}
+
+		info := dataHubTermInfo{Name: term.Term, Definition: term.BaseDefinition, TermSource: "INTERNAL", CustomProperties: properties}
+		if term.Category != nil && *term.Category != "" {
+			info.ParentNode = dataHubNodeURN(*term.Category)
+		}
+		propose("glossaryTerm", termURN(term.ID), "glossaryTermInfo", info)
+
+		if terms, ok := related[term.ID]; ok && (len(terms.IsRelatedTerms) > 0 || len(terms.HasRelatedTerms) > 0 || len(terms.RelatedTerms) > 0) {
+			propose("glossaryTerm", termURN(term.ID), "glossaryRelatedTerms", terms)
+		}
+		if len(term.Tags) > 0 {
+			tags := dataHubGlobalTags{}
+			for _, tag := range term.Tags {
+				tags.Tags = append(tags.Tags, dataHubTagAssociation{Tag: dataHubTagURNPrefix + tag})
+			}
+			propose("glossaryTerm", termURN(term.ID), "globalTags", tags)
+		}
+		if term.Status == "deprecated" {
+			propose("glossaryTerm", termURN(term.ID), "deprecation", dataHubDeprecation{Deprecated: true, Note: "", Actor: "urn:li:corpuser:datahub"})
+		}
+	}
+
+	data, err := json.MarshalIndent(proposals, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode DataHub glossary: %w", err)
+	}
+	return data, nil
+}
+
+// ReadDataHub converts the glossary terms of a DataHub JSON file to an import. It reads metadata
+// change proposals (with aspects as {"json": ...}, serialized {"value": ...} or inline) and
+// metadata change events with glossary term and node snapshots. Terms without a category property
+// get the name of their glossary node as category. Row subjects are term URNs.
+func ReadDataHub(data []byte) (*ImportSource, error) {
+	var records []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid DataHub file, expected a JSON array of metadata changes: %w", err)
+	}
+
+	aspects := map[string]map[string]json.RawMessage{}
+	order := []string{}
+	setAspect := func(urn string, name string, value json.RawMessage) {
+		if _, ok := aspects[urn]; !ok {
+			aspects[urn] = map[string]json.RawMessage{}
+			order = append(order, urn)
+		}
+		aspects[urn][name] = value
+	}
+
+	for _, record := range records {
+		if snapshot, ok := record["proposedSnapshot"]; ok {
+			if err := readDataHubSnapshot(snapshot, setAspect); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var urn, name string
+		json.Unmarshal(record["entityUrn"], &urn)
+		json.Unmarshal(record["aspectName"], &name)
+		if urn == "" || name == "" || record["aspect"] == nil {
+			continue
+		}
+		var wrapped struct {
+			JSON  json.RawMessage `json:"json"`
+			Value *string         `json:"value"`
+		}
+		if err := json.Unmarshal(record["aspect"], &wrapped); err != nil {
+			return nil, fmt.Errorf("invalid %s aspect of %s: %w", name, urn, err)
+		}
+		switch {
+		case wrapped.JSON != nil:
+			setAspect(urn, name, wrapped.JSON)
+		case wrapped.Value != nil:
+			setAspect(urn, name, json.RawMessage(*wrapped.Value))
+		default:
+			setAspect(urn, name, record["aspect"])
+		}
+	}
+
+	nodeNames := map[string]string{}
+	for urn, named := range aspects {
+		var info dataHubNodeInfo
+		if raw, ok := named["glossaryNodeInfo"]; ok && json.Unmarshal(raw, &info) == nil {
+			nodeNames[urn] = info.Name
+			if info.Name == "" {
+				nodeNames[urn] = strings.TrimPrefix(urn, dataHubNodeURNPrefix)
+			}
+		}
+	}
+
+	terms := []*catalogTerm{}
+	for _, urn := range order {
+		raw, ok := aspects[urn]["glossaryTermInfo"]
+		if !strings.HasPrefix(urn, dataHubTermURNPrefix) || !ok {
+			continue
+		}
+		var info dataHubTermInfo
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return nil, fmt.Errorf("invalid glossaryTermInfo of %s: %w", urn, err)
+		}
+
+		term := &catalogTerm{
+			subject:    urn,
+			keys:       []string{urn},
+			name:       strings.TrimSpace(info.Name),
+			definition: info.Definition,
+			codeName:   info.CustomProperties["code_name"],
+			category:   info.CustomProperties["category"],
+			status:     info.CustomProperties["status"],
+			visibility: info.CustomProperties["visibility_type"],
+		}
+		if term.name == "" {
+			term.name = strings.TrimPrefix(urn, dataHubTermURNPrefix)
+		}
+		if term.category == "" {
+			term.category = nodeNames[info.ParentNode]
+		}
+
+		var frameworks, aliases, examples, antonyms []string
+		lists := map[string]interface{}{
+			"compliance_frameworks": &frameworks,
+			"allowed_departments":   &term.departments,
+			"aliases":               &aliases,
+			"contexts":              &term.contexts,
+			"examples":              &examples,
+			"antonyms":              &antonyms,
+		}
+		for _, key := range dataHubListProperties {
+			value, ok := info.CustomProperties[key]
+			if !ok || value == "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(value), lists[key]); err != nil {
+				return nil, fmt.Errorf("invalid %s custom property of %s, expected a JSON list", key, urn)
+			}
+		}
+		term.frameworks, term.aliases, term.examples = frameworks, aliases, examples
+
+		var relatedTerms dataHubRelatedTerms
+		if raw, ok := aspects[urn]["glossaryRelatedTerms"]; ok {
+			if err := json.Unmarshal(raw, &relatedTerms); err != nil {
+				return nil, fmt.Errorf("invalid glossaryRelatedTerms of %s: %w", urn, err)
+			}
+		}
+		relationships := []struct {
+			relationshipType string
+			targets          []string
+		}{{"parent", relatedTerms.IsRelatedTerms}, {"child", relatedTerms.HasRelatedTerms}, {"related", relatedTerms.RelatedTerms}, {"antonym", antonyms}}
+		for _, group := range relationships {
+			for _, target := range group.targets {
+				term.relationships = append(term.relationships, catalogRelationship{relationshipType: group.relationshipType, target: target})
+			}
+		}
+
+		var tags dataHubGlobalTags
+		if raw, ok := aspects[urn]["globalTags"]; ok && json.Unmarshal(raw, &tags) == nil {
+			for _, tag := range tags.Tags {
+				term.tags = append(term.tags, strings.TrimPrefix(tag.Tag, dataHubTagURNPrefix))
+			}
+		}
+		var deprecation dataHubDeprecation
+		if raw, ok := aspects[urn]["deprecation"]; ok && term.status == "" && json.Unmarshal(raw, &deprecation) == nil && deprecation.Deprecated {
+			term.status = "deprecated"
+		}
+
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("no glossary terms found")
+	}
+	return catalogImportSource(terms), nil
+}
+
+// readDataHubSnapshot reads the aspects of a metadata change event snapshot, such as
+// {"com.linkedin.pegasus2avro.metadata.snapshot.GlossaryTermSnapshot": {"urn": ..., "aspects": [...]}}.
+// Aspect names are derived from their record names (GlossaryTermInfo is glossaryTermInfo).
+func readDataHubSnapshot(raw json.RawMessage, setAspect func(urn string, name string, value json.RawMessage)) error {
+	var snapshots map[string]struct {
+		URN     string                       `json:"urn"`
+		Aspects []map[string]json.RawMessage `json:"aspects"`
+	}
+	if err := json.Unmarshal(raw, &snapshots); err != nil {
+		return fmt.Errorf("invalid DataHub snapshot: %w", err)
+	}
+	for _, snapshot := range snapshots {
+		for _, aspect := range snapshot.Aspects {
+			for recordName, value := range aspect {
+				name := recordName[strings.LastIndex(recordName, ".")+1:]
+				if name == "" {
+					continue
+				}
+				setAspect(snapshot.URN, strings.ToLower(name[:1])+name[1:], value)
+			}
+		}
+	}
+	return nil
+}
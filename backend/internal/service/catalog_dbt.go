@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// DBTDefaultModel is the model whose columns the schema.yml export describes by default
+const DBTDefaultModel = "glossary"
+
+var (
+	dbtDocsBlock    = regexp.MustCompile(`(?s)\{%-?\s*docs\s+([A-Za-z0-9_]+)\s*-?%\}(.*?)\{%-?\s*enddocs\s*-?%\}`)
+	dbtDocReference = regexp.MustCompile(`^\{\{\s*doc\(\s*['"]([A-Za-z0-9_]+)['"]\s*\)\s*\}\}$`)
+	dbtNameInvalid  = regexp.MustCompile(`[^a-z0-9_]+`)
+
+	// dbtJinjaEscaper writes Jinja delimiters in docs as expressions printing them, so a definition
+	// can't end its docs block early or run template code; dbtJinjaUnescaper renders them back
+	dbtJinjaEscaper   = strings.NewReplacer("{{", `{{ "{{" }}`, "{%", `{{ "{%" }}`, "{#", `{{ "{#" }}`)
+	dbtJinjaUnescaper = strings.NewReplacer(`{{ "{{" }}`, "{{", `{{ "{%" }}`, "{%", `{{ "{#" }}`, "{#")
+)
+
+// dbtSchema is the part of a dbt properties file (schema.yml) describing columns
+type dbtSchema struct {
+	Version   int         `yaml:"version"`
+	Models    []dbtModel  `yaml:"models,omitempty"`
+	Seeds     []dbtModel  `yaml:"seeds,omitempty"`
+	Snapshots []dbtModel  `yaml:"snapshots,omitempty"`
+	Sources   []dbtSource `yaml:"sources,omitempty"`
+}
+
+type dbtSource struct {
+	Name   string     `yaml:"name"`
+	Tables []dbtModel `yaml:"tables,omitempty"`
+}
+
+type dbtModel struct {
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description,omitempty"`
+	Columns     []dbtColumn `yaml:"columns,omitempty"`
+}
+
+type dbtColumn struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Meta        *dbtMeta `yaml:"meta,omitempty"`
+}
+
+// dbtMeta keeps the glossary data under its own key, next to any other meta of the column
+type dbtMeta struct {
+	Glossary *dbtGlossaryMeta `yaml:"glossary,omitempty"`
+}
+
+type dbtGlossaryMeta struct {
+	Term                 string               `yaml:"term,omitempty"`
+	Category             string               `yaml:"category,omitempty"`
+	Status               string               `yaml:"status,omitempty"`
+	ComplianceFrameworks []string             `yaml:"compliance_frameworks,omitempty"`
+	VisibilityType       string               `yaml:"visibility_type,omitempty"`
+	AllowedDepartments   []string             `yaml:"allowed_departments,omitempty"`
+	Aliases              []string             `yaml:"aliases,omitempty"`
+	Contexts             []catalogContext     `yaml:"contexts,omitempty"`
+	Examples             []string             `yaml:"examples,omitempty"`
+	Relationships        []dbtRelationshipRef `yaml:"relationships,omitempty"`
+}
+
+// dbtRelationshipRef refers to the related term by its column (code) name
+type dbtRelationshipRef struct {
+	Type string `yaml:"type"`
+	Term string `yaml:"term"`
+}
+
+// dbtDocNames names the docs block of each term with a code name after the code name, made a
+// valid dbt identifier; clashing names get a numeric suffix
+func dbtDocNames(terms []models.Term) map[uuid.UUID]string {
+	names := map[uuid.UUID]string{}
+	used := map[string]bool{}
+	for _, term := range terms {
+		if term.CodeName == nil || strings.TrimSpace(*term.CodeName) == "" {
+			continue
+		}
+		name := strings.Trim(dbtNameInvalid.ReplaceAllString(strings.ToLower(*term.CodeName), "_"), "_")
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			name = "term_" + name
+		}
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = name + "_" + strconv.Itoa(i)
+		}
+		used[unique] = true
+		names[term.ID] = unique
+	}
+	return names
+}
+
+// ExportDBTDocs writes a dbt docs block with the base definition of each term that has a code
+// name, named after the code name. Jinja delimiters in definitions are escaped.
+func (s *CatalogService) ExportDBTDocs(ctx context.Context, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+	return writeDBTDocs(glossary)
+}
+
+func writeDBTDocs(glossary *glossaryExport) ([]byte, error) {
+	docNames := dbtDocNames(glossary.terms)
+	var out bytes.Buffer
+	for _, term := range glossary.terms {
+		name, ok := docNames[term.ID]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&out, "{%% docs %s %%}\n%s\n{%% enddocs %%}\n\n", name, dbtJinjaEscaper.Replace(strings.TrimSpace(term.BaseDefinition)))
+	}
+	return out.Bytes(), nil
+}
+
+// ExportDBTSchema writes a schema.yml describing a column per term code name in the model. Column
+// descriptions refer to the docs blocks of ExportDBTDocs; the rest of the term is in meta.glossary.
+func (s *CatalogService) ExportDBTSchema(ctx context.Context, model string, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+	return writeDBTSchema(glossary, model)
+}
+
+func writeDBTSchema(glossary *glossaryExport, model string) ([]byte, error) {
+	docNames := dbtDocNames(glossary.terms)
+	relationships := map[uuid.UUID][]dbtRelationshipRef{}
+	for _, rel := range glossary.relationships {
+		if related := glossary.byID[rel.RelatedTermID]; related.CodeName != nil && *related.CodeName != "" {
+			relationships[rel.TermID] = append(relationships[rel.TermID], dbtRelationshipRef{Type: rel.RelationshipType, Term: *related.CodeName})
+		}
+	}
+
+	columns := []dbtColumn{}
+	for _, term := range glossary.terms {
+		name, ok := docNames[term.ID]
+		if !ok {
+			continue
+		}
+		meta := &dbtGlossaryMeta{
+			Term:                 term.Term,
+			Category:             valueOrEmpty(term.Category),
+			Status:               term.Status,
+			ComplianceFrameworks: term.ComplianceFrameworks,
+			VisibilityType:       valueOrEmpty(term.VisibilityType),
+			AllowedDepartments:   term.AllowedDepartments,
+			Aliases:              glossary.aliasNames(term),
+			Contexts:             catalogContexts(term),
+			Examples:             glossary.exampleTexts(term),
+			Relationships:        relationships[term.ID],
+		}
+		columns = append(columns, dbtColumn{
+			Name:        *term.CodeName,
+			Description: fmt.Sprintf(`{{ doc("%s") }}`, name),
+			Tags:        term.Tags,
+			Meta:        &dbtMeta{Glossary: meta},
+		})
+	}
+
+	schema := dbtSchema{
+		Version: 2,
+		Models:  []dbtModel{{Name: model, Description: catalogGlossaryDescription, Columns: columns}},
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(schema); err != nil {
+		return nil, fmt.Errorf("failed to encode schema.yml: %w", err)
+	}
+	encoder.Close()
+	return out.Bytes(), nil
+}
+
+// DBTProject collects the docs blocks and column descriptions of dbt files for an import
+type DBTProject struct {
+	docs     map[string]string
+	docNames []string
+	columns  []dbtImportColumn
+}
+
+type dbtImportColumn struct {
+	dbtColumn
+	subject string
+}
+
+func NewDBTProject() *DBTProject {
+	return &DBTProject{docs: map[string]string{}}
+}
+
+// AddDocs reads the docs blocks of a markdown file, with the Jinja delimiters escaped as
+// ExportDBTDocs does rendered back
+func (p *DBTProject) AddDocs(filename string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	blocks := dbtDocsBlock.FindAllStringSubmatch(string(data), -1)
+	if len(blocks) == 0 {
+		return fmt.Errorf("%s has no docs blocks", filename)
+	}
+	for _, block := range blocks {
+		if _, ok := p.docs[block[1]]; !ok {
+			p.docNames = append(p.docNames, block[1])
+		}
+		p.docs[block[1]] = dbtJinjaUnescaper.Replace(strings.TrimSpace(block[2]))
+	}
+	return nil
+}
+
+// Empty reports whether no columns or docs blocks were read
+func (p *DBTProject) Empty() bool {
+	return len(p.columns) == 0 && len(p.docs) == 0
+}
+
+// AddSchema reads the columns of the models, seeds, snapshots and source tables of a properties file
+func (p *DBTProject) AddSchema(filename string, r io.Reader) error {
+	var schema dbtSchema
+	if err := yaml.NewDecoder(r).Decode(&schema); err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	add := func(model string, columns []dbtColumn) {
+		for _, column := range columns {
+			p.columns = append(p.columns, dbtImportColumn{dbtColumn: column, subject: fmt.Sprintf("%s: %s.%s", filename, model, column.Name)})
+		}
+	}
+	for _, group := range [][]dbtModel{schema.Models, schema.Seeds, schema.Snapshots} {
+		for _, model := range group {
+			add(model.Name, model.Columns)
+		}
+	}
+	for _, source := range schema.Sources {
+		for _, table := range source.Tables {
+			add(source.Name+"."+table.Name, table.Columns)
+		}
+	}
+	return nil
+}
+
+// ConvertDBT converts a dbt project to an import. Each column is a term with the column name as code
+// name and its description, or the docs block it refers to, as definition; docs blocks no column
+// refers to are terms named after the block. Terms are named by meta.glossary.term, else keep the
+// name of the existing term with that code name, else get one made from the code name. The first
+// column of a name wins. Row subjects are "file: model.column" or "docs: block".
+func (s *CatalogService) ConvertDBT(ctx context.Context, project *DBTProject) (*ImportSource, error) {
+	existing, err := s.termRepo.ListTermNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, term := range existing {
+		if term.CodeName != nil {
+			names[strings.ToLower(*term.CodeName)] = term.Term
+		}
+	}
+	return project.convert(names), nil
+}
+
+// convert builds the import, with names holding the names of existing terms by code name
+func (p *DBTProject) convert(names map[string]string) *ImportSource {
+	nameFor := func(code string, meta *dbtGlossaryMeta) string {
+		if meta != nil && strings.TrimSpace(meta.Term) != "" {
+			return strings.TrimSpace(meta.Term)
+		}
+		if name, ok := names[strings.ToLower(code)]; ok {
+			return name
+		}
+		return humanizeCodeName(code)
+	}
+
+	terms := []*catalogTerm{}
+	seen := map[string]bool{}
+	referenced := map[string]bool{}
+	for _, column := range p.columns {
+		code := strings.TrimSpace(column.Name)
+		if code == "" || seen[strings.ToLower(code)] {
+			continue
+		}
+		seen[strings.ToLower(code)] = true
+
+		term := &catalogTerm{subject: column.subject, keys: []string{code}, codeName: code, tags: column.Tags}
+		term.definition = strings.TrimSpace(column.Description)
+		if ref := dbtDocReference.FindStringSubmatch(term.definition); ref != nil {
+			term.definition = p.docs[ref[1]]
+			referenced[ref[1]] = true
+		}
+
+		var meta *dbtGlossaryMeta
+		if column.Meta != nil {
+			meta = column.Meta.Glossary
+		}
+		term.name = nameFor(code, meta)
+		if meta != nil {
+			term.category = meta.Category
+			term.status = meta.Status
+			term.frameworks = meta.ComplianceFrameworks
+			term.visibility = meta.VisibilityType
+			term.departments = meta.AllowedDepartments
+			term.aliases = meta.Aliases
+			term.contexts = meta.Contexts
+			term.examples = meta.Examples
+			for _, rel := range meta.Relationships {
+				term.relationships = append(term.relationships, catalogRelationship{relationshipType: rel.Type, target: rel.Term})
+			}
+		}
+		terms = append(terms, term)
+	}
+
+	for _, name := range p.docNames {
+		if referenced[name] || seen[strings.ToLower(name)] {
+			continue
+		}
+		terms = append(terms, &catalogTerm{subject: "docs: " + name, keys: []string{name}, codeName: name, name: nameFor(name, nil), definition: p.docs[name]})
+	}
+
+	return catalogImportSource(terms)
+}
+
+// humanizeCodeName makes a term name from a code name: customer_id becomes Customer Id
+func humanizeCodeName(code string) string {
+	words := strings.FieldsFunc(code, func(r rune) bool { return r == '_' || r == '-' || r == '.' || r == ' ' })
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(unicode.ToUpper(runes[0])) + string(runes[1:])
+	}
+	return strings.Join(words, " ")
+}
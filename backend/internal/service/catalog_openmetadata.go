@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// openMetadataTagClassification holds the term tags in OpenMetadata
+const openMetadataTagClassification = "GlossaryTags"
+
+// openMetadataExport is a glossary with its terms in the shape of the OpenMetadata create
+// requests (CreateGlossary, CreateGlossaryTerm)
+type openMetadataExport struct {
+	Glossary openMetadataGlossary `json:"glossary"`
+	Terms    []openMetadataTerm   `json:"terms"`
+}
+
+type openMetadataGlossary struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description"`
+}
+
+type openMetadataTerm struct {
+	Name               string                  `json:"name"`
+	DisplayName        string                  `json:"displayName,omitempty"`
+	Description        string                  `json:"description"`
+	FullyQualifiedName string                  `json:"fullyQualifiedName,omitempty"`
+	Glossary           openMetadataReference   `json:"glossary,omitempty"`
+	Parent             openMetadataReference   `json:"parent,omitempty"`
+	Synonyms           []string                `json:"synonyms,omitempty"`
+	RelatedTerms       []openMetadataReference `json:"relatedTerms,omitempty"`
+	Tags               []openMetadataTag       `json:"tags,omitempty"`
+	Status             string                  `json:"status,omitempty"`
+	Extension          *openMetadataExtension  `json:"extension,omitempty"`
+}
+
+type openMetadataTag struct {
+	TagFQN    string `json:"tagFQN"`
+	Source    string `json:"source,omitempty"`
+	LabelType string `json:"labelType,omitempty"`
+	State     string `json:"state,omitempty"`
+}
+
+// openMetadataExtension holds the term data OpenMetadata has no field for, as custom properties
+type openMetadataExtension struct {
+	CodeName             string           `json:"codeName,omitempty"`
+	Category             string           `json:"category,omitempty"`
+	Status               string           `json:"status,omitempty"`
+	ComplianceFrameworks []string         `json:"complianceFrameworks,omitempty"`
+	VisibilityType       string           `json:"visibilityType,omitempty"`
+	AllowedDepartments   []string         `json:"allowedDepartments,omitempty"`
+	Contexts             []catalogContext `json:"contexts,omitempty"`
+	Examples             []string         `json:"examples,omitempty"`
+	Antonyms             []string         `json:"antonyms,omitempty"`
+}
+
+// openMetadataReference is a fully qualified name. It reads both the plain names of create
+// requests and the entity references of API responses.
+type openMetadataReference string
+
+func (r *openMetadataReference) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = openMetadataReference(name)
+		return nil
+	}
+	var entity struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Name               string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &entity); err != nil {
+		return fmt.Errorf("expected a name or entity reference")
+	}
+	*r = openMetadataReference(entity.FullyQualifiedName)
+	if entity.FullyQualifiedName == "" {
+		*r = openMetadataReference(entity.Name)
+	}
+	return nil
+}
+
+// openMetadataStatuses map term statuses to glossary term statuses; certified has no counterpart
+// and is kept in the extension
+var openMetadataStatuses = map[string]string{
+	"draft":      "Draft",
+	"approved":   "Approved",
+	"certified":  "Approved",
+	"deprecated": "Deprecated",
+}
+
+// openMetadataQuote quotes a name that contains dots, as fully qualified names do
+func openMetadataQuote(name string) string {
+	if strings.Contains(name, ".") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// ExportOpenMetadata writes the glossary and its terms as OpenMetadata glossary JSON. Parent
+// relationships make the term hierarchy; related, see also and synonym relationships are
+// related terms, aliases are synonyms and tags are in the GlossaryTags classification.
+func (s *CatalogService) ExportOpenMetadata(ctx context.Context, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+	return writeOpenMetadata(glossary)
+}
+
+func writeOpenMetadata(glossary *glossaryExport) ([]byte, error) {
+	parents := glossary.parents()
+	fqns := map[uuid.UUID]string{}
+	visiting := map[uuid.UUID]bool{}
+	var fqn func(id uuid.UUID) string
+	fqn = func(id uuid.UUID) string {
+		if name, ok := fqns[id]; ok {
+			return name
+		}
+		prefix := catalogGlossaryName
+		// a parent cycle is cut where it closes
+		if parent, ok := parents[id]; ok && !visiting[parent] {
+			visiting[id] = true
+			prefix = fqn(parent)
+			delete(visiting, id)
+		}
+		fqns[id] = prefix + "." + openMetadataQuote(glossary.byID[id].Term)
+		return fqns[id]
+	}
+
+	related := map[uuid.UUID][]openMetadataReference{}
+	antonyms := map[uuid.UUID][]string{}
+	for _, rel := range glossary.relationships {
+		switch rel.RelationshipType {
+		case "related", "see_also", "synonym":
+			related[rel.TermID] = append(related[rel.TermID], openMetadataReference(fqn(rel.RelatedTermID)))
+		case "antonym":
+			antonyms[rel.TermID] = append(antonyms[rel.TermID], fqn(rel.RelatedTermID))
+		}
+	}
+
+	export := openMetadataExport{
+		Glossary: openMetadataGlossary{Name: catalogGlossaryName, DisplayName: catalogGlossaryDisplayName, Description: catalogGlossaryDescription},
+		Terms:    []openMetadataTerm{},
+	}
+	for _, term := range glossary.terms {
+		omTerm := openMetadataTerm{
+			Name:               term.Term,
+			DisplayName:        term.Term,
+			Description:        term.BaseDefinition,
+			FullyQualifiedName: fqn(term.ID),
+			Glossary:           catalogGlossaryName,
+			Synonyms:           glossary.aliasNames(term),
+			RelatedTerms:       related[term.ID],
+			Status:             openMetadataStatuses[term.Status],
+			Extension: &openMetadataExtension{
+				CodeName:             valueOrEmpty(term.CodeName),
+				Category:             valueOrEmpty(term.Category),
+				Status:               term.Status,
+				ComplianceFrameworks: term.ComplianceFrameworks,
+				VisibilityType:       valueOrEmpty(term.VisibilityType),
+				AllowedDepartments:   term.AllowedDepartments,
+				Contexts:             catalogContexts(term),
+				Examples:             glossary.exampleTexts(term),
+				Antonyms:             antonyms[term.ID],
+			},
+		}
+		if parent, ok := parents[term.ID]; ok && strings.HasPrefix(omTerm.FullyQualifiedName, fqn(parent)+".") {
+			omTerm.Parent = openMetadataReference(fqn(parent))
+		}
+		for _, tag := range term.Tags {
+			omTerm.Tags = append(omTerm.Tags, openMetadataTag{
+				TagFQN:    openMetadataTagClassification + "." + openMetadataQuote(tag),
+				Source:    "Classification",
+				LabelType: "Manual",
+				State:     "Confirmed",
+			})
+		}
+		export.Terms = append(export.Terms, omTerm)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenMetadata glossary: %w", err)
+	}
+	return data, nil
+}
+
+// ReadOpenMetadata converts OpenMetadata glossary terms to an import. It reads the export of
+// ExportOpenMetadata, a list of terms, or a glossaryTerms API response ({"data": [...]}).
+// Synonyms become aliases, the parent a parent relationship and related terms related
+// relationships. Row subjects are fully qualified names.
+func ReadOpenMetadata(data []byte) (*ImportSource, error) {
+	var document struct {
+		Terms []openMetadataTerm `json:"terms"`
+		Data  []openMetadataTerm `json:"data"`
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &document.Terms); err != nil {
+			return nil, fmt.Errorf("invalid OpenMetadata glossary: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &document); err != nil {
+		return nil, fmt.Errorf("invalid OpenMetadata glossary: %w", err)
+	}
+
+	omTerms := append(document.Terms, document.Data...)
+	if len(omTerms) == 0 {
+		return nil, fmt.Errorf("no glossary terms found")
+	}
+
+	terms := []*catalogTerm{}
+	for _, omTerm := range omTerms {
+		name := strings.TrimSpace(omTerm.DisplayName)
+		if name == "" {
+			name = strings.TrimSpace(omTerm.Name)
+		}
+		fqn := omTerm.FullyQualifiedName
+		if fqn == "" && omTerm.Name != "" {
+			glossaryName := string(omTerm.Glossary)
+			if glossaryName == "" {
+				glossaryName = catalogGlossaryName
+			}
+			fqn = glossaryName + "." + openMetadataQuote(omTerm.Name)
+			if omTerm.Parent != "" {
+				fqn = string(omTerm.Parent) + "." + openMetadataQuote(omTerm.Name)
+			}
+		}
+
+		term := &catalogTerm{
+			subject:    fqn,
+			keys:       []string{fqn, omTerm.Name},
+			name:       name,
+			definition: omTerm.Description,
+			aliases:    omTerm.Synonyms,
+			status:     strings.ToLower(omTerm.Status),
+		}
+		switch term.status {
+		case "", "draft", "approved", "deprecated":
+		default:
+			term.status = "draft"
+		}
+		if omTerm.Parent != "" {
+			term.relationships = append(term.relationships, catalogRelationship{relationshipType: "parent", target: string(omTerm.Parent)})
+		}
+		for _, relatedTerm := range omTerm.RelatedTerms {
+			term.relationships = append(term.relationships, catalogRelationship{relationshipType: "related", target: string(relatedTerm)})
+		}
+		for _, tag := range omTerm.Tags {
+			name := strings.TrimPrefix(tag.TagFQN, openMetadataTagClassification+".")
+			term.tags = append(term.tags, strings.Trim(name, `"`))
+		}
+		if ext := omTerm.Extension; ext != nil {
+			term.codeName = ext.CodeName
+			term.category = ext.Category
+			if ext.Status != "" {
+				term.status = ext.Status
+			}
+			term.frameworks = ext.ComplianceFrameworks
+			term.visibility = ext.VisibilityType
+			term.departments = ext.AllowedDepartments
+			term.contexts = ext.Contexts
+			term.examples = ext.Examples
+			for _, antonym := range ext.Antonyms {
+				term.relationships = append(term.relationships, catalogRelationship{relationshipType: "antonym", target: antonym})
+			}
+		}
+		terms = append(terms, term)
+	}
+
+	return catalogImportSource(terms), nil
+}
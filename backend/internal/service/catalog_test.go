@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testCatalogTables are the import sheets testGlossaryExport reads back as from a catalog format.
+// Other terms refer to the earnings term by its code name, else its name; the formats without
+// synonyms import synonym relationships as related.
+func testCatalogTables(earningsCode string, synonym string) map[string][][]string {
+	earnings := earningsCode
+	if earnings == "" {
+		earnings = "Earnings à la carte 💶"
+	}
+	return map[string][][]string{
+		"terms": {
+			importColumns["terms"],
+			{`Net "Interest" Margin`, "NIM", "Finance", "Interest income minus expense,\nover assets.\tSee C:\\finance\\nim", "kpi|profit", "IFRS 9", "approved", "department_restricted", "Finance|Risk"},
+			{"Earnings à la carte 💶", earningsCode, "", `Ends with a quote"`, "", "", "draft", "", ""},
+		},
+		"contexts": {
+			importColumns["contexts"],
+			{"NIM", "Retail", "", "Loans", `Margin on """retail""" loans`, "Monthly|Net of fees", "true"},
+		},
+		"examples": {
+			importColumns["examples"],
+			{"NIM", "2.5% in Q1", ""},
+		},
+		"relationships": {
+			importColumns["relationships"],
+			{"NIM", earnings, "parent"},
+			{earnings, "NIM", synonym},
+		},
+		"aliases": {
+			importColumns["aliases"],
+			{"NIM", "NM", "acronym"},
+			{"NIM", "Net margine", "other"},
+		},
+	}
+}
+
+func checkCatalogImport(t *testing.T, source *ImportSource, want map[string][][]string) {
+	t.Helper()
+	for _, table := range source.Tables {
+		if !reflect.DeepEqual(table.Rows, want[table.Name]) {
+			t.Errorf("%s = %q\nwant %q", table.Name, table.Rows, want[table.Name])
+		}
+	}
+	v := newImportValidator(nil)
+	v.plan(source.Tables)
+	if len(v.errors) != 0 {
+		t.Errorf("round trip does not import: %+v", v.errors)
+	}
+}
+
+func TestOpenMetadataRoundTrip(t *testing.T) {
+	data, err := writeOpenMetadata(testGlossaryExport())
+	if err != nil {
+		t.Fatalf("writeOpenMetadata returned error: %v", err)
+	}
+	source, err := ReadOpenMetadata(data)
+	if err != nil {
+		t.Fatalf("ReadOpenMetadata returned error: %v\n%s", err, data)
+	}
+	checkCatalogImport(t, source, testCatalogTables("", "related"))
+
+	if subject := source.subjects["terms"][2]; subject != `BusinessGlossary.Earnings à la carte 💶.Net "Interest" Margin` {
+		t.Errorf("subject of terms row 2 = %q, want the fully qualified name under its parent", subject)
+	}
+}
+
+func TestDataHubRoundTrip(t *testing.T) {
+	data, err := writeDataHub(testGlossaryExport())
+	if err != nil {
+		t.Fatalf("writeDataHub returned error: %v", err)
+	}
+	source, err := ReadDataHub(data)
+	if err != nil {
+		t.Fatalf("ReadDataHub returned error: %v\n%s", err, data)
+	}
+	checkCatalogImport(t, source, testCatalogTables("", "related"))
+}
+
+func TestDBTRoundTrip(t *testing.T) {
+	glossary := testGlossaryExport()
+	// dbt only describes terms with a code name
+	glossary.terms[1].CodeName = strPtr("EARN")
+
+	docs, err := writeDBTDocs(glossary)
+	if err != nil {
+		t.Fatalf("writeDBTDocs returned error: %v", err)
+	}
+	schema, err := writeDBTSchema(glossary, DBTDefaultModel)
+	if err != nil {
+		t.Fatalf("writeDBTSchema returned error: %v", err)
+	}
+
+	project := NewDBTProject()
+	if err := project.AddDocs("glossary.md", bytes.NewReader(docs)); err != nil {
+		t.Fatalf("AddDocs returned error: %v\n%s", err, docs)
+	}
+	if err := project.AddSchema("schema.yml", bytes.NewReader(schema)); err != nil {
+		t.Fatalf("AddSchema returned error: %v\n%s", err, schema)
+	}
+	checkCatalogImport(t, project.convert(nil), testCatalogTables("EARN", "synonym"))
+}
+
+func TestDBTDocsEscapeJinja(t *testing.T) {
+	glossary := testGlossaryExport()
+	definition := "Ends early {% enddocs %} or runs {{ code }} {# hidden #} {%- raw -%}"
+	glossary.terms[0].BaseDefinition = definition
+
+	docs, err := writeDBTDocs(glossary)
+	if err != nil {
+		t.Fatalf("writeDBTDocs returned error: %v", err)
+	}
+	if blocks := dbtDocsBlock.FindAllStringSubmatch(string(docs), -1); len(blocks) != 1 || strings.Contains(blocks[0][2], "{% enddocs") {
+		t.Fatalf("docs blocks = %q, want one block holding the whole definition", blocks)
+	}
+
+	project := NewDBTProject()
+	if err := project.AddDocs("glossary.md", bytes.NewReader(docs)); err != nil {
+		t.Fatalf("AddDocs returned error: %v", err)
+	}
+	if got := project.docs["nim"]; got != definition {
+		t.Errorf("definition read back as %q, want %q", got, definition)
+	}
+}
@@ -3,88 +3,247 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"clarityconnect/internal/models"
 	"clarityconnect/internal/repository"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultGapBatchSize is the number of terms loaded per query and gaps written per COPY
+	defaultGapBatchSize = 500
+
+	// gapSampleSize is the number of detected gaps DetectGaps returns; the rest are paged
+	// through GET /gaps
+	gapSampleSize = 100
 )
 
 type GapDetectionService struct {
-	termRepo *repository.TermRepository
-	gapRepo  *repository.GapRepository
+	termRepo  *repository.TermRepository
+	gapRepo   *repository.GapRepository
+	workers   int
+	batchSize int
 }
 
 func NewGapDetectionService() *GapDetectionService {
 	return &GapDetectionService{
-		termRepo: repository.NewTermRepository(),
-		gapRepo:  repository.NewGapRepository(),
+		termRepo:  repository.NewTermRepository(),
+		gapRepo:   repository.NewGapRepository(),
+		workers:   envInt("GAP_DETECTION_WORKERS", runtime.NumCPU()),
+		batchSize: envInt("GAP_DETECTION_BATCH_SIZE", defaultGapBatchSize),
 	}
 }
 
-// DetectGaps scans all terms and identifies gaps
-func (s *GapDetectionService) DetectGaps(ctx context.Context) ([]models.GapAnalysis, error) {
-	var detectedGaps []models.GapAnalysis
+// DetectGaps scans all terms and identifies gaps.
+// Terms and their contexts are streamed in keyset-paginated batches, detectors run on a bounded
+// worker pool, and each scanned term's open gaps are replaced by the gaps found now, written with
+// COPY, so repeated runs don't pile up copies of the same gap. The scan stops as soon as ctx is cancelled.
+// Only the first gapSampleSize gaps are kept in memory and returned; stats.GapsDetected counts all.
+func (s *GapDetectionService) DetectGaps(ctx context.Context) ([]models.GapAnalysis, *models.GapDetectionStats, error) {
+	start := time.Now()
+	stats := &models.GapDetectionStats{Workers: s.workers, BatchSize: s.batchSize}
+	sample := []models.GapAnalysis{}
 
 	// Get all clusters from contexts
 	allClusters, err := s.gapRepo.GetAllClustersFromContexts(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get clusters: %w", err)
+		return nil, nil, fmt.Errorf("failed to get clusters: %w", err)
 	}
 
 	if len(allClusters) == 0 {
-		return detectedGaps, nil
+		return sample, stats, nil
 	}
 
-	// Get all terms
-	terms, _, err := s.termRepo.ListTerms(ctx, 10000, 0, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list terms: %w", err)
-	}
+	g, gctx := errgroup.WithContext(ctx)
+	termsCh := make(chan models.Term, s.batchSize)
+	gapsCh := make(chan scannedTerm, s.workers)
 
-	// Detect gaps for each term
-	for _, term := range terms {
-		// Get contexts for this term
-		contexts, err := s.termRepo.GetContextsByTermID(ctx, term.ID)
-		if err != nil {
-			continue
+	// Producer: stream terms with their contexts batch by batch
+	g.Go(func() error {
+		defer close(termsCh)
+
+		var afterID *uuid.UUID
+		for {
+			batch, err := s.termRepo.ListTermsWithContextsAfter(gctx, afterID, s.batchSize)
+			if err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+			atomic.AddInt64(&stats.Batches, 1)
+
+			for _, term := range batch {
+				select {
+				case termsCh <- term:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+
+			if len(batch) < s.batchSize {
+				return nil
+			}
+			lastID := batch[len(batch)-1].ID
+			afterID = &lastID
+		}
+	})
+
+	// Workers: run the detectors for each term
+	var workers sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		workers.Add(1)
+		g.Go(func() error {
+			defer workers.Done()
+
+			for term := range termsCh {
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+
+				atomic.AddInt64(&stats.TermsScanned, 1)
+				atomic.AddInt64(&stats.ContextsScanned, int64(len(term.Contexts)))
+
+				scanned := scannedTerm{termID: term.ID, gaps: s.detectTermGaps(term, allClusters)}
+				select {
+				case gapsCh <- scanned:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		workers.Wait()
+		close(gapsCh)
+	}()
+
+	// Writer: replace the scanned terms' open gaps in the database in COPY batches
+	g.Go(func() error {
+		pending := &gapBatch{}
+		flush := func() error {
+			written, err := s.gapRepo.ReplaceOpenGaps(gctx, pending.termIDs, pending.gaps)
+			if err != nil {
+				return err
+			}
+			stats.GapsWritten += written
+			stats.GapsDetected += int64(len(pending.gaps))
+			if room := gapSampleSize - len(sample); room > 0 {
+				if room > len(pending.gaps) {
+					room = len(pending.gaps)
+				}
+				sample = append(sample, pending.gaps[:room]...)
+			}
+			pending.reset()
+			return nil
 		}
 
-		// Group contexts by cluster
-		contextsByCluster := make(map[string][]models.TermContext)
-		for _, ctx := range contexts {
-			if ctx.Cluster != nil {
-				clusterName := *ctx.Cluster
-				contextsByCluster[clusterName] = append(contextsByCluster[clusterName], ctx)
+		for scanned := range gapsCh {
+			pending.add(scanned, time.Now())
+			if pending.full(s.batchSize) {
+				if err := flush(); err != nil {
+					return err
+				}
 			}
 		}
+		return flush()
+	})
 
-		// Detect missing contexts
-		missingContextGaps := s.detectMissingContexts(term.ID, allClusters, contextsByCluster)
-		detectedGaps = append(detectedGaps, missingContextGaps...)
+	err = g.Wait()
 
-		// Detect conflicting definitions
-		conflictGaps := s.detectConflictingDefinitions(term.ID, contextsByCluster)
-		detectedGaps = append(detectedGaps, conflictGaps...)
+	elapsed := time.Since(start)
+	stats.DurationMs = elapsed.Milliseconds()
+	if elapsed > 0 {
+		stats.TermsPerSecond = float64(stats.TermsScanned) / elapsed.Seconds()
+	}
 
-		// Detect outdated contexts
-		outdatedGaps := s.detectOutdatedContexts(term.ID, contexts)
-		detectedGaps = append(detectedGaps, outdatedGaps...)
+	if err != nil {
+		return nil, stats, fmt.Errorf("gap detection failed: %w", err)
 	}
 
-	// Save detected gaps to database
-	for i := range detectedGaps {
-		detectedGaps[i].ID = uuid.New()
-		detectedGaps[i].DetectedAt = time.Now()
-		if err := s.gapRepo.CreateGap(ctx, &detectedGaps[i]); err != nil {
-			// Log error but continue
-			continue
+	log.Printf("Gap detection scanned %d terms (%d contexts) in %d batches, wrote %d gaps in %s (%.0f terms/s, %d workers)",
+		stats.TermsScanned, stats.ContextsScanned, stats.Batches, stats.GapsWritten, elapsed, stats.TermsPerSecond, stats.Workers)
+
+	return sample, stats, nil
+}
+
+// scannedTerm is a term and the gaps the detectors found for it, possibly none
+type scannedTerm struct {
+	termID uuid.UUID
+	gaps   []models.GapAnalysis
+}
+
+// gapBatch collects scanned terms until their gaps are written. Terms without gaps are kept as
+// well, so writing the batch clears gaps of theirs that no longer apply.
+type gapBatch struct {
+	termIDs []uuid.UUID
+	gaps    []models.GapAnalysis
+}
+
+func (b *gapBatch) add(scanned scannedTerm, detectedAt time.Time) {
+	b.termIDs = append(b.termIDs, scanned.termID)
+	for _, gap := range scanned.gaps {
+		gap.ID = uuid.New()
+		gap.DetectedAt = detectedAt
+		b.gaps = append(b.gaps, gap)
+	}
+}
+
+func (b *gapBatch) full(size int) bool {
+	return len(b.termIDs) >= size || len(b.gaps) >= size
+}
+
+func (b *gapBatch) reset() {
+	b.termIDs = b.termIDs[:0]
+	b.gaps = b.gaps[:0]
+}
+
+// detectTermGaps runs every detector against a single term with its contexts loaded
+func (s *GapDetectionService) detectTermGaps(term models.Term, allClusters []string) []models.GapAnalysis {
+	var gaps []models.GapAnalysis
+
+	// Group contexts by cluster
+	contextsByCluster := make(map[string][]models.TermContext)
+	for _, tc := range term.Contexts {
+		if tc.Cluster != nil {
+			clusterName := *tc.Cluster
+			contextsByCluster[clusterName] = append(contextsByCluster[clusterName], tc)
 		}
 	}
 
-	return detectedGaps, nil
+	// Detect missing contexts
+	gaps = append(gaps, s.detectMissingContexts(term.ID, allClusters, contextsByCluster)...)
+
+	// Detect conflicting definitions
+	gaps = append(gaps, s.detectConflictingDefinitions(term.ID, contextsByCluster)...)
+
+	// Detect conflicting definitions across systems and products within a cluster
+	gaps = append(gaps, s.detectIntraClusterConflicts(term.ID, contextsByCluster)...)
+
+	// Detect outdated contexts
+	gaps = append(gaps, s.detectOutdatedContexts(term.ID, term.Contexts)...)
+
+	return gaps
+}
+
+// envInt reads a positive integer from the environment, falling back to def
+func envInt(key string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return def
 }
 
 // detectMissingContexts identifies terms missing contexts in certain clusters
@@ -116,6 +275,7 @@ func (s *GapDetectionService) detectMissingContexts(termID uuid.UUID, allCluster
 		gap := models.GapAnalysis{
 			TermID:          termID,
 			GapType:         "missing_context",
+			Dimension:       "cluster",
 			AffectedClusters: missingClusters,
 			Severity:        severity,
 			Description:     &desc,
@@ -134,10 +294,7 @@ func (s *GapDetectionService) detectConflictingDefinitions(termID uuid.UUID, con
 		return gaps
 	}
 
-	// Compare definitions across clusters
-	var conflictingClusters []string
 	clusterDefinitions := make(map[string]string)
-
 	for cluster, contexts := range contextsByCluster {
 		if len(contexts) > 0 {
 			// Use the most recent context definition
@@ -145,38 +302,8 @@ func (s *GapDetectionService) detectConflictingDefinitions(termID uuid.UUID, con
 		}
 	}
 
-	// Simple similarity check - if definitions are very different, flag as conflict
-	clusters := make([]string, 0, len(clusterDefinitions))
-	for cluster := range clusterDefinitions {
-		clusters = append(clusters, cluster)
-	}
-
-	for i := 0; i < len(clusters); i++ {
-		for j := i + 1; j < len(clusters); j++ {
-			cluster1 := clusters[i]
-			cluster2 := clusters[j]
-			def1 := clusterDefinitions[cluster1]
-			def2 := clusterDefinitions[cluster2]
-
-			similarity := calculateSimilarity(def1, def2)
-			// If similarity is below 0.5, consider it a conflict
-			if similarity < 0.5 && len(def1) > 20 && len(def2) > 20 {
-				conflictingClusters = append(conflictingClusters, cluster1, cluster2)
-			}
-		}
-	}
-
-	if len(conflictingClusters) > 0 {
-		// Remove duplicates
-		uniqueClusters := make(map[string]bool)
-		for _, cluster := range conflictingClusters {
-			uniqueClusters[cluster] = true
-		}
-		affectedClusters := make([]string, 0, len(uniqueClusters))
-		for cluster := range uniqueClusters {
-			affectedClusters = append(affectedClusters, cluster)
-		}
-
+	affectedClusters := findConflictingDefinitions(clusterDefinitions)
+	if len(affectedClusters) > 0 {
 		severity := "high"
 		if len(affectedClusters) == 2 {
 			severity = "medium"
@@ -186,6 +313,7 @@ func (s *GapDetectionService) detectConflictingDefinitions(termID uuid.UUID, con
 		gap := models.GapAnalysis{
 			TermID:          termID,
 			GapType:         "conflicting_definition",
+			Dimension:       "cluster",
 			AffectedClusters: affectedClusters,
 			Severity:        severity,
 			Description:     &desc,
@@ -196,6 +324,96 @@ func (s *GapDetectionService) detectConflictingDefinitions(termID uuid.UUID, con
 	return gaps
 }
 
+// detectIntraClusterConflicts identifies terms whose definitions disagree between systems or products of the same cluster
+func (s *GapDetectionService) detectIntraClusterConflicts(termID uuid.UUID, contextsByCluster map[string][]models.TermContext) []models.GapAnalysis {
+	var gaps []models.GapAnalysis
+
+	for cluster, contexts := range contextsByCluster {
+		for _, dimension := range []string{"system", "product"} {
+			conflicting := findConflictingDefinitions(latestDefinitionsBy(contexts, dimension))
+			if len(conflicting) == 0 {
+				continue
+			}
+
+			severity := "medium"
+			if len(conflicting) > 2 {
+				severity = "high"
+			}
+
+			desc := fmt.Sprintf("Term has conflicting definitions across %d %s(s) within the %s cluster", len(conflicting), dimension, cluster)
+			gap := models.GapAnalysis{
+				TermID:           termID,
+				GapType:          "conflicting_definition",
+				Dimension:        dimension,
+				AffectedClusters: []string{cluster},
+				Severity:         severity,
+				Description:      &desc,
+			}
+			if dimension == "system" {
+				gap.AffectedSystems = conflicting
+			} else {
+				gap.AffectedProducts = conflicting
+			}
+			gaps = append(gaps, gap)
+		}
+	}
+
+	return gaps
+}
+
+// latestDefinitionsBy maps each system or product to its most recent context definition.
+// Contexts are expected in created_at DESC order; contexts without a value for the dimension are skipped.
+func latestDefinitionsBy(contexts []models.TermContext, dimension string) map[string]string {
+	definitions := make(map[string]string)
+	for _, tc := range contexts {
+		key := repository.ContextDimensionValue(tc, dimension)
+		if key == nil || *key == "" {
+			continue
+		}
+		if _, exists := definitions[*key]; !exists {
+			definitions[*key] = tc.ContextDefinition
+		}
+	}
+	return definitions
+}
+
+// findConflictingDefinitions compares definitions pairwise and returns the sorted keys involved in a conflict
+func findConflictingDefinitions(definitions map[string]string) []string {
+	if len(definitions) < 2 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(definitions))
+	for key := range definitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conflicting := make(map[string]bool)
+	for i := 0; i < len(keys); i++ {
+		for j := i + 1; j < len(keys); j++ {
+			if definitionsConflict(definitions[keys[i]], definitions[keys[j]]) {
+				conflicting[keys[i]] = true
+				conflicting[keys[j]] = true
+			}
+		}
+	}
+
+	var result []string
+	for _, key := range keys {
+		if conflicting[key] {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// definitionsConflict reports whether two definitions are long enough to compare and too dissimilar to agree
+func definitionsConflict(def1, def2 string) bool {
+	// If similarity is below 0.5, consider it a conflict
+	return calculateSimilarity(def1, def2) < 0.5 && len(def1) > 20 && len(def2) > 20
+}
+
 // detectOutdatedContexts identifies contexts that haven't been updated in a long time
 func (s *GapDetectionService) detectOutdatedContexts(termID uuid.UUID, contexts []models.TermContext) []models.GapAnalysis {
 	var gaps []models.GapAnalysis
@@ -225,6 +443,7 @@ func (s *GapDetectionService) detectOutdatedContexts(termID uuid.UUID, contexts
 		gap := models.GapAnalysis{
 			TermID:          termID,
 			GapType:         "outdated",
+			Dimension:       "cluster",
 			AffectedClusters: affectedClusters,
 			Severity:        "low",
 			Description:     &desc,
@@ -289,3 +508,114 @@ func (s *GapDetectionService) CompareClusters(ctx context.Context, termID uuid.U
 	return s.gapRepo.GetTermClusterComparison(ctx, termID)
 }
 
+// CompareContexts compares a term's definitions across systems or products, optionally within a single cluster.
+// It returns the contexts grouped by the dimension and the values whose definitions disagree.
+func (s *GapDetectionService) CompareContexts(ctx context.Context, termID uuid.UUID, dimension string, cluster *string) (map[string][]models.TermContext, []string, error) {
+	comparison, err := s.gapRepo.GetTermContextComparison(ctx, termID, dimension, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	definitions := make(map[string]string)
+	for key, contexts := range comparison {
+		if len(contexts) > 0 {
+			definitions[key] = contexts[0].ContextDefinition
+		}
+	}
+
+	return comparison, findConflictingDefinitions(definitions), nil
+}
+
+// BuildDefinitionMatrix builds a term × system (or product) matrix for a cluster showing
+// which systems define each term and where their definitions disagree
+func (s *GapDetectionService) BuildDefinitionMatrix(ctx context.Context, cluster string, dimension string) (*models.DefinitionMatrix, error) {
+	if dimension != "system" && dimension != "product" {
+		return nil, fmt.Errorf("invalid dimension")
+	}
+
+	terms, err := s.gapRepo.GetClusterContexts(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildDefinitionMatrix(cluster, dimension, terms, nil), nil
+}
+
+// buildDefinitionMatrix builds a term × cluster, system or product matrix from terms with their
+// contexts in created_at DESC order. The columns are the given ones, or else every value the
+// contexts have for the dimension.
+func buildDefinitionMatrix(cluster string, dimension string, terms []models.Term, columns []string) *models.DefinitionMatrix {
+	matrix := &models.DefinitionMatrix{
+		Cluster:   cluster,
+		Dimension: dimension,
+		Columns:   columns,
+		Rows:      []models.DefinitionMatrixRow{},
+	}
+
+	// Collect the columns first so every row has a cell for every system
+	if matrix.Columns == nil {
+		matrix.Columns = []string{}
+		columnSet := make(map[string]bool)
+		for _, term := range terms {
+			for _, tc := range term.Contexts {
+				if key := repository.ContextDimensionValue(tc, dimension); key != nil && *key != "" {
+					columnSet[*key] = true
+				}
+			}
+		}
+		for column := range columnSet {
+			matrix.Columns = append(matrix.Columns, column)
+		}
+		sort.Strings(matrix.Columns)
+	}
+
+	for _, term := range terms {
+		definitions := latestDefinitionsBy(term.Contexts, dimension)
+		if len(definitions) == 0 {
+			continue
+		}
+
+		row := models.DefinitionMatrixRow{
+			TermID:      term.ID,
+			Term:        term.Term,
+			Cells:       make(map[string]models.DefinitionMatrixCell, len(matrix.Columns)),
+			Conflicting: findConflictingDefinitions(definitions),
+		}
+
+		conflicting := make(map[string]bool, len(row.Conflicting))
+		for _, key := range row.Conflicting {
+			conflicting[key] = true
+		}
+
+		for _, column := range matrix.Columns {
+			row.Cells[column] = models.DefinitionMatrixCell{Status: "missing"}
+		}
+		for _, tc := range term.Contexts {
+			key := repository.ContextDimensionValue(tc, dimension)
+			if key == nil || *key == "" {
+				continue
+			}
+			if cell := row.Cells[*key]; cell.Status != "missing" {
+				// Keep the most recent context only
+				continue
+			}
+
+			contextID := tc.ID
+			definition := tc.ContextDefinition
+			status := "defined"
+			if conflicting[*key] {
+				status = "conflicting"
+			}
+			row.Cells[*key] = models.DefinitionMatrixCell{
+				ContextID:  &contextID,
+				Definition: &definition,
+				Status:     status,
+			}
+		}
+
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	return matrix
+}
+
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestDetectTermGapsPerClusterConflicts(t *testing.T) {
+	s := &GapDetectionService{}
+	now := time.Now()
+	context := func(cluster, system, definition string) models.TermContext {
+		return models.TermContext{Cluster: strPtr(cluster), System: strPtr(system), ContextDefinition: definition, UpdatedAt: now}
+	}
+	term := models.Term{ID: uuid.New(), Contexts: []models.TermContext{
+		context("Retail", "Core", "Interest income minus interest expense over earning assets"),
+		context("Retail", "Ledger", "Revenue left after paying every supplier and each employee"),
+		context("Corporate", "Core", "Interest income minus interest expense over earning assets"),
+		context("Corporate", "Treasury", "Spread between funding cost and the rate charged to clients"),
+	}}
+
+	var clusters []string
+	for _, gap := range s.detectTermGaps(term, []string{"Corporate", "Retail"}) {
+		if gap.GapType == "conflicting_definition" && gap.Dimension == "system" {
+			clusters = append(clusters, gap.AffectedClusters...)
+		}
+	}
+	sort.Strings(clusters)
+	if want := []string{"Corporate", "Retail"}; !reflect.DeepEqual(clusters, want) {
+		t.Errorf("system conflicts found in %v, want one gap for each of %v", clusters, want)
+	}
+}
+
+func TestGapBatch(t *testing.T) {
+	withGaps, withoutGaps := uuid.New(), uuid.New()
+	detectedAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	found := []models.GapAnalysis{
+		{TermID: withGaps, GapType: "conflicting_definition", Dimension: "system", AffectedClusters: []string{"Retail"}},
+		{TermID: withGaps, GapType: "conflicting_definition", Dimension: "system", AffectedClusters: []string{"Corporate"}},
+	}
+
+	batch := &gapBatch{}
+	batch.add(scannedTerm{termID: withGaps, gaps: found}, detectedAt)
+	batch.add(scannedTerm{termID: withoutGaps}, detectedAt)
+
+	if want := []uuid.UUID{withGaps, withoutGaps}; !reflect.DeepEqual(batch.termIDs, want) {
+		t.Errorf("termIDs = %v, want %v including the term without gaps", batch.termIDs, want)
+	}
+	if len(batch.gaps) != 2 {
+		t.Fatalf("batch has %d gaps, want 2", len(batch.gaps))
+	}
+	for i, gap := range batch.gaps {
+		if gap.ID == uuid.Nil || !gap.DetectedAt.Equal(detectedAt) {
+			t.Errorf("gap %d has ID %s and detection time %s", i, gap.ID, gap.DetectedAt)
+		}
+		if gap.AffectedClusters[0] != found[i].AffectedClusters[0] {
+			t.Errorf("gap %d affects %v, want %v", i, gap.AffectedClusters, found[i].AffectedClusters)
+		}
+	}
+	if batch.gaps[0].ID == batch.gaps[1].ID {
+		t.Error("gaps share an ID")
+	}
+	if found[0].ID != uuid.Nil {
+		t.Error("add modified the detected gaps")
+	}
+
+	if batch.full(3) {
+		t.Error("batch of 2 terms and 2 gaps is full at 3")
+	}
+	if !batch.full(2) {
+		t.Error("batch of 2 terms and 2 gaps is not full at 2")
+	}
+	batch.reset()
+	if len(batch.termIDs) != 0 || len(batch.gaps) != 0 || batch.full(1) {
+		t.Errorf("reset left %d terms and %d gaps", len(batch.termIDs), len(batch.gaps))
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// glossaryExportBatchSize is the number of terms loaded per query by exports
+const glossaryExportBatchSize = 500
+
+// glossaryExport is the part of the glossary visible to a department, as the export formats
+// need it: terms with contexts in ID order, their aliases and examples, and the relationships
+// between them.
+type glossaryExport struct {
+	terms         []models.Term
+	byID          map[uuid.UUID]*models.Term
+	aliases       map[uuid.UUID][]models.TermAlias
+	examples      map[uuid.UUID][]models.TermExample
+	relationships []models.TermRelationship
+}
+
+// loadGlossaryExport reads the terms visible to the department. Relationships to terms that are
+// not visible are left out.
+func loadGlossaryExport(ctx context.Context, termRepo *repository.TermRepository, userDepartment *string) (*glossaryExport, error) {
+	export := &glossaryExport{
+		terms:    []models.Term{},
+		byID:     map[uuid.UUID]*models.Term{},
+		aliases:  map[uuid.UUID][]models.TermAlias{},
+		examples: map[uuid.UUID][]models.TermExample{},
+	}
+
+	var afterID *uuid.UUID
+	for {
+		batch, err := termRepo.ListTermsWithContextsAfter(ctx, afterID, glossaryExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, term := range batch {
+			if termVisibility(term).visibleTo(userDepartment) {
+				export.terms = append(export.terms, term)
+			}
+		}
+		afterID = &batch[len(batch)-1].ID
+	}
+	for i := range export.terms {
+		export.byID[export.terms[i].ID] = &export.terms[i]
+	}
+
+	aliases, err := termRepo.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if _, ok := export.byID[alias.TermID]; ok {
+			export.aliases[alias.TermID] = append(export.aliases[alias.TermID], alias)
+		}
+	}
+
+	examples, err := termRepo.ListExamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, example := range examples {
+		if _, ok := export.byID[example.TermID]; ok {
+			export.examples[example.TermID] = append(export.examples[example.TermID], example)
+		}
+	}
+
+	relationships, err := termRepo.ListRelationships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range relationships {
+		_, termOK := export.byID[rel.TermID]
+		_, relatedOK := export.byID[rel.RelatedTermID]
+		if termOK && relatedOK {
+			export.relationships = append(export.relationships, rel)
+		}
+	}
+
+	return export, nil
+}
+
+// parents returns the parent of each term that has one: the first parent relationship, or the
+// first term naming it as child
+func (e *glossaryExport) parents() map[uuid.UUID]uuid.UUID {
+	parents := map[uuid.UUID]uuid.UUID{}
+	for _, rel := range e.relationships {
+		child, parent := rel.TermID, rel.RelatedTermID
+		switch rel.RelationshipType {
+		case "parent":
+		case "child":
+			child, parent = parent, child
+		default:
+			continue
+		}
+		if _, ok := parents[child]; !ok {
+			parents[child] = parent
+		}
+	}
+	return parents
+}
+
+// withoutRestricted returns the export without department_restricted terms and the aliases,
+// examples and relationships that belong to them
+func (e *glossaryExport) withoutRestricted() *glossaryExport {
+	public := &glossaryExport{
+		terms:    []models.Term{},
+		byID:     map[uuid.UUID]*models.Term{},
+		aliases:  map[uuid.UUID][]models.TermAlias{},
+		examples: map[uuid.UUID][]models.TermExample{},
+	}
+	for _, term := range e.terms {
+		if term.VisibilityType == nil || *term.VisibilityType != "department_restricted" {
+			public.terms = append(public.terms, term)
+		}
+	}
+	for i := range public.terms {
+		id := public.terms[i].ID
+		public.byID[id] = &public.terms[i]
+		public.aliases[id] = e.aliases[id]
+		public.examples[id] = e.examples[id]
+	}
+	for _, rel := range e.relationships {
+		_, termOK := public.byID[rel.TermID]
+		_, relatedOK := public.byID[rel.RelatedTermID]
+		if termOK && relatedOK {
+			public.relationships = append(public.relationships, rel)
+		}
+	}
+	return public
+}
@@ -0,0 +1,630 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// importListSeparator separates the values of list cells (tags, business rules, ...)
+	importListSeparator = "|"
+
+	// importMaxUnzipBytes bounds the uncompressed size of an XLSX workbook
+	importMaxUnzipBytes = 256 << 20
+)
+
+// ImportSheets are the sheets of an import in the order they are applied. In a workbook they are
+// worksheets of these names; as CSV, one file each.
+var ImportSheets = []string{"terms", "contexts", "examples", "relationships", "aliases"}
+
+// importColumns are the columns each sheet may have, importRequiredColumns those it must have
+var importColumns = map[string][]string{
+	"terms":         {"term", "code_name", "category", "base_definition", "tags", "compliance_frameworks", "status", "visibility_type", "allowed_departments"},
+	"contexts":      {"term", "cluster", "system", "product", "context_definition", "business_rules", "compliance_required"},
+	"examples":      {"term", "example_text", "source"},
+	"relationships": {"term", "related_term", "relationship_type"},
+	"aliases":       {"term", "alias", "alias_type"},
+}
+
+var importRequiredColumns = map[string][]string{
+	"terms":         {"term"},
+	"contexts":      {"term", "context_definition"},
+	"examples":      {"term", "example_text"},
+	"relationships": {"term", "related_term", "relationship_type"},
+	"aliases":       {"term", "alias"},
+}
+
+// importMaxLengths mirror the VARCHAR sizes of the schema
+var importMaxLengths = map[string]int{
+	"term": 255, "code_name": 255, "category": 100, "cluster": 100, "system": 100, "product": 100, "alias": 255,
+}
+
+var (
+	importStatuses          = toSet([]string{"draft", "approved", "certified", "deprecated"})
+	importVisibilityTypes   = toSet([]string{"public", "department_restricted"})
+	importRelationshipTypes = toSet([]string{"synonym", "antonym", "related", "see_also", "parent", "child"})
+	importAliasTypes        = toSet([]string{"acronym", "abbreviation", "alternative_spelling", "other"})
+)
+
+// ImportTable is one sheet of an import, header row first
+type ImportTable struct {
+	Name string
+	Rows [][]string
+}
+
+// ReadImportCSV reads one sheet of an import from a CSV file
+func ReadImportCSV(name string, r io.Reader) (*ImportTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s CSV: %w", name, err)
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+
+	return &ImportTable{Name: name, Rows: rows}, nil
+}
+
+// ReadImportXLSX reads the import sheets of a workbook. Worksheet names are matched
+// case-insensitively; other worksheets (e.g. instructions) are ignored.
+func ReadImportXLSX(r io.Reader) ([]*ImportTable, error) {
+	workbook, err := excelize.OpenReader(r, excelize.Options{UnzipSizeLimit: importMaxUnzipBytes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+	defer workbook.Close()
+
+	tables := []*ImportTable{}
+	for _, sheet := range workbook.GetSheetList() {
+		name := strings.ToLower(strings.TrimSpace(sheet))
+		if _, ok := importColumns[name]; !ok {
+			continue
+		}
+		rows, err := workbook.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read worksheet %s: %w", sheet, err)
+		}
+		tables = append(tables, &ImportTable{Name: name, Rows: rows})
+	}
+
+	return tables, nil
+}
+
+// GlossaryImportService validates bulk imports of terms, contexts, examples, relationships and
+// aliases and applies them in one transaction.
+//
+// Terms are matched to existing terms by code_name, then by name (case-insensitive); matched
+// terms are updated, with empty cells leaving a value unchanged. Contexts are matched by term,
+// cluster, system and product. Other sheets refer to terms by name or code_name, from the
+// import or already in the glossary. List cells separate values with "|".
+type GlossaryImportService struct {
+	termRepo   *repository.TermRepository
+	importRepo *repository.ImportRepository
+}
+
+func NewGlossaryImportService() *GlossaryImportService {
+	return &GlossaryImportService{
+		termRepo:   repository.NewTermRepository(),
+		importRepo: repository.NewImportRepository(),
+	}
+}
+
+// importRow is one data row of a sheet, with cells addressed by column name
+type importRow struct {
+	sheet   string
+	number  int
+	columns map[string]int
+	cells   []string
+}
+
+func (r importRow) get(column string) string {
+	pos, ok := r.columns[column]
+	if !ok || pos >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[pos])
+}
+
+func (r importRow) optional(column string) *string {
+	if value := r.get(column); value != "" {
+		return &value
+	}
+	return nil
+}
+
+// list returns the values of a list cell, or nil when it is empty
+func (r importRow) list(column string) []string {
+	var values []string
+	for _, value := range strings.Split(r.get(column), importListSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// importValidator collects row errors and resolves term references
+type importValidator struct {
+	errors []models.ImportRowError
+
+	// Existing terms by lowercased name and code name
+	existingByName map[string][]uuid.UUID
+	existingByCode map[string][]uuid.UUID
+
+	// Terms of the import by lowercased name and code name; uuid.Nil marks an ambiguous key
+	imported map[string]uuid.UUID
+}
+
+// newImportValidator returns a validator resolving references against the existing terms
+func newImportValidator(existing []models.Term) *importValidator {
+	v := &importValidator{
+		errors:         []models.ImportRowError{},
+		existingByName: map[string][]uuid.UUID{},
+		existingByCode: map[string][]uuid.UUID{},
+		imported:       map[string]uuid.UUID{},
+	}
+	for _, term := range existing {
+		name := strings.ToLower(strings.TrimSpace(term.Term))
+		v.existingByName[name] = append(v.existingByName[name], term.ID)
+		if term.CodeName != nil && strings.TrimSpace(*term.CodeName) != "" {
+			code := strings.ToLower(strings.TrimSpace(*term.CodeName))
+			v.existingByCode[code] = append(v.existingByCode[code], term.ID)
+		}
+	}
+	return v
+}
+
+// plan validates the sheets and returns the changes they make. The plan is only complete when
+// no errors were collected.
+func (v *importValidator) plan(tables []*ImportTable) *models.GlossaryImportPlan {
+	sheets := v.readSheets(tables)
+	plan := &models.GlossaryImportPlan{}
+	plan.Terms = v.planTerms(sheets["terms"])
+	plan.Contexts = v.planContexts(sheets["contexts"])
+	plan.Examples = v.planExamples(sheets["examples"])
+	plan.Relationships = v.planRelationships(sheets["relationships"])
+	plan.Aliases = v.planAliases(sheets["aliases"])
+	return plan
+}
+
+func (v *importValidator) add(row importRow, column string, format string, args ...interface{}) {
+	v.errors = append(v.errors, models.ImportRowError{Sheet: row.sheet, Row: row.number, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkLength reports values longer than their database column
+func (v *importValidator) checkLength(row importRow, columns ...string) {
+	for _, column := range columns {
+		if max, ok := importMaxLengths[column]; ok && len([]rune(row.get(column))) > max {
+			v.add(row, column, "must be at most %d characters", max)
+		}
+	}
+}
+
+// resolve finds the term a cell refers to: an imported term, then an existing term by code
+// name, then by name
+func (v *importValidator) resolve(row importRow, column string) (uuid.UUID, bool) {
+	ref := row.get(column)
+	if ref == "" {
+		v.add(row, column, "is required")
+		return uuid.Nil, false
+	}
+	key := strings.ToLower(ref)
+
+	if id, ok := v.imported[key]; ok {
+		if id == uuid.Nil {
+			v.add(row, column, "%q matches more than one imported term", ref)
+			return uuid.Nil, false
+		}
+		return id, true
+	}
+	for _, candidates := range [][]uuid.UUID{v.existingByCode[key], v.existingByName[key]} {
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], true
+		default:
+			v.add(row, column, "%q matches %d existing terms; use the code name", ref, len(candidates))
+			return uuid.Nil, false
+		}
+	}
+
+	v.add(row, column, "unknown term %q", ref)
+	return uuid.Nil, false
+}
+
+// Import validates the sheets and, when they are free of errors, applies them in one
+// transaction. The result lists every row error found; nothing is written when there are any.
+// A dry run validates and runs the import against the database, then rolls it back.
+func (s *GlossaryImportService) Import(ctx context.Context, tables []*ImportTable, dryRun bool, userID *uuid.UUID) (*models.ImportResult, error) {
+	existing, err := s.termRepo.ListTermNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := newImportValidator(existing)
+	plan := v.plan(tables)
+	if len(v.errors) > 0 {
+		return &models.ImportResult{DryRun: dryRun, Errors: v.errors}, nil
+	}
+
+	return s.importRepo.ApplyGlossaryImport(ctx, plan, userID, !dryRun)
+}
+
+// ImportSource is an import converted from another format (SKOS, catalog exports). It remembers
+// the source record of each row, so row errors can name it.
+type ImportSource struct {
+	Tables   []*ImportTable
+	subjects map[string]map[int]string
+}
+
+func newImportSource() *ImportSource {
+	source := &ImportSource{subjects: map[string]map[int]string{}}
+	for _, sheet := range ImportSheets {
+		source.Tables = append(source.Tables, &ImportTable{Name: sheet, Rows: [][]string{importColumns[sheet]}})
+		source.subjects[sheet] = map[int]string{}
+	}
+	return source
+}
+
+// add appends a row to a sheet, with values keyed by column
+func (s *ImportSource) add(sheet string, subject string, values map[string]string) {
+	for _, table := range s.Tables {
+		if table.Name != sheet {
+			continue
+		}
+		row := make([]string, len(table.Rows[0]))
+		for i, column := range table.Rows[0] {
+			row[i] = values[column]
+		}
+		table.Rows = append(table.Rows, row)
+		s.subjects[sheet][len(table.Rows)] = subject
+		return
+	}
+}
+
+// ImportConverted imports a converted source like Import, setting the subject of row errors
+func (s *GlossaryImportService) ImportConverted(ctx context.Context, source *ImportSource, dryRun bool, userID *uuid.UUID) (*models.ImportResult, error) {
+	result, err := s.Import(ctx, source.Tables, dryRun, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i, rowErr := range result.Errors {
+		result.Errors[i].Subject = source.subjects[rowErr.Sheet][rowErr.Row]
+	}
+	return result, nil
+}
+
+// readSheets checks the headers and returns the non-empty data rows per sheet. Sheets with
+// header errors are skipped, as their cells cannot be interpreted.
+func (v *importValidator) readSheets(tables []*ImportTable) map[string][]importRow {
+	sheets := map[string][]importRow{}
+	seen := map[string]bool{}
+
+	for _, table := range tables {
+		header := importRow{sheet: table.Name, number: 1}
+		known, ok := importColumns[table.Name]
+		if !ok {
+			v.add(header, "", "unknown sheet; expected one of %s", strings.Join(ImportSheets, ", "))
+			continue
+		}
+		if seen[table.Name] {
+			v.add(header, "", "sheet appears more than once")
+			continue
+		}
+		seen[table.Name] = true
+		if len(table.Rows) == 0 {
+			continue
+		}
+
+		columns := map[string]int{}
+		valid := true
+		for pos, cell := range table.Rows[0] {
+			column := strings.ToLower(strings.TrimSpace(cell))
+			column = strings.NewReplacer(" ", "_", "-", "_").Replace(column)
+			if column == "" {
+				continue
+			}
+			if !contains(known, column) {
+				v.add(header, cell, "unknown column; expected %s", strings.Join(known, ", "))
+				valid = false
+				continue
+			}
+			if _, dup := columns[column]; dup {
+				v.add(header, column, "column appears more than once")
+				valid = false
+				continue
+			}
+			columns[column] = pos
+		}
+		for _, column := range importRequiredColumns[table.Name] {
+			if _, ok := columns[column]; !ok {
+				v.add(header, column, "required column is missing")
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		rows := []importRow{}
+		for i, cells := range table.Rows[1:] {
+			if strings.TrimSpace(strings.Join(cells, "")) == "" {
+				continue
+			}
+			rows = append(rows, importRow{sheet: table.Name, number: i + 2, columns: columns, cells: cells})
+		}
+		sheets[table.Name] = rows
+	}
+
+	return sheets
+}
+
+func (v *importValidator) planTerms(rows []importRow) []models.ImportTerm {
+	terms := []models.ImportTerm{}
+	firstRow := map[string]int{} // term ID, new term name or code name -> first row
+
+	for _, row := range rows {
+		name := row.get("term")
+		if name == "" {
+			v.add(row, "term", "is required")
+			continue
+		}
+		v.checkLength(row, "term", "code_name", "category")
+
+		term := models.ImportTerm{
+			Term:                 name,
+			BaseDefinition:       row.optional("base_definition"),
+			Category:             row.optional("category"),
+			CodeName:             row.optional("code_name"),
+			Tags:                 row.list("tags"),
+			ComplianceFrameworks: row.list("compliance_frameworks"),
+			Status:               row.optional("status"),
+			VisibilityType:       row.optional("visibility_type"),
+			AllowedDepartments:   row.list("allowed_departments"),
+		}
+		if term.Status != nil {
+			lower := strings.ToLower(*term.Status)
+			if !importStatuses[lower] {
+				v.add(row, "status", "must be one of draft, approved, certified, deprecated")
+			}
+			term.Status = &lower
+		}
+		if term.VisibilityType != nil {
+			lower := strings.ToLower(*term.VisibilityType)
+			if !importVisibilityTypes[lower] {
+				v.add(row, "visibility_type", "must be public or department_restricted")
+			}
+			term.VisibilityType = &lower
+		}
+
+		// Match by code name first, as names may be reworded by the import
+		var matches []uuid.UUID
+		matchedBy := "term"
+		if term.CodeName != nil {
+			matches = v.existingByCode[strings.ToLower(*term.CodeName)]
+			matchedBy = "code_name"
+		}
+		if len(matches) == 0 {
+			matches = v.existingByName[strings.ToLower(name)]
+			matchedBy = "term"
+		}
+		if len(matches) > 1 {
+			v.add(row, matchedBy, "matches %d existing terms", len(matches))
+			continue
+		}
+
+		key := "new:" + strings.ToLower(name)
+		if len(matches) == 1 {
+			term.ID = matches[0]
+			term.Exists = true
+			key = matches[0].String()
+		} else {
+			term.ID = uuid.New()
+			if term.BaseDefinition == nil {
+				v.add(row, "base_definition", "is required for new terms")
+			}
+			if term.VisibilityType != nil && *term.VisibilityType == "department_restricted" && len(term.AllowedDepartments) == 0 {
+				v.add(row, "allowed_departments", "is required for department_restricted terms")
+			}
+		}
+		if first, dup := firstRow[key]; dup {
+			v.add(row, matchedBy, "same term as row %d", first)
+			continue
+		}
+		if term.CodeName != nil {
+			codeKey := "code:" + strings.ToLower(*term.CodeName)
+			if first, dup := firstRow[codeKey]; dup {
+				v.add(row, "code_name", "same code name as row %d", first)
+				continue
+			}
+			firstRow[codeKey] = row.number
+		}
+		firstRow[key] = row.number
+
+		for _, ref := range []*string{&term.Term, term.CodeName} {
+			if ref == nil {
+				continue
+			}
+			refKey := strings.ToLower(*ref)
+			if id, ok := v.imported[refKey]; ok && id != term.ID {
+				v.imported[refKey] = uuid.Nil
+			} else {
+				v.imported[refKey] = term.ID
+			}
+		}
+		terms = append(terms, term)
+	}
+
+	return terms
+}
+
+func (v *importValidator) planContexts(rows []importRow) []models.ImportContext {
+	contexts := []models.ImportContext{}
+	firstRow := map[string]int{}
+
+	for _, row := range rows {
+		termID, ok := v.resolve(row, "term")
+		v.checkLength(row, "cluster", "system", "product")
+
+		tc := models.ImportContext{
+			TermID:            termID,
+			Cluster:           row.optional("cluster"),
+			System:            row.optional("system"),
+			Product:           row.optional("product"),
+			ContextDefinition: row.get("context_definition"),
+			BusinessRules:     row.list("business_rules"),
+		}
+		if tc.ContextDefinition == "" {
+			v.add(row, "context_definition", "is required")
+			ok = false
+		}
+		if value := row.get("compliance_required"); value != "" {
+			required, valid := parseImportBool(value)
+			if !valid {
+				v.add(row, "compliance_required", "must be true or false")
+				ok = false
+			}
+			tc.ComplianceRequired = &required
+		}
+		if !ok {
+			continue
+		}
+
+		key := strings.Join([]string{termID.String(), row.get("cluster"), row.get("system"), row.get("product")}, "\x00")
+		if first, dup := firstRow[key]; dup {
+			v.add(row, "", "same term, cluster, system and product as row %d", first)
+			continue
+		}
+		firstRow[key] = row.number
+		contexts = append(contexts, tc)
+	}
+
+	return contexts
+}
+
+func (v *importValidator) planExamples(rows []importRow) []models.ImportExample {
+	examples := []models.ImportExample{}
+	firstRow := map[string]int{}
+
+	for _, row := range rows {
+		termID, ok := v.resolve(row, "term")
+		text := row.get("example_text")
+		if text == "" {
+			v.add(row, "example_text", "is required")
+			ok = false
+		}
+		if !ok {
+			continue
+		}
+
+		key := termID.String() + "\x00" + text
+		if first, dup := firstRow[key]; dup {
+			v.add(row, "example_text", "same example as row %d", first)
+			continue
+		}
+		firstRow[key] = row.number
+		examples = append(examples, models.ImportExample{TermID: termID, ExampleText: text, Source: row.optional("source")})
+	}
+
+	return examples
+}
+
+func (v *importValidator) planRelationships(rows []importRow) []models.ImportRelationship {
+	relationships := []models.ImportRelationship{}
+	firstRow := map[string]int{}
+
+	for _, row := range rows {
+		termID, ok := v.resolve(row, "term")
+		relatedID, relatedOK := v.resolve(row, "related_term")
+		relationshipType := strings.ToLower(row.get("relationship_type"))
+		if !importRelationshipTypes[relationshipType] {
+			v.add(row, "relationship_type", "must be one of synonym, antonym, related, see_also, parent, child")
+			ok = false
+		}
+		if !ok || !relatedOK {
+			continue
+		}
+		if termID == relatedID {
+			v.add(row, "related_term", "a term cannot be related to itself")
+			continue
+		}
+
+		key := strings.Join([]string{termID.String(), relatedID.String(), relationshipType}, "\x00")
+		if first, dup := firstRow[key]; dup {
+			v.add(row, "", "same relationship as row %d", first)
+			continue
+		}
+		firstRow[key] = row.number
+		relationships = append(relationships, models.ImportRelationship{TermID: termID, RelatedTermID: relatedID, RelationshipType: relationshipType})
+	}
+
+	return relationships
+}
+
+func (v *importValidator) planAliases(rows []importRow) []models.ImportAlias {
+	aliases := []models.ImportAlias{}
+	firstRow := map[string]int{}
+
+	for _, row := range rows {
+		termID, ok := v.resolve(row, "term")
+		v.checkLength(row, "alias")
+		alias := row.get("alias")
+		if alias == "" {
+			v.add(row, "alias", "is required")
+			ok = false
+		}
+		aliasType := strings.ToLower(row.get("alias_type"))
+		if aliasType == "" {
+			aliasType = "other"
+		}
+		if !importAliasTypes[aliasType] {
+			v.add(row, "alias_type", "must be one of acronym, abbreviation, alternative_spelling, other")
+			ok = false
+		}
+		if !ok {
+			continue
+		}
+
+		key := termID.String() + "\x00" + strings.ToLower(alias)
+		if first, dup := firstRow[key]; dup {
+			v.add(row, "alias", "same alias as row %d", first)
+			continue
+		}
+		firstRow[key] = row.number
+		aliases = append(aliases, models.ImportAlias{TermID: termID, Alias: alias, AliasType: aliasType})
+	}
+
+	return aliases
+}
+
+func parseImportBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "1":
+		return true, true
+	case "false", "no", "n", "0":
+		return false, true
+	}
+	return false, false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
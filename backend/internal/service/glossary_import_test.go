@@ -0,0 +1,217 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestImportValidatorPlan(t *testing.T) {
+	existing := models.Term{ID: uuid.New(), Term: "Customer", CodeName: strPtr("CUST")}
+	v := newImportValidator([]models.Term{existing})
+
+	plan := v.plan([]*ImportTable{
+		{Name: "terms", Rows: [][]string{
+			{"Term", "Code Name", "Base Definition", "Tags", "Status"},
+			{"Client", "CUST", "", "kyc | retail|", "Approved"},
+			{"Account", "ACCT", "A customer's account", "", ""},
+			{"", "", "", "", ""},
+		}},
+		{Name: "contexts", Rows: [][]string{
+			{"term", "cluster", "context_definition", "compliance_required"},
+			{"acct", "Retail", "A retail account", "yes"},
+		}},
+		{Name: "relationships", Rows: [][]string{
+			{"term", "related_term", "relationship_type"},
+			{"Account", "Client", "Related"},
+		}},
+		{Name: "aliases", Rows: [][]string{
+			{"term", "alias"},
+			{"ACCT", "Acc"},
+		}},
+	})
+
+	if len(v.errors) != 0 {
+		t.Fatalf("unexpected errors: %+v", v.errors)
+	}
+	if len(plan.Terms) != 2 {
+		t.Fatalf("planned %d terms, want 2", len(plan.Terms))
+	}
+
+	updated := plan.Terms[0]
+	if !updated.Exists || updated.ID != existing.ID || updated.Term != "Client" {
+		t.Errorf("code name match = %+v, want an update of %s", updated, existing.ID)
+	}
+	if !reflect.DeepEqual(updated.Tags, []string{"kyc", "retail"}) {
+		t.Errorf("tags = %q, want [kyc retail]", updated.Tags)
+	}
+	if updated.Status == nil || *updated.Status != "approved" {
+		t.Errorf("status = %v, want approved", updated.Status)
+	}
+	if updated.BaseDefinition != nil {
+		t.Errorf("empty base definition = %q, want unchanged", *updated.BaseDefinition)
+	}
+
+	created := plan.Terms[1]
+	if created.Exists || created.ID == uuid.Nil {
+		t.Errorf("new term = %+v, want a new ID", created)
+	}
+
+	if len(plan.Contexts) != 1 || plan.Contexts[0].TermID != created.ID {
+		t.Fatalf("contexts = %+v, want one for the imported term", plan.Contexts)
+	}
+	if required := plan.Contexts[0].ComplianceRequired; required == nil || !*required {
+		t.Errorf("compliance_required = %v, want true", required)
+	}
+
+	wantRelationship := []models.ImportRelationship{{TermID: created.ID, RelatedTermID: existing.ID, RelationshipType: "related"}}
+	if !reflect.DeepEqual(plan.Relationships, wantRelationship) {
+		t.Errorf("relationships = %+v, want %+v", plan.Relationships, wantRelationship)
+	}
+
+	wantAlias := []models.ImportAlias{{TermID: created.ID, Alias: "Acc", AliasType: "other"}}
+	if !reflect.DeepEqual(plan.Aliases, wantAlias) {
+		t.Errorf("aliases = %+v, want %+v", plan.Aliases, wantAlias)
+	}
+}
+
+func TestImportValidatorErrors(t *testing.T) {
+	first := models.Term{ID: uuid.New(), Term: "Margin"}
+	second := models.Term{ID: uuid.New(), Term: "Margin", CodeName: strPtr("NIM")}
+	existing := []models.Term{first, second}
+
+	tests := []struct {
+		name   string
+		tables []*ImportTable
+		want   []models.ImportRowError
+	}{
+		{
+			name:   "unknown sheet",
+			tables: []*ImportTable{{Name: "notes", Rows: [][]string{{"text"}}}},
+			want:   []models.ImportRowError{{Sheet: "notes", Row: 1, Message: "unknown sheet; expected one of terms, contexts, examples, relationships, aliases"}},
+		},
+		{
+			name: "duplicate sheet",
+			tables: []*ImportTable{
+				{Name: "aliases", Rows: [][]string{{"term", "alias"}}},
+				{Name: "aliases", Rows: [][]string{{"term", "alias"}}},
+			},
+			want: []models.ImportRowError{{Sheet: "aliases", Row: 1, Message: "sheet appears more than once"}},
+		},
+		{
+			name:   "header errors",
+			tables: []*ImportTable{{Name: "examples", Rows: [][]string{{"term", "colour", "Term"}, {"x", "y", "z"}}}},
+			want: []models.ImportRowError{
+				{Sheet: "examples", Row: 1, Column: "colour", Message: "unknown column; expected term, example_text, source"},
+				{Sheet: "examples", Row: 1, Column: "term", Message: "column appears more than once"},
+				{Sheet: "examples", Row: 1, Column: "example_text", Message: "required column is missing"},
+			},
+		},
+		{
+			name: "term values",
+			tables: []*ImportTable{{Name: "terms", Rows: [][]string{
+				{"term", "base_definition", "status", "visibility_type", "category"},
+				{"Yield", "", "final", "secret", strings.Repeat("x", 101)},
+				{"Spread", "A difference", "", "department_restricted", ""},
+				{"", "No name", "", "", ""},
+			}}},
+			want: []models.ImportRowError{
+				{Sheet: "terms", Row: 2, Column: "category", Message: "must be at most 100 characters"},
+				{Sheet: "terms", Row: 2, Column: "status", Message: "must be one of draft, approved, certified, deprecated"},
+				{Sheet: "terms", Row: 2, Column: "visibility_type", Message: "must be public or department_restricted"},
+				{Sheet: "terms", Row: 2, Column: "base_definition", Message: "is required for new terms"},
+				{Sheet: "terms", Row: 3, Column: "allowed_departments", Message: "is required for department_restricted terms"},
+				{Sheet: "terms", Row: 4, Column: "term", Message: "is required"},
+			},
+		},
+		{
+			name: "duplicate terms",
+			tables: []*ImportTable{{Name: "terms", Rows: [][]string{
+				{"term", "code_name", "base_definition"},
+				{"Yield", "YLD", "Income"},
+				{"yield", "", "Income again"},
+				{"Return", "yld", "Gain"},
+				{"Margin", "", ""},
+			}}},
+			want: []models.ImportRowError{
+				{Sheet: "terms", Row: 3, Column: "term", Message: "same term as row 2"},
+				{Sheet: "terms", Row: 4, Column: "code_name", Message: "same code name as row 2"},
+				{Sheet: "terms", Row: 5, Column: "term", Message: "matches 2 existing terms"},
+			},
+		},
+		{
+			name: "references",
+			tables: []*ImportTable{{Name: "relationships", Rows: [][]string{
+				{"term", "related_term", "relationship_type"},
+				{"Margin", "nim", "synonym"},
+				{"NIM", "Unknown", "synonym"},
+				{"NIM", "nim", "synonym"},
+				{"NIM", "", "opposite"},
+			}}},
+			want: []models.ImportRowError{
+				{Sheet: "relationships", Row: 2, Column: "term", Message: `"Margin" matches 2 existing terms; use the code name`},
+				{Sheet: "relationships", Row: 3, Column: "related_term", Message: `unknown term "Unknown"`},
+				{Sheet: "relationships", Row: 4, Column: "related_term", Message: "a term cannot be related to itself"},
+				{Sheet: "relationships", Row: 5, Column: "related_term", Message: "is required"},
+				{Sheet: "relationships", Row: 5, Column: "relationship_type", Message: "must be one of synonym, antonym, related, see_also, parent, child"},
+			},
+		},
+		{
+			name: "ambiguous imported reference",
+			tables: []*ImportTable{
+				{Name: "terms", Rows: [][]string{
+					{"term", "code_name", "base_definition"},
+					{"Rate", "", "A ratio"},
+					{"Interest Rate", "rate", "A price of money"},
+				}},
+				{Name: "aliases", Rows: [][]string{{"term", "alias"}, {"Rate", "R"}}},
+			},
+			want: []models.ImportRowError{{Sheet: "aliases", Row: 2, Column: "term", Message: `"Rate" matches more than one imported term`}},
+		},
+		{
+			name: "duplicate rows of other sheets",
+			tables: []*ImportTable{
+				{Name: "contexts", Rows: [][]string{
+					{"term", "cluster", "context_definition", "compliance_required"},
+					{"NIM", "Retail", "Retail margin", ""},
+					{"NIM", "Retail", "Retail margin again", ""},
+					{"NIM", "", "Margin", "maybe"},
+				}},
+				{Name: "examples", Rows: [][]string{{"term", "example_text"}, {"NIM", "2%"}, {"NIM", "2%"}}},
+				{Name: "aliases", Rows: [][]string{{"term", "alias", "alias_type"}, {"NIM", "Net margin", ""}, {"NIM", "net MARGIN", ""}, {"NIM", "NM", "nickname"}}},
+			},
+			want: []models.ImportRowError{
+				{Sheet: "contexts", Row: 3, Message: "same term, cluster, system and product as row 2"},
+				{Sheet: "contexts", Row: 4, Column: "compliance_required", Message: "must be true or false"},
+				{Sheet: "examples", Row: 3, Column: "example_text", Message: "same example as row 2"},
+				{Sheet: "aliases", Row: 3, Column: "alias", Message: "same alias as row 2"},
+				{Sheet: "aliases", Row: 4, Column: "alias_type", Message: "must be one of acronym, abbreviation, alternative_spelling, other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newImportValidator(existing)
+			v.plan(tt.tables)
+			if !reflect.DeepEqual(v.errors, tt.want) {
+				t.Errorf("errors = %+v\nwant %+v", v.errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadImportCSV(t *testing.T) {
+	table, err := ReadImportCSV("terms", strings.NewReader("\ufeffterm,tags\n\"Know Your Customer\",\"kyc|aml\"\nLCR\n"))
+	if err != nil {
+		t.Fatalf("ReadImportCSV returned error: %v", err)
+	}
+	want := [][]string{{"term", "tags"}, {"Know Your Customer", "kyc|aml"}, {"LCR"}}
+	if table.Name != "terms" || !reflect.DeepEqual(table.Rows, want) {
+		t.Errorf("ReadImportCSV = %s %q, want terms %q", table.Name, table.Rows, want)
+	}
+}
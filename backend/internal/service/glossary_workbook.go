@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+// Fixed worksheets of the glossary workbook; cluster sheets go between the terms and comparison
+// sheets
+const (
+	workbookTermsSheet      = "Terms"
+	workbookComparisonSheet = "Cluster Comparison"
+	workbookGapsSheet       = "Open Gaps"
+)
+
+// workbookSheetNameInvalid are the characters Excel does not allow in worksheet names
+var workbookSheetNameInvalid = strings.NewReplacer(
+	"[", "(", "]", ")", ":", "-", "*", "-", "?", "", "/", "-", `\`, "-",
+)
+
+// workbookMaxSheetName is the longest worksheet name Excel accepts
+const workbookMaxSheetName = 31
+
+// WorkbookFilter limits a workbook export to terms in any of the categories and with any of the
+// compliance frameworks; empty lists do not filter. Values match case-insensitively.
+type WorkbookFilter struct {
+	Categories []string
+	Frameworks []string
+}
+
+func (f WorkbookFilter) matches(term models.Term) bool {
+	if len(f.Categories) > 0 && !containsFold(f.Categories, valueOrEmpty(term.Category)) {
+		return false
+	}
+	if len(f.Frameworks) > 0 {
+		for _, framework := range term.ComplianceFrameworks {
+			if containsFold(f.Frameworks, framework) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GlossaryWorkbookService exports the glossary as an Excel workbook for business owners: a
+// master sheet of terms, a sheet per cluster with its context definitions and business rules,
+// a term × cluster comparison matrix highlighting missing and conflicting definitions, and the
+// open gaps of the exported terms.
+type GlossaryWorkbookService struct {
+	termRepo *repository.TermRepository
+	gapRepo  *repository.GapRepository
+}
+
+func NewGlossaryWorkbookService() *GlossaryWorkbookService {
+	return &GlossaryWorkbookService{
+		termRepo: repository.NewTermRepository(),
+		gapRepo:  repository.NewGapRepository(),
+	}
+}
+
+// Export writes the workbook for the terms visible to the department that match the filter
+func (s *GlossaryWorkbookService) Export(ctx context.Context, filter WorkbookFilter, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := false
+	gaps := []models.GapAnalysis{}
+	for offset := 0; ; offset += glossaryExportBatchSize {
+		batch, total, err := s.gapRepo.ListGaps(ctx, glossaryExportBatchSize, offset, nil, nil, nil, &resolved, nil)
+		if err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, batch...)
+		if len(batch) == 0 || offset+len(batch) >= total {
+			break
+		}
+	}
+
+	return writeGlossaryWorkbook(glossary, gaps, filter)
+}
+
+// workbookStyles are the cell styles of the workbook
+type workbookStyles struct {
+	header      int
+	text        int
+	missing     int
+	conflicting int
+}
+
+func newWorkbookStyles(f *excelize.File) (*workbookStyles, error) {
+	styles := &workbookStyles{}
+	var err error
+	add := func(style *excelize.Style) int {
+		if err != nil {
+			return 0
+		}
+		var id int
+		id, err = f.NewStyle(style)
+		return id
+	}
+	wrap := &excelize.Alignment{WrapText: true, Vertical: "top"}
+	fill := func(color string) excelize.Fill {
+		return excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}}
+	}
+
+	styles.header = add(&excelize.Style{Font: &excelize.Font{Bold: true}, Fill: fill("D9D9D9"), Alignment: &excelize.Alignment{Vertical: "top"}})
+	styles.text = add(&excelize.Style{Alignment: wrap})
+	styles.missing = add(&excelize.Style{Alignment: wrap, Fill: fill("F4CCCC"), Font: &excelize.Font{Italic: true, Color: "990000"}})
+	styles.conflicting = add(&excelize.Style{Alignment: wrap, Fill: fill("FCE5CD")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workbook styles: %w", err)
+	}
+	return styles, nil
+}
+
+// workbookSheet writes rows to a worksheet under a header row
+type workbookSheet struct {
+	file *excelize.File
+	name string
+	row  int
+	err  error
+}
+
+func newWorkbookSheet(f *excelize.File, styles *workbookStyles, name string, header []string, widths []float64) (*workbookSheet, error) {
+	if _, err := f.NewSheet(name); err != nil {
+		return nil, fmt.Errorf("failed to add worksheet %s: %w", name, err)
+	}
+	sheet := &workbookSheet{file: f, name: name, row: 1}
+	sheet.write(header, styles.header)
+	for i, width := range widths {
+		column, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(name, column, column, width); err != nil && sheet.err == nil {
+			sheet.err = err
+		}
+	}
+	if err := f.SetPanes(name, &excelize.Panes{Freeze: true, Split: false, XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight"}); err != nil && sheet.err == nil {
+		sheet.err = err
+	}
+	return sheet, nil
+}
+
+// write adds a row with one style for all cells
+func (s *workbookSheet) write(values []string, style int) {
+	styles := make([]int, len(values))
+	for i := range styles {
+		styles[i] = style
+	}
+	s.writeStyled(values, styles)
+}
+
+// writeStyled adds a row with a style per cell
+func (s *workbookSheet) writeStyled(values []string, styles []int) {
+	if s.err != nil {
+		return
+	}
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+	cell, _ := excelize.CoordinatesToCellName(1, s.row)
+	if err := s.file.SetSheetRow(s.name, cell, &cells); err != nil {
+		s.err = err
+		return
+	}
+	for i, style := range styles {
+		cell, _ := excelize.CoordinatesToCellName(i+1, s.row)
+		if err := s.file.SetCellStyle(s.name, cell, cell, style); err != nil {
+			s.err = err
+			return
+		}
+	}
+	s.row++
+}
+
+// finish adds an autofilter over the header and data rows
+func (s *workbookSheet) finish(columns int) error {
+	if s.err == nil && s.row > 2 {
+		last, _ := excelize.CoordinatesToCellName(columns, s.row-1)
+		s.err = s.file.AutoFilter(s.name, "A1:"+last, nil)
+	}
+	if s.err != nil {
+		return fmt.Errorf("failed to write worksheet %s: %w", s.name, s.err)
+	}
+	return nil
+}
+
+// workbookSheetNames names the cluster sheets: the cluster name made valid, shortened to the
+// Excel limit, with a numeric suffix where names clash
+func workbookSheetNames(clusters []string) map[string]string {
+	used := map[string]bool{
+		strings.ToLower(workbookTermsSheet):      true,
+		strings.ToLower(workbookComparisonSheet): true,
+		strings.ToLower(workbookGapsSheet):       true,
+		"sheet1":                                 true, // the default worksheet, removed once the terms sheet exists
+	}
+	names := map[string]string{}
+	for _, cluster := range clusters {
+		base := strings.Trim(workbookSheetNameInvalid.Replace(cluster), "' ")
+		if base == "" {
+			base = "Cluster"
+		}
+		name := workbookTruncate(base, workbookMaxSheetName)
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			suffix := fmt.Sprintf(" (%d)", i)
+			name = workbookTruncate(base, workbookMaxSheetName-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[cluster] = name
+	}
+	return names
+}
+
+func workbookTruncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) > limit {
+		return strings.TrimSpace(string(runes[:limit]))
+	}
+	return value
+}
+
+func workbookDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+// writeGlossaryWorkbook writes the workbook for the terms of the glossary that match the filter.
+// The comparison has a column for every cluster the glossary's contexts name, so terms missing
+// from clusters outside the filtered terms still show.
+func writeGlossaryWorkbook(glossary *glossaryExport, gaps []models.GapAnalysis, filter WorkbookFilter) ([]byte, error) {
+	terms := []models.Term{}
+	clusterSet := map[string]bool{}
+	for _, term := range glossary.terms {
+		for _, tc := range term.Contexts {
+			if cluster := valueOrEmpty(tc.Cluster); cluster != "" {
+				clusterSet[cluster] = true
+			}
+		}
+		if filter.matches(term) {
+			terms = append(terms, term)
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return nameLess(terms[i].Term, terms[j].Term)
+	})
+	allClusters := []string{}
+	for cluster := range clusterSet {
+		allClusters = append(allClusters, cluster)
+	}
+	sort.Strings(allClusters)
+
+	f := excelize.NewFile()
+	defer f.Close()
+	styles, err := newWorkbookStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Terms
+	termsHeader := []string{"Term", "Code Name", "Category", "Status", "Base Definition", "Aliases", "Tags", "Compliance Frameworks", "Visibility", "Allowed Departments", "Clusters", "Updated"}
+	sheet, err := newWorkbookSheet(f, styles, workbookTermsSheet, termsHeader, []float64{28, 18, 18, 12, 60, 24, 20, 22, 14, 20, 24, 12})
+	if err != nil {
+		return nil, err
+	}
+	if err := f.DeleteSheet("Sheet1"); err != nil {
+		return nil, fmt.Errorf("failed to remove default worksheet: %w", err)
+	}
+	clusterContexts := map[string][]models.Term{}
+	for _, term := range terms {
+		termClusters := []string{}
+		for _, tc := range term.Contexts {
+			cluster := valueOrEmpty(tc.Cluster)
+			if cluster == "" {
+				continue
+			}
+			if !contains(termClusters, cluster) {
+				termClusters = append(termClusters, cluster)
+				clusterContexts[cluster] = append(clusterContexts[cluster], term)
+			}
+		}
+		sort.Strings(termClusters)
+		visibility := valueOrEmpty(term.VisibilityType)
+		if visibility == "" {
+			visibility = "public"
+		}
+		sheet.write([]string{
+			term.Term,
+			valueOrEmpty(term.CodeName),
+			valueOrEmpty(term.Category),
+			term.Status,
+			term.BaseDefinition,
+			strings.Join(glossary.aliasNames(term), ", "),
+			strings.Join(term.Tags, ", "),
+			strings.Join(term.ComplianceFrameworks, ", "),
+			visibility,
+			strings.Join(term.AllowedDepartments, ", "),
+			strings.Join(termClusters, ", "),
+			workbookDate(term.UpdatedAt),
+		}, styles.text)
+	}
+	if err := sheet.finish(len(termsHeader)); err != nil {
+		return nil, err
+	}
+
+	// A sheet per cluster
+	clusters := []string{}
+	for cluster := range clusterContexts {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	sheetNames := workbookSheetNames(clusters)
+	clusterHeader := []string{"Term", "Code Name", "System", "Product", "Context Definition", "Business Rules", "Compliance Required", "Updated"}
+	for _, cluster := range clusters {
+		sheet, err := newWorkbookSheet(f, styles, sheetNames[cluster], clusterHeader, []float64{28, 18, 18, 18, 60, 50, 12, 12})
+		if err != nil {
+			return nil, err
+		}
+		for _, term := range clusterContexts[cluster] {
+			for _, tc := range term.Contexts {
+				if valueOrEmpty(tc.Cluster) != cluster {
+					continue
+				}
+				compliance := "No"
+				if tc.ComplianceRequired {
+					compliance = "Yes"
+				}
+				sheet.write([]string{
+					term.Term,
+					valueOrEmpty(term.CodeName),
+					valueOrEmpty(tc.System),
+					valueOrEmpty(tc.Product),
+					tc.ContextDefinition,
+					strings.Join(tc.BusinessRules, "\n"),
+					compliance,
+					workbookDate(tc.UpdatedAt),
+				}, styles.text)
+			}
+		}
+		if err := sheet.finish(len(clusterHeader)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cluster comparison
+	matrix := buildDefinitionMatrix("", "cluster", terms, allClusters)
+	comparisonHeader := append([]string{"Term"}, matrix.Columns...)
+	widths := []float64{28}
+	for range matrix.Columns {
+		widths = append(widths, 40)
+	}
+	sheet, err = newWorkbookSheet(f, styles, workbookComparisonSheet, comparisonHeader, widths)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range matrix.Rows {
+		values := []string{row.Term}
+		cellStyles := []int{styles.text}
+		for _, column := range matrix.Columns {
+			cell := row.Cells[column]
+			switch cell.Status {
+			case "missing":
+				values = append(values, "Missing")
+				cellStyles = append(cellStyles, styles.missing)
+			case "conflicting":
+				values = append(values, valueOrEmpty(cell.Definition))
+				cellStyles = append(cellStyles, styles.conflicting)
+			default:
+				values = append(values, valueOrEmpty(cell.Definition))
+				cellStyles = append(cellStyles, styles.text)
+			}
+		}
+		sheet.writeStyled(values, cellStyles)
+	}
+	if err := sheet.finish(len(comparisonHeader)); err != nil {
+		return nil, err
+	}
+	sheet.row++
+	sheet.writeStyled([]string{"Legend", "Missing", "Conflicting definition"}, []int{styles.header, styles.missing, styles.conflicting})
+	if sheet.err != nil {
+		return nil, fmt.Errorf("failed to write worksheet %s: %w", sheet.name, sheet.err)
+	}
+
+	// Open gaps of the exported terms
+	exported := map[uuid.UUID]models.Term{}
+	for _, term := range terms {
+		exported[term.ID] = term
+	}
+	gapsHeader := []string{"Term", "Gap Type", "Dimension", "Severity", "Affected Clusters", "Affected Systems", "Affected Products", "Description", "Detected"}
+	sheet, err = newWorkbookSheet(f, styles, workbookGapsSheet, gapsHeader, []float64{28, 22, 12, 10, 24, 24, 24, 60, 12})
+	if err != nil {
+		return nil, err
+	}
+	for _, gap := range gaps {
+		term, ok := exported[gap.TermID]
+		if !ok {
+			continue
+		}
+		sheet.write([]string{
+			term.Term,
+			gap.GapType,
+			gap.Dimension,
+			gap.Severity,
+			strings.Join(gap.AffectedClusters, ", "),
+			strings.Join(gap.AffectedSystems, ", "),
+			strings.Join(gap.AffectedProducts, ", "),
+			valueOrEmpty(gap.Description),
+			workbookDate(gap.DetectedAt),
+		}, styles.text)
+	}
+	if err := sheet.finish(len(gapsHeader)); err != nil {
+		return nil, err
+	}
+
+	f.SetActiveSheet(0)
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlTermClass is the class of the elements wrapping annotated terms
+const htmlTermClass = "glossary-term"
+
+// htmlSkippedElements are never annotated inside: links, scripts and form controls, and text that
+// is not shown in the page body
+var htmlSkippedElements = map[string]bool{
+	"a": true, "script": true, "style": true, "noscript": true, "template": true, "textarea": true,
+	"button": true, "select": true, "option": true, "title": true, "head": true,
+}
+
+// AnnotateHTML sanitizes an HTML document or fragment and wraps the terms found in its text with
+//
+//	<span class="glossary-term" data-term-id="..." data-term="..." data-definition="..." data-cluster="..." tabindex="0">
+//
+// where data-cluster is only set for cluster-specific definitions. Only text nodes are annotated,
+// never markup or text inside links, scripts and form controls; a term split across elements
+// ("Know <b>Your</b> Customer") is not found. With firstOnly only the first mention of each term
+// is wrapped.
+func (a *TermAnnotator) AnnotateHTML(ctx context.Context, document string, cluster *string, userDepartment *string, firstOnly bool) (*models.HTMLAnnotationResult, error) {
+	root, fragment, err := parseHTMLDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	sanitizeHTML(root)
+
+	// First pass: find the mentions per text node, so definitions can be loaded at once
+	type textMentions struct {
+		node     *html.Node
+		mentions []models.TermMention
+	}
+	found := []textMentions{}
+	counts := map[uuid.UUID]int{}
+	termIDs := []uuid.UUID{}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.ElementNode:
+				if !htmlSkippedElements[child.Data] {
+					walk(child)
+				}
+			case html.TextNode:
+				mentions := []models.TermMention{}
+				for _, mention := range a.FindMentions(child.Data, userDepartment) {
+					if firstOnly && counts[mention.TermID] > 0 {
+						continue
+					}
+					if counts[mention.TermID] == 0 {
+						termIDs = append(termIDs, mention.TermID)
+					}
+					counts[mention.TermID]++
+					mentions = append(mentions, mention)
+				}
+				if len(mentions) > 0 {
+					found = append(found, textMentions{node: child, mentions: mentions})
+				}
+			}
+		}
+	}
+	walk(root)
+
+	result := &models.HTMLAnnotationResult{Terms: []models.AnnotatedTerm{}}
+	definitions := map[uuid.UUID]*models.AnnotatedTerm{}
+	if len(termIDs) > 0 {
+		definitions, err = a.termRepo.ResolveDefinitions(ctx, termIDs, cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Second pass: split the text nodes around their mentions
+	for _, tm := range found {
+		text := []rune(tm.node.Data)
+		parent := tm.node.Parent
+		pos := 0
+		for _, mention := range tm.mentions {
+			term, ok := definitions[mention.TermID]
+			if !ok {
+				continue // deleted since the last rebuild
+			}
+			if mention.Start > pos {
+				parent.InsertBefore(&html.Node{Type: html.TextNode, Data: string(text[pos:mention.Start])}, tm.node)
+			}
+			parent.InsertBefore(termSpan(term, string(text[mention.Start:mention.End])), tm.node)
+			pos = mention.End
+			result.Mentions++
+		}
+		if pos < len(text) {
+			parent.InsertBefore(&html.Node{Type: html.TextNode, Data: string(text[pos:])}, tm.node)
+		}
+		parent.RemoveChild(tm.node)
+	}
+
+	for _, termID := range termIDs {
+		if term, ok := definitions[termID]; ok {
+			term.Mentions = counts[termID]
+			result.Terms = append(result.Terms, *term)
+		}
+	}
+
+	var out strings.Builder
+	if fragment {
+		for child := root.FirstChild; child != nil; child = child.NextSibling {
+			if err := html.Render(&out, child); err != nil {
+				return nil, fmt.Errorf("failed to render HTML: %w", err)
+			}
+		}
+	} else if err := html.Render(&out, root); err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	result.HTML = out.String()
+
+	return result, nil
+}
+
+// parseHTMLDocument parses a full document when the input has an <html> element or doctype, and
+// a body fragment otherwise. Fragments are returned as the children of a detached <div>.
+func parseHTMLDocument(document string) (*html.Node, bool, error) {
+	lower := strings.ToLower(document)
+	if strings.Contains(lower, "<html") || strings.Contains(lower, "<!doctype") {
+		root, err := html.Parse(strings.NewReader(document))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		return root, false, nil
+	}
+
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(document), body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, node := range nodes {
+		root.AppendChild(node)
+	}
+	return root, true, nil
+}
+
+// termSpan wraps text in the markup the tooltip script looks for
+func termSpan(term *models.AnnotatedTerm, text string) *html.Node {
+	span := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "span",
+		DataAtom: atom.Span,
+		Attr: []html.Attribute{
+			{Key: "class", Val: htmlTermClass},
+			{Key: "data-term-id", Val: term.TermID.String()},
+			{Key: "data-term", Val: term.Term},
+			{Key: "data-definition", Val: term.Definition},
+		},
+	}
+	if term.Cluster != nil {
+		span.Attr = append(span.Attr, html.Attribute{Key: "data-cluster", Val: *term.Cluster})
+	}
+	span.Attr = append(span.Attr, html.Attribute{Key: "tabindex", Val: "0"})
+	span.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+	return span
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// sanitizeDroppedElements are removed together with their content
+var sanitizeDroppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true, "object": true,
+	"embed": true, "applet": true, "noscript": true, "template": true, "form": true, "input": true,
+	"button": true, "select": true, "option": true, "textarea": true, "svg": true, "math": true,
+	"link": true, "meta": true, "base": true,
+}
+
+// sanitizeAllowedElements are kept; any other element is replaced by its content
+var sanitizeAllowedElements = map[string]bool{
+	"html": true, "head": true, "body": true, "title": true,
+	"p": true, "div": true, "span": true, "br": true, "hr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true,
+	"blockquote": true, "pre": true, "code": true, "em": true, "strong": true, "b": true, "i": true,
+	"u": true, "s": true, "sub": true, "sup": true, "small": true, "mark": true, "abbr": true,
+	"cite": true, "q": true, "a": true, "img": true,
+	"table": true, "thead": true, "tbody": true, "tfoot": true, "tr": true, "th": true, "td": true,
+	"caption": true, "colgroup": true, "col": true,
+	"section": true, "article": true, "header": true, "footer": true, "nav": true, "aside": true,
+	"main": true, "figure": true, "figcaption": true, "details": true, "summary": true, "time": true,
+}
+
+// sanitizeGlobalAttributes are allowed on every element
+var sanitizeGlobalAttributes = map[string]bool{"class": true, "id": true, "title": true, "lang": true, "dir": true}
+
+// sanitizeElementAttributes are allowed on specific elements; URL attributes are checked separately
+var sanitizeElementAttributes = map[string]map[string]bool{
+	"a":    {"href": true},
+	"img":  {"src": true, "alt": true, "width": true, "height": true},
+	"td":   {"colspan": true, "rowspan": true},
+	"th":   {"colspan": true, "rowspan": true, "scope": true},
+	"col":  {"span": true},
+	"ol":   {"start": true},
+	"time": {"datetime": true},
+}
+
+// sanitizeHTML removes scripts, event handlers, styles, comments and unsafe URLs from the tree
+// below n, keeping a conservative set of formatting elements and attributes
+func sanitizeHTML(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch child.Type {
+		case html.CommentNode:
+			n.RemoveChild(child)
+		case html.ElementNode:
+			name := strings.ToLower(child.Data)
+			if sanitizeDroppedElements[name] {
+				n.RemoveChild(child)
+				break
+			}
+
+			sanitizeHTML(child)
+			if !sanitizeAllowedElements[name] {
+				// Unwrap: the already sanitized content takes the element's place
+				for grandchild := child.FirstChild; grandchild != nil; {
+					following := grandchild.NextSibling
+					child.RemoveChild(grandchild)
+					n.InsertBefore(grandchild, child)
+					grandchild = following
+				}
+				n.RemoveChild(child)
+				break
+			}
+			child.Attr = sanitizeAttributes(name, child.Attr)
+		}
+
+		child = next
+	}
+}
+
+func sanitizeAttributes(element string, attrs []html.Attribute) []html.Attribute {
+	kept := []html.Attribute{}
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || (!sanitizeGlobalAttributes[key] && !sanitizeElementAttributes[element][key]) {
+			continue
+		}
+		if (key == "href" || key == "src") && !isSafeURL(attr.Val, key == "href") {
+			continue
+		}
+		kept = append(kept, html.Attribute{Key: key, Val: attr.Val})
+	}
+	return kept
+}
+
+// isSafeURL allows relative URLs and http(s), plus mailto for links
+func isSafeURL(value string, link bool) bool {
+	// Browsers ignore whitespace and control characters in schemes ("java\tscript:")
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+
+	parsed, err := url.Parse(cleaned)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "", "http", "https":
+		return true
+	case "mailto":
+		return link
+	}
+	return false
+}
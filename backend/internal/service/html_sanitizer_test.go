@@ -0,0 +1,123 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"mixed case scheme", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a>x</a>`},
+		{"tab entity in scheme", `<a href="jav&#x09;ascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"literal tab in scheme", "<a href=\"java\tscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"newline in scheme", "<a href=\"java\nscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"encoded scheme letter", `<a href="&#106;avascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"encoded colon", `<a href="javascript&colon;alert(1)">x</a>`, `<a>x</a>`},
+		{"leading control characters", `<a href=" &#14;javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"vbscript link", `<a href="vbscript:msgbox(1)">x</a>`, `<a>x</a>`},
+		{"data image", `<img src="data:image/svg+xml,<svg onload=alert(1)>">`, `<img/>`},
+		{"mailto only on links", `<a href="mailto:a@b.c">x</a><img src="mailto:a@b.c">`, `<a href="mailto:a@b.c">x</a><img/>`},
+		{"safe urls kept", `<a href="/relative">r</a><img src="https://x/y.png" onerror="alert(1)">`, `<a href="/relative">r</a><img src="https://x/y.png"/>`},
+		{"namespaced href", `<a xlink:href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"event handlers and styles", `<p srcdoc="x" onclick="alert(1)" style="color:red" class="c">t</p>`, `<p class="c">t</p>`},
+		{"script", `<p>a</p><script>alert(1)</script>`, `<p>a</p>`},
+		{"comment", `<!--<img src=x onerror=alert(1)>--><p>ok</p>`, `<p>ok</p>`},
+		{"unknown element unwrapped", `<custom-el onmouseover="alert(1)"><b>kept</b></custom-el>`, `<b>kept</b>`},
+		{"form", `<form action="javascript:alert(1)"><input></form>`, ``},
+		{"xmp content stays text", `<xmp><script>alert(1)</script></xmp>`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"noembed content stays text", `<noembed><img src=x onerror=alert(1)></noembed>`, `&lt;img src=x onerror=alert(1)&gt;`},
+		{"noframes content stays text", `<noframes><img src=x onerror=alert(1)></noframes>`, `&lt;img src=x onerror=alert(1)&gt;`},
+		{"plaintext content stays text", `<plaintext><script>alert(1)</script>`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"title content stays text", `<title><img src=x onerror=alert(1)></title>`, `<title>&lt;img src=x onerror=alert(1)&gt;</title>`},
+		{"noscript breakout", `<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`, `<img src="x"/>&#34;&gt;`},
+		{"svg mutation", `<svg><p><style><img src=x onerror=alert(1)></style></p></svg>`, ``},
+		{"math mutation", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)></style></mglyph></table></mtext></math>`, ``},
+		{"iframe srcdoc", `<iframe srcdoc="&lt;script&gt;alert(1)&lt;/script&gt;"></iframe>`, ``},
+		{
+			"full document",
+			`<html><head><title>t</title><script>alert(1)</script></head><body><p>x</p></body></html>`,
+			`<html><head><title>t</title></head><body><p>x</p></body></html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeString(t, tt.input); got != tt.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSanitizeHTMLReparse checks that the sanitized output stays safe when a browser parses it
+// again, which is where mutation XSS appears
+func TestSanitizeHTMLReparse(t *testing.T) {
+	inputs := []string{
+		`<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`,
+		`<svg></p><style><a id="</style><img src=1 onerror=alert(1)>">`,
+		`<math><mi><table><mglyph><svg><mtext><textarea><path id="</textarea><img onerror=alert(1) src=1>">`,
+		`<form><math><mtext></form><form><mglyph><style></math><img src onerror=alert(1)>`,
+		`<xmp></xmp><img src=x onerror=alert(1)></xmp>`,
+		`<p title="&lt;/p&gt;&lt;img src=x onerror=alert(1)&gt;">x</p>`,
+	}
+
+	for _, input := range inputs {
+		once := sanitizeString(t, input)
+		if twice := sanitizeString(t, once); twice != once {
+			t.Errorf("sanitizeHTML is not stable for %q: %q, then %q", input, once, twice)
+		}
+		root, _, err := parseHTMLDocument(once)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", once, err)
+		}
+		assertNoActiveContent(t, root, input)
+	}
+}
+
+// sanitizeString parses, sanitizes and renders a document the way AnnotateHTML does
+func sanitizeString(t *testing.T, document string) string {
+	t.Helper()
+	root, fragment, err := parseHTMLDocument(document)
+	if err != nil {
+		t.Fatalf("parseHTMLDocument(%q) returned error: %v", document, err)
+	}
+	sanitizeHTML(root)
+
+	var out strings.Builder
+	if !fragment {
+		if err := html.Render(&out, root); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&out, child); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out.String()
+}
+
+func assertNoActiveContent(t *testing.T, n *html.Node, input string) {
+	t.Helper()
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			if sanitizeDroppedElements[child.Data] {
+				t.Errorf("output of %q contains <%s>", input, child.Data)
+			}
+			for _, attr := range child.Attr {
+				if strings.HasPrefix(strings.ToLower(attr.Key), "on") || attr.Key == "style" || attr.Key == "srcdoc" {
+					t.Errorf("output of %q contains attribute %s", input, attr.Key)
+				}
+			}
+		}
+		assertNoActiveContent(t, child, input)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// indexRefresher keeps an in-memory index fresh: it rebuilds the index when started, then
+// periodically and shortly after Invalidate is called
+type indexRefresher struct {
+	refreshInterval time.Duration
+	invalidate      chan struct{}
+}
+
+func newIndexRefresher(refreshInterval time.Duration) indexRefresher {
+	return indexRefresher{refreshInterval: refreshInterval, invalidate: make(chan struct{}, 1)}
+}
+
+// start runs rebuild in the background until ctx is cancelled. Each rebuild is bounded by
+// suggestBuildTimeout.
+func (r *indexRefresher) start(ctx context.Context, rebuild func(ctx context.Context)) {
+	run := func() {
+		buildCtx, cancel := context.WithTimeout(ctx, suggestBuildTimeout)
+		defer cancel()
+		rebuild(buildCtx)
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(r.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-r.invalidate:
+			}
+			run()
+		}
+	}()
+}
+
+// Invalidate schedules a rebuild after the indexed data changed. Calls made while a rebuild is
+// already pending are coalesced.
+func (r *indexRefresher) Invalidate() {
+	select {
+	case r.invalidate <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Namespaces of the vocabularies used by the SKOS export and import
+const (
+	rdfNS      = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	skosNS     = "http://www.w3.org/2004/02/skos/core#"
+	dctNS      = "http://purl.org/dc/terms/"
+	xsdNS      = "http://www.w3.org/2001/XMLSchema#"
+	glossaryNS = "https://clarityconnect.local/ns/glossary#" // glossary data SKOS has no property for
+
+	rdfType = rdfNS + "type"
+)
+
+// rdfPrefixes abbreviate IRIs in the output, in this order
+var rdfPrefixes = []struct{ prefix, namespace string }{
+	{"rdf", rdfNS},
+	{"skos", skosNS},
+	{"dct", dctNS},
+	{"xsd", xsdNS},
+	{"cc", glossaryNS},
+}
+
+// rdfLocalName is the subset of Turtle local names written abbreviated
+var rdfLocalName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// rdfNode is a resource and its properties, for writing. Nodes without an IRI are blank nodes,
+// written nested in the property that refers to them.
+type rdfNode struct {
+	iri        string
+	types      []string
+	properties []rdfProperty
+}
+
+type rdfProperty struct {
+	predicate string
+	values    []rdfValue
+}
+
+// rdfValue is an IRI, a nested blank node or a literal; literals have a language or a datatype
+type rdfValue struct {
+	iri      string
+	node     *rdfNode
+	literal  string
+	language string
+	datatype string
+}
+
+// add appends values to the property, keeping properties in the order they were first added
+func (n *rdfNode) add(predicate string, values ...rdfValue) {
+	if len(values) == 0 {
+		return
+	}
+	for i := range n.properties {
+		if n.properties[i].predicate == predicate {
+			n.properties[i].values = append(n.properties[i].values, values...)
+			return
+		}
+	}
+	n.properties = append(n.properties, rdfProperty{predicate: predicate, values: values})
+}
+
+func rdfIRI(iri string) rdfValue {
+	return rdfValue{iri: iri}
+}
+
+func rdfText(text string, language string) rdfValue {
+	return rdfValue{literal: text, language: language}
+}
+
+func rdfString(text string) rdfValue {
+	return rdfValue{literal: text}
+}
+
+func rdfBool(value bool) rdfValue {
+	return rdfValue{literal: fmt.Sprint(value), datatype: xsdNS + "boolean"}
+}
+
+func rdfDateTime(value time.Time) rdfValue {
+	return rdfValue{literal: value.UTC().Format(time.RFC3339), datatype: xsdNS + "dateTime"}
+}
+
+// compactIRI abbreviates an IRI with the known prefixes, or returns ok=false
+func compactIRI(iri string) (string, bool) {
+	for _, p := range rdfPrefixes {
+		if local := strings.TrimPrefix(iri, p.namespace); local != iri && rdfLocalName.MatchString(local) {
+			return p.prefix + ":" + local, true
+		}
+	}
+	return iri, false
+}
+
+// writeTurtle serializes the nodes as Turtle, with the known prefixes declared
+func writeTurtle(nodes []*rdfNode) []byte {
+	var out strings.Builder
+	for _, p := range rdfPrefixes {
+		fmt.Fprintf(&out, "@prefix %s: <%s> .\n", p.prefix, p.namespace)
+	}
+
+	for _, node := range nodes {
+		out.WriteString("\n")
+		out.WriteString(turtleIRI(node.iri))
+		writeTurtleProperties(&out, node, "    ")
+		out.WriteString(" .\n")
+	}
+
+	return []byte(out.String())
+}
+
+func writeTurtleProperties(out *strings.Builder, node *rdfNode, indent string) {
+	separator := "\n"
+	if len(node.types) > 0 {
+		types := make([]string, len(node.types))
+		for i, t := range node.types {
+			types[i] = turtleIRI(t)
+		}
+		fmt.Fprintf(out, "%s%sa %s", separator, indent, strings.Join(types, ", "))
+		separator = " ;\n"
+	}
+	for _, property := range node.properties {
+		values := make([]string, len(property.values))
+		for i, value := range property.values {
+			values[i] = turtleValue(value, indent)
+		}
+		fmt.Fprintf(out, "%s%s%s %s", separator, indent, turtleIRI(property.predicate), strings.Join(values, ", "))
+		separator = " ;\n"
+	}
+}
+
+func turtleIRI(iri string) string {
+	if compact, ok := compactIRI(iri); ok {
+		return compact
+	}
+	escaped := strings.NewReplacer(">", "%3E", "<", "%3C", `"`, "%22", " ", "%20", `\`, "%5C").Replace(iri)
+	return "<" + escaped + ">"
+}
+
+func turtleValue(value rdfValue, indent string) string {
+	switch {
+	case value.node != nil:
+		var nested strings.Builder
+		nested.WriteString("[")
+		writeTurtleProperties(&nested, value.node, indent+"    ")
+		nested.WriteString("\n" + indent + "]")
+		return nested.String()
+	case value.iri != "":
+		return turtleIRI(value.iri)
+	case value.datatype == xsdNS+"boolean":
+		return value.literal
+	}
+
+	literal := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(value.literal) + `"`
+	if value.language != "" {
+		return literal + "@" + value.language
+	}
+	if value.datatype != "" {
+		return literal + "^^" + turtleIRI(value.datatype)
+	}
+	return literal
+}
+
+// writeJSONLD serializes the nodes as a compacted JSON-LD graph with the known prefixes in its context
+func writeJSONLD(nodes []*rdfNode) ([]byte, error) {
+	context := map[string]interface{}{}
+	for _, p := range rdfPrefixes {
+		context[p.prefix] = p.namespace
+	}
+
+	graph := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		graph[i] = jsonldNode(node)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"@context": context, "@graph": graph}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON-LD: %w", err)
+	}
+	return data, nil
+}
+
+func jsonldNode(node *rdfNode) map[string]interface{} {
+	object := map[string]interface{}{}
+	if node.iri != "" {
+		object["@id"] = node.iri
+	}
+	if len(node.types) == 1 {
+		object["@type"], _ = compactIRI(node.types[0])
+	} else if len(node.types) > 1 {
+		types := make([]string, len(node.types))
+		for i, t := range node.types {
+			types[i], _ = compactIRI(t)
+		}
+		object["@type"] = types
+	}
+
+	for _, property := range node.properties {
+		key, _ := compactIRI(property.predicate)
+		if len(property.values) == 1 {
+			object[key] = jsonldValue(property.values[0])
+			continue
+		}
+		values := make([]interface{}, len(property.values))
+		for i, value := range property.values {
+			values[i] = jsonldValue(value)
+		}
+		object[key] = values
+	}
+
+	return object
+}
+
+func jsonldValue(value rdfValue) interface{} {
+	switch {
+	case value.node != nil:
+		return jsonldNode(value.node)
+	case value.iri != "":
+		return map[string]interface{}{"@id": value.iri}
+	case value.language != "":
+		return map[string]interface{}{"@value": value.literal, "@language": value.language}
+	case value.datatype == xsdNS+"boolean":
+		return value.literal == "true"
+	case value.datatype != "":
+		datatype, _ := compactIRI(value.datatype)
+		return map[string]interface{}{"@value": value.literal, "@type": datatype}
+	}
+	return value.literal
+}
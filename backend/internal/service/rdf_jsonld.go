@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jsonldContext holds the term definitions of an inline JSON-LD context
+type jsonldContext struct {
+	terms map[string]jsonldTerm
+	vocab string
+	base  string
+}
+
+type jsonldTerm struct {
+	iri      string
+	idValues bool // "@type": "@id" or "@vocab", string values are IRIs
+	language string
+}
+
+// jsonldParser reads JSON-LD documents with inline contexts into triples. It covers node objects,
+// @graph, @id, @type, value objects, lists and sets, which is what glossary tools produce; remote
+// contexts, reverse properties and named graphs are not supported.
+type jsonldParser struct {
+	blanks  int
+	triples []rdfTriple
+}
+
+func parseJSONLD(data []byte) ([]rdfTriple, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid JSON-LD: %w", err)
+	}
+
+	p := &jsonldParser{}
+	context := &jsonldContext{terms: map[string]jsonldTerm{}}
+	if err := p.element(document, context); err != nil {
+		return nil, err
+	}
+	return p.triples, nil
+}
+
+// element reads top-level node objects, or arrays of them
+func (p *jsonldParser) element(value interface{}, context *jsonldContext) error {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := p.element(item, context); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		_, err := p.node(v, context)
+		return err
+	default:
+		return fmt.Errorf("invalid JSON-LD: expected a node object")
+	}
+	return nil
+}
+
+// node reads a node object and returns its IRI or blank node
+func (p *jsonldParser) node(object map[string]interface{}, context *jsonldContext) (string, error) {
+	if local, ok := object["@context"]; ok {
+		var err error
+		if context, err = context.with(local); err != nil {
+			return "", err
+		}
+	}
+
+	subject := ""
+	if id, ok := object["@id"].(string); ok {
+		subject = context.expand(id, false)
+	}
+	if subject == "" {
+		p.blanks++
+		subject = fmt.Sprintf("_:b%d", p.blanks)
+	}
+
+	if graph, ok := object["@graph"]; ok {
+		if err := p.element(graph, context); err != nil {
+			return "", err
+		}
+	}
+
+	switch types := object["@type"].(type) {
+	case string:
+		p.addType(subject, types, context)
+	case []interface{}:
+		for _, t := range types {
+			if name, ok := t.(string); ok {
+				p.addType(subject, name, context)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		if !strings.HasPrefix(key, "@") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		predicate := context.expand(key, true)
+		if predicate == "" || isBlankNode(predicate) {
+			continue
+		}
+		if err := p.values(subject, predicate, object[key], context.terms[key], context); err != nil {
+			return "", err
+		}
+	}
+
+	return subject, nil
+}
+
+func (p *jsonldParser) addType(subject string, name string, context *jsonldContext) {
+	if iri := context.expand(name, true); iri != "" {
+		p.triples = append(p.triples, rdfTriple{subject: subject, predicate: rdfType, object: rdfObject{value: iri}})
+	}
+}
+
+func (p *jsonldParser) values(subject string, predicate string, value interface{}, term jsonldTerm, context *jsonldContext) error {
+	var object rdfObject
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			if err := p.values(subject, predicate, item, term, context); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		if term.idValues {
+			object = rdfObject{value: context.expand(v, false)}
+		} else {
+			object = rdfObject{value: v, literal: true, language: term.language}
+		}
+	case json.Number:
+		object = rdfObject{value: v.String(), literal: true, datatype: xsdNS + "integer"}
+		if strings.ContainsAny(v.String(), ".eE") {
+			object.datatype = xsdNS + "double"
+		}
+	case bool:
+		object = rdfObject{value: fmt.Sprint(v), literal: true, datatype: xsdNS + "boolean"}
+	case map[string]interface{}:
+		if literal, ok := v["@value"]; ok {
+			object = rdfObject{value: fmt.Sprint(literal), literal: true}
+			if language, ok := v["@language"].(string); ok {
+				object.language = strings.ToLower(language)
+			}
+			if datatype, ok := v["@type"].(string); ok {
+				object.datatype = context.expand(datatype, true)
+			}
+			break
+		}
+		if list, ok := v["@list"]; ok {
+			return p.values(subject, predicate, list, term, context)
+		}
+		if set, ok := v["@set"]; ok {
+			return p.values(subject, predicate, set, term, context)
+		}
+		node, err := p.node(v, context)
+		if err != nil {
+			return err
+		}
+		object = rdfObject{value: node}
+	default:
+		return fmt.Errorf("invalid JSON-LD value for %s", predicate)
+	}
+
+	if object.value == "" && !object.literal {
+		return nil
+	}
+	p.triples = append(p.triples, rdfTriple{subject: subject, predicate: predicate, object: object})
+	return nil
+}
+
+// with returns the context extended by a local context definition
+func (c *jsonldContext) with(definition interface{}) (*jsonldContext, error) {
+	next := &jsonldContext{terms: make(map[string]jsonldTerm, len(c.terms)), vocab: c.vocab, base: c.base}
+	for name, term := range c.terms {
+		next.terms[name] = term
+	}
+
+	switch d := definition.(type) {
+	case nil:
+		return &jsonldContext{terms: map[string]jsonldTerm{}}, nil
+	case []interface{}:
+		for _, item := range d {
+			var err error
+			if next, err = next.with(item); err != nil {
+				return nil, err
+			}
+		}
+		return next, nil
+	case string:
+		return nil, fmt.Errorf("remote JSON-LD contexts are not supported: %s", d)
+	case map[string]interface{}:
+		if vocab, ok := d["@vocab"].(string); ok {
+			next.vocab = vocab
+		}
+		if base, ok := d["@base"].(string); ok {
+			next.base = base
+		}
+
+		// prefixes (terms defined as an absolute IRI) first, so other definitions can use them
+		// whatever their order
+		isPrefix := func(name string) bool {
+			iri, ok := d[name].(string)
+			if !ok {
+				return false
+			}
+			colon := strings.IndexByte(iri, ':')
+			if colon < 0 {
+				return false
+			}
+			_, defined := d[iri[:colon]]
+			return !defined
+		}
+		names := make([]string, 0, len(d))
+		for name := range d {
+			if !strings.HasPrefix(name, "@") {
+				names = append(names, name)
+			}
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if prefixI, prefixJ := isPrefix(names[i]), isPrefix(names[j]); prefixI != prefixJ {
+				return prefixI
+			}
+			return names[i] < names[j]
+		})
+
+		for _, name := range names {
+			switch t := d[name].(type) {
+			case string:
+				next.terms[name] = jsonldTerm{iri: next.expand(t, true)}
+			case map[string]interface{}:
+				term := jsonldTerm{}
+				if id, ok := t["@id"].(string); ok {
+					term.iri = next.expand(id, true)
+				} else {
+					term.iri = next.expand(name, true)
+				}
+				if valueType, ok := t["@type"].(string); ok {
+					term.idValues = valueType == "@id" || valueType == "@vocab"
+				}
+				if language, ok := t["@language"].(string); ok {
+					term.language = strings.ToLower(language)
+				}
+				next.terms[name] = term
+			case nil:
+				delete(next.terms, name)
+			}
+		}
+		return next, nil
+	}
+	return nil, fmt.Errorf("invalid JSON-LD context")
+}
+
+// expand resolves a term, compact IRI or relative IRI. Keys and types expand against the vocabulary,
+// @id values against the base. Returns "" for values that don't map to an IRI.
+func (c *jsonldContext) expand(value string, vocab bool) string {
+	if term, ok := c.terms[value]; ok && vocab {
+		return term.iri
+	}
+	if colon := strings.IndexByte(value, ':'); colon > 0 {
+		prefix, suffix := value[:colon], value[colon+1:]
+		if prefix == "_" {
+			return value
+		}
+		if term, ok := c.terms[prefix]; ok && !strings.HasPrefix(suffix, "//") {
+			return term.iri + suffix
+		}
+		return value
+	}
+	if vocab {
+		if c.vocab != "" {
+			return c.vocab + value
+		}
+		return ""
+	}
+	if c.base != "" {
+		return c.base[:strings.LastIndexAny(c.base, "/#")+1] + value
+	}
+	return value
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONLD(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []rdfTriple
+	}{
+		{
+			name: "prefixes and compact IRIs",
+			input: `{
+				"@context": {"ex": "https://example.com/ns#", "skos": "http://www.w3.org/2004/02/skos/core#"},
+				"@id": "ex:a",
+				"@type": "skos:Concept",
+				"skos:prefLabel": "Margin",
+				"https://example.com/full": {"@id": "https://example.com/b"}
+			}`,
+			want: []rdfTriple{
+				{testNS + "a", rdfType, iriObject(skosNS + "Concept")},
+				{testNS + "a", "https://example.com/full", iriObject("https://example.com/b")},
+				{testNS + "a", skosNS + "prefLabel", literalObject("Margin", "", "")},
+			},
+		},
+		{
+			name: "term definitions",
+			input: `{
+				"@context": {
+					"label": {"@id": "ex:label", "@language": "EN"},
+					"broader": {"@id": "ex:broader", "@type": "@id"},
+					"ex": "https://example.com/ns#",
+					"code": "ex:code"
+				},
+				"@id": "https://example.com/a",
+				"label": "Margin",
+				"broader": "ex:b",
+				"code": "NIM"
+			}`,
+			want: []rdfTriple{
+				{"https://example.com/a", testNS + "broader", iriObject(testNS + "b")},
+				{"https://example.com/a", testNS + "code", literalObject("NIM", "", "")},
+				{"https://example.com/a", testNS + "label", literalObject("Margin", "en", "")},
+			},
+		},
+		{
+			name: "vocab and base",
+			input: `{
+				"@context": {"@vocab": "https://example.com/ns#", "@base": "https://example.com/terms/index"},
+				"@id": "a",
+				"@type": "Concept",
+				"label": "Margin",
+				"@unknown": "ignored"
+			}`,
+			want: []rdfTriple{
+				{"https://example.com/terms/a", rdfType, iriObject(testNS + "Concept")},
+				{"https://example.com/terms/a", testNS + "label", literalObject("Margin", "", "")},
+			},
+		},
+		{
+			name: "keys without a mapping are dropped",
+			input: `{
+				"@context": {"ex": "https://example.com/ns#"},
+				"@id": "ex:a",
+				"label": "no vocab",
+				"ex:kept": "yes"
+			}`,
+			want: []rdfTriple{{testNS + "a", testNS + "kept", literalObject("yes", "", "")}},
+		},
+		{
+			name: "graph and nested contexts",
+			input: `{
+				"@context": {"ex": "https://example.com/ns#"},
+				"@graph": [
+					{"@id": "ex:a", "ex:p": "outer"},
+					{"@context": {"ex": "https://example.com/other#"}, "@id": "ex:b", "ex:p": "inner"},
+					{"@context": null, "@id": "https://example.com/c", "ex:p": "reset"}
+				]
+			}`,
+			want: []rdfTriple{
+				{testNS + "a", testNS + "p", literalObject("outer", "", "")},
+				{"https://example.com/other#b", "https://example.com/other#p", literalObject("inner", "", "")},
+				{"https://example.com/c", "ex:p", literalObject("reset", "", "")},
+			},
+		},
+		{
+			name: "value objects",
+			input: `{
+				"@context": {"ex": "https://example.com/ns#", "xsd": "http://www.w3.org/2001/XMLSchema#"},
+				"@id": "ex:a",
+				"ex:p": [
+					{"@value": "Marge", "@language": "FR"},
+					{"@value": "2026-01-02", "@type": "xsd:date"},
+					42, 1.5, true, null
+				]
+			}`,
+			want: []rdfTriple{
+				{testNS + "a", testNS + "p", literalObject("Marge", "fr", "")},
+				{testNS + "a", testNS + "p", literalObject("2026-01-02", "", xsdNS+"date")},
+				{testNS + "a", testNS + "p", literalObject("42", "", xsdNS+"integer")},
+				{testNS + "a", testNS + "p", literalObject("1.5", "", xsdNS+"double")},
+				{testNS + "a", testNS + "p", literalObject("true", "", xsdNS+"boolean")},
+			},
+		},
+		{
+			name: "lists and sets",
+			input: `{
+				"@context": {"ex": "https://example.com/ns#"},
+				"@id": "ex:a",
+				"ex:p": {"@list": ["one", "two"]},
+				"ex:q": {"@set": ["three"]}
+			}`,
+			want: []rdfTriple{
+				{testNS + "a", testNS + "p", literalObject("one", "", "")},
+				{testNS + "a", testNS + "p", literalObject("two", "", "")},
+				{testNS + "a", testNS + "q", literalObject("three", "", "")},
+			},
+		},
+		{
+			name: "blank nodes",
+			input: `[
+				{"@context": {"ex": "https://example.com/ns#"}, "ex:p": {"ex:q": "nested"}},
+				{"@context": {"ex": "https://example.com/ns#"}, "@id": "_:x", "ex:r": {"@id": "_:x"}}
+			]`,
+			want: []rdfTriple{
+				{"_:b2", testNS + "q", literalObject("nested", "", "")},
+				{"_:b1", testNS + "p", iriObject("_:b2")},
+				{"_:x", testNS + "r", iriObject("_:x")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSONLD([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parseJSONLD returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONLD = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONLDErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"invalid JSON", `{"@id": `, "invalid JSON-LD"},
+		{"not a node object", `"text"`, "expected a node object"},
+		{"remote context", `{"@context": "https://schema.org/", "@id": "a"}`, "remote JSON-LD contexts are not supported"},
+		{"invalid context", `{"@context": 42}`, "invalid JSON-LD context"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseJSONLD([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseJSONLD error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rdfTriple is a parsed statement. Blank nodes are written as "_:" followed by their label.
+type rdfTriple struct {
+	subject   string
+	predicate string
+	object    rdfObject
+}
+
+// rdfObject is an IRI, a blank node or, with literal set, the lexical form of a literal
+type rdfObject struct {
+	value    string
+	literal  bool
+	language string
+	datatype string
+}
+
+func isBlankNode(value string) bool {
+	return strings.HasPrefix(value, "_:")
+}
+
+// turtleMaxNesting bounds how deeply blank node property lists may nest, so a hostile document
+// can't exhaust the stack
+const turtleMaxNesting = 64
+
+// turtleParser reads the subset of Turtle needed to exchange glossaries: prefixes, IRIs, prefixed
+// names, blank nodes, literals and the abbreviations of predicate and object lists. Collections
+// are not supported.
+type turtleParser struct {
+	src      string
+	pos      int
+	line     int
+	base     string
+	prefixes map[string]string
+	blanks   int
+	depth    int
+	triples  []rdfTriple
+}
+
+func parseTurtle(src string) ([]rdfTriple, error) {
+	p := &turtleParser{src: strings.TrimPrefix(src, "\ufeff"), line: 1, prefixes: map[string]string{}}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return p.triples, nil
+		}
+		if err := p.statement(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *turtleParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid Turtle on line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *turtleParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *turtleParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *turtleParser) advance(n int) {
+	p.line += strings.Count(p.src[p.pos:p.pos+n], "\n")
+	p.pos += n
+}
+
+// skipSpace skips whitespace and comments
+func (p *turtleParser) skipSpace() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '#':
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				end = len(p.src) - p.pos
+			}
+			p.advance(end)
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			p.advance(1)
+		default:
+			return
+		}
+	}
+}
+
+func (p *turtleParser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.advance(1)
+	return nil
+}
+
+// keyword reports whether the input continues with the word, followed by something that can't
+// continue a name
+func (p *turtleParser) keyword(word string, ignoreCase bool) bool {
+	if len(p.src)-p.pos < len(word) {
+		return false
+	}
+	next := p.src[p.pos : p.pos+len(word)]
+	if next != word && !(ignoreCase && strings.EqualFold(next, word)) {
+		return false
+	}
+	if p.pos+len(word) == len(p.src) {
+		return true
+	}
+	after := p.src[p.pos+len(word)]
+	return after != ':' && !isTurtleNameChar(after)
+}
+
+func (p *turtleParser) statement() error {
+	switch {
+	case p.keyword("@prefix", false):
+		p.advance(len("@prefix"))
+		if err := p.prefixDirective(); err != nil {
+			return err
+		}
+		return p.expect('.')
+	case p.keyword("@base", false):
+		p.advance(len("@base"))
+		p.skipSpace()
+		iri, err := p.iriRef()
+		if err != nil {
+			return err
+		}
+		p.base = iri
+		return p.expect('.')
+	case p.keyword("PREFIX", true):
+		p.advance(len("PREFIX"))
+		return p.prefixDirective()
+	case p.keyword("BASE", true):
+		p.advance(len("BASE"))
+		p.skipSpace()
+		iri, err := p.iriRef()
+		if err != nil {
+			return err
+		}
+		p.base = iri
+		return nil
+	}
+
+	var subject string
+	var err error
+	if p.peek() == '[' {
+		subject, err = p.blankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() == '.' {
+			p.advance(1)
+			return nil
+		}
+	} else if subject, err = p.resource(); err != nil {
+		return err
+	}
+
+	if err := p.predicateObjectList(subject); err != nil {
+		return err
+	}
+	return p.expect('.')
+}
+
+func (p *turtleParser) prefixDirective() error {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && p.peek() != ':' && isTurtleNameChar(p.peek()) {
+		p.advance(1)
+	}
+	if p.peek() != ':' {
+		return p.errorf("expected a prefix name")
+	}
+	prefix := p.src[start:p.pos]
+	p.advance(1)
+	p.skipSpace()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[prefix] = iri
+	return nil
+}
+
+func (p *turtleParser) predicateObjectList(subject string) error {
+	for {
+		p.skipSpace()
+		var predicate string
+		if p.keyword("a", false) {
+			p.advance(1)
+			predicate = rdfType
+		} else {
+			var err error
+			if predicate, err = p.iri(); err != nil {
+				return err
+			}
+		}
+
+		for {
+			object, err := p.object()
+			if err != nil {
+				return err
+			}
+			p.triples = append(p.triples, rdfTriple{subject: subject, predicate: predicate, object: object})
+			p.skipSpace()
+			if p.peek() != ',' {
+				break
+			}
+			p.advance(1)
+		}
+
+		if p.peek() != ';' {
+			return nil
+		}
+		for p.peek() == ';' {
+			p.advance(1)
+			p.skipSpace()
+		}
+		if c := p.peek(); c == '.' || c == ']' || p.eof() {
+			return nil
+		}
+	}
+}
+
+// blankNodePropertyList reads "[ predicate object ; ... ]" and returns the new blank node
+func (p *turtleParser) blankNodePropertyList() (string, error) {
+	if p.depth >= turtleMaxNesting {
+		return "", p.errorf("blank nodes nested too deeply")
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	p.advance(1)
+	p.blanks++
+	node := fmt.Sprintf("_:b%d", p.blanks)
+	p.skipSpace()
+	if p.peek() != ']' {
+		if err := p.predicateObjectList(node); err != nil {
+			return "", err
+		}
+	}
+	if err := p.expect(']'); err != nil {
+		return "", err
+	}
+	return node, nil
+}
+
+// resource reads a subject or object that is not a literal: an IRI, a prefixed name or a blank node
+func (p *turtleParser) resource() (string, error) {
+	p.skipSpace()
+	switch {
+	case p.peek() == '(':
+		return "", p.errorf("collections are not supported")
+	case strings.HasPrefix(p.src[p.pos:], "_:"):
+		p.advance(2)
+		return "_:" + p.name(), nil
+	}
+	return p.iri()
+}
+
+func (p *turtleParser) object() (rdfObject, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '[':
+		node, err := p.blankNodePropertyList()
+		return rdfObject{value: node}, err
+	case c == '"' || c == '\'':
+		return p.literal()
+	case c == '+' || c == '-' || (c >= '0' && c <= '9') || (c == '.' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9'):
+		return p.number()
+	case p.keyword("true", false) || p.keyword("false", false):
+		value := "true"
+		if c == 'f' {
+			value = "false"
+		}
+		p.advance(len(value))
+		return rdfObject{value: value, literal: true, datatype: xsdNS + "boolean"}, nil
+	}
+	value, err := p.resource()
+	return rdfObject{value: value}, err
+}
+
+// iri reads an IRI reference or a prefixed name and returns the absolute IRI
+func (p *turtleParser) iri() (string, error) {
+	p.skipSpace()
+	if p.peek() == '<' {
+		return p.iriRef()
+	}
+
+	name := p.name()
+	colon := strings.IndexByte(name, ':')
+	if colon < 0 {
+		if name == "" && !p.eof() {
+			return "", p.errorf("unexpected %q", p.peek())
+		}
+		return "", p.errorf("expected an IRI, found %q", name)
+	}
+	namespace, ok := p.prefixes[name[:colon]]
+	if !ok {
+		return "", p.errorf("undefined prefix %q", name[:colon])
+	}
+	return namespace + unescapeTurtleLocal(name[colon+1:]), nil
+}
+
+func (p *turtleParser) iriRef() (string, error) {
+	if p.peek() != '<' {
+		return "", p.errorf("expected an IRI")
+	}
+	end := strings.IndexByte(p.src[p.pos:], '>')
+	if end < 0 {
+		return "", p.errorf("unterminated IRI")
+	}
+	raw := p.src[p.pos+1 : p.pos+end]
+	p.advance(end + 1)
+
+	iri, err := unescapeTurtleString(raw)
+	if err != nil {
+		return "", p.errorf("%v", err)
+	}
+	return p.resolve(iri), nil
+}
+
+// resolve makes a relative IRI absolute against the base IRI
+func (p *turtleParser) resolve(iri string) string {
+	if p.base == "" || strings.Contains(strings.SplitN(iri, "/", 2)[0], ":") {
+		return iri
+	}
+	switch {
+	case iri == "":
+		return p.base
+	case iri[0] == '#':
+		return strings.SplitN(p.base, "#", 2)[0] + iri
+	case iri[0] == '/':
+		if scheme := strings.Index(p.base, "://"); scheme >= 0 {
+			if path := strings.IndexByte(p.base[scheme+3:], '/'); path >= 0 {
+				return p.base[:scheme+3+path] + iri
+			}
+		}
+		return strings.TrimSuffix(p.base, "/") + iri
+	}
+	return p.base[:strings.LastIndexAny(p.base, "/#")+1] + iri
+}
+
+// name reads a prefixed name or blank node label. A trailing dot ends the statement instead.
+func (p *turtleParser) name() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '\\' && p.pos+1 < len(p.src) {
+			p.advance(2)
+			continue
+		}
+		if !isTurtleNameChar(c) && c != ':' {
+			break
+		}
+		p.advance(1)
+	}
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+	return p.src[start:p.pos]
+}
+
+func isTurtleNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == '%' || c >= utf8.RuneSelf
+}
+
+func unescapeTurtleLocal(local string) string {
+	if !strings.Contains(local, `\`) {
+		return local
+	}
+	var out strings.Builder
+	for i := 0; i < len(local); i++ {
+		if local[i] == '\\' && i+1 < len(local) {
+			i++
+		}
+		out.WriteByte(local[i])
+	}
+	return out.String()
+}
+
+func (p *turtleParser) literal() (rdfObject, error) {
+	quote := p.src[p.pos : p.pos+1]
+	long := strings.HasPrefix(p.src[p.pos:], strings.Repeat(quote, 3))
+	delimiter := quote
+	if long {
+		delimiter = strings.Repeat(quote, 3)
+	}
+	p.advance(len(delimiter))
+
+	start := p.pos
+	for {
+		if p.eof() {
+			return rdfObject{}, p.errorf("unterminated string")
+		}
+		c := p.peek()
+		if c == '\\' {
+			p.advance(2)
+			continue
+		}
+		if !long && (c == '\n' || c == '\r') {
+			return rdfObject{}, p.errorf("line break in a short string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delimiter) {
+			// a long string may end with up to two quotes of its own
+			for long && strings.HasPrefix(p.src[p.pos+1:], delimiter) {
+				p.advance(1)
+			}
+			break
+		}
+		p.advance(1)
+	}
+	value, err := unescapeTurtleString(p.src[start:p.pos])
+	if err != nil {
+		return rdfObject{}, p.errorf("%v", err)
+	}
+	p.advance(len(delimiter))
+
+	object := rdfObject{value: value, literal: true}
+	switch {
+	case p.peek() == '@':
+		p.advance(1)
+		start := p.pos
+		for !p.eof() && (isTurtleNameChar(p.peek()) && p.peek() != '.' && p.peek() != '_') {
+			p.advance(1)
+		}
+		object.language = strings.ToLower(p.src[start:p.pos])
+		if object.language == "" {
+			return rdfObject{}, p.errorf("expected a language tag")
+		}
+	case strings.HasPrefix(p.src[p.pos:], "^^"):
+		p.advance(2)
+		if object.datatype, err = p.iri(); err != nil {
+			return rdfObject{}, err
+		}
+	}
+	return object, nil
+}
+
+func (p *turtleParser) number() (rdfObject, error) {
+	start := p.pos
+	if c := p.peek(); c == '+' || c == '-' {
+		p.advance(1)
+	}
+	datatype := xsdNS + "integer"
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9':
+			datatype = xsdNS + "decimal"
+		case c == 'e' || c == 'E':
+			datatype = xsdNS + "double"
+			if n := p.src[p.pos+1:]; strings.HasPrefix(n, "+") || strings.HasPrefix(n, "-") {
+				p.advance(1)
+			}
+		default:
+			if p.pos == start || !strings.ContainsAny(p.src[start:p.pos], "0123456789") {
+				return rdfObject{}, p.errorf("invalid number")
+			}
+			return rdfObject{value: p.src[start:p.pos], literal: true, datatype: datatype}, nil
+		}
+		p.advance(1)
+	}
+	return rdfObject{value: p.src[start:p.pos], literal: true, datatype: datatype}, nil
+}
+
+// unescapeTurtleString resolves the string and numeric escapes of Turtle strings and IRIs
+func unescapeTurtleString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			out.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("dangling escape")
+		}
+		i++
+		switch s[i] {
+		case 't':
+			out.WriteByte('\t')
+		case 'b':
+			out.WriteByte('\b')
+		case 'n':
+			out.WriteByte('\n')
+		case 'r':
+			out.WriteByte('\r')
+		case 'f':
+			out.WriteByte('\f')
+		case '"', '\'', '\\':
+			out.WriteByte(s[i])
+		case 'u', 'U':
+			size := 4
+			if s[i] == 'U' {
+				size = 8
+			}
+			if i+size >= len(s) {
+				return "", fmt.Errorf("invalid escape \\%c", s[i])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape \\%s", s[i:i+1+size])
+			}
+			out.WriteRune(rune(code))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testNS = "https://example.com/ns#"
+
+func iriObject(iri string) rdfObject {
+	return rdfObject{value: iri}
+}
+
+func literalObject(value, language, datatype string) rdfObject {
+	return rdfObject{value: value, literal: true, language: language, datatype: datatype}
+}
+
+func TestParseTurtle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []rdfTriple
+	}{
+		{
+			name:  "full IRIs",
+			input: `<https://example.com/a> <https://example.com/p> <https://example.com/b> .`,
+			want:  []rdfTriple{{"https://example.com/a", "https://example.com/p", iriObject("https://example.com/b")}},
+		},
+		{
+			name: "prefixed names",
+			input: `@prefix ex: <https://example.com/ns#> .
+				PREFIX : <https://example.com/default#>
+				ex:a :p ex:b.c .`,
+			want: []rdfTriple{{testNS + "a", "https://example.com/default#p", iriObject(testNS + "b.c")}},
+		},
+		{
+			name: "escaped local names",
+			input: `@prefix ex: <https://example.com/ns#> .
+				ex:a\-b ex:p ex:c\.d\/e .`,
+			want: []rdfTriple{{testNS + "a-b", testNS + "p", iriObject(testNS + "c.d/e")}},
+		},
+		{
+			name: "base and relative IRIs",
+			input: `@base <https://example.com/dir/doc> .
+				<a> <#p> </root> .
+				BASE <https://example.com/other/>
+				<b> <q> <> .`,
+			want: []rdfTriple{
+				{"https://example.com/dir/a", "https://example.com/dir/doc#p", iriObject("https://example.com/root")},
+				{"https://example.com/other/b", "https://example.com/other/q", iriObject("https://example.com/other/")},
+			},
+		},
+		{
+			name: "predicate and object lists",
+			input: `@prefix ex: <https://example.com/ns#> .
+				ex:a a ex:Concept, ex:Term ;
+					ex:p ex:b ;;
+					ex:q ex:c ;
+				.`,
+			want: []rdfTriple{
+				{testNS + "a", rdfType, iriObject(testNS + "Concept")},
+				{testNS + "a", rdfType, iriObject(testNS + "Term")},
+				{testNS + "a", testNS + "p", iriObject(testNS + "b")},
+				{testNS + "a", testNS + "q", iriObject(testNS + "c")},
+			},
+		},
+		{
+			name: "blank nodes",
+			input: `@prefix ex: <https://example.com/ns#> .
+				_:x ex:p [ ex:q "nested" ; ex:r [ ex:s ex:t ] ] .
+				[ ex:u ex:v ] .
+				[ ex:w ex:x ] ex:y _:x .`,
+			want: []rdfTriple{
+				{"_:b1", testNS + "q", literalObject("nested", "", "")},
+				{"_:b2", testNS + "s", iriObject(testNS + "t")},
+				{"_:b1", testNS + "r", iriObject("_:b2")},
+				{"_:x", testNS + "p", iriObject("_:b1")},
+				{"_:b3", testNS + "u", iriObject(testNS + "v")},
+				{"_:b4", testNS + "w", iriObject(testNS + "x")},
+				{"_:b4", testNS + "y", iriObject("_:x")},
+			},
+		},
+		{
+			name: "string escapes",
+			input: `<https://example.com/a> <https://example.com/p>
+				"tab\tnewline\nquote\"apostrophe\'backslash\\",
+				"\u00e9\U0001F4B6",
+				'single "quoted"' .`,
+			want: []rdfTriple{
+				{"https://example.com/a", "https://example.com/p", literalObject("tab\tnewline\nquote\"apostrophe'backslash\\", "", "")},
+				{"https://example.com/a", "https://example.com/p", literalObject("é💶", "", "")},
+				{"https://example.com/a", "https://example.com/p", literalObject(`single "quoted"`, "", "")},
+			},
+		},
+		{
+			name:  "long strings",
+			input: "<https://example.com/a> <https://example.com/p> \"\"\"line one\n\"quoted\" and \"\"two\"\"\"\"\" , '''it's''' .",
+			want: []rdfTriple{
+				{"https://example.com/a", "https://example.com/p", literalObject("line one\n\"quoted\" and \"\"two\"\"", "", "")},
+				{"https://example.com/a", "https://example.com/p", literalObject("it's", "", "")},
+			},
+		},
+		{
+			name: "language tags and datatypes",
+			input: `@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+				<https://example.com/a> <https://example.com/p> "Marge"@FR-be, "2026-01-02"^^xsd:date, "x"^^<https://example.com/t> .`,
+			want: []rdfTriple{
+				{"https://example.com/a", "https://example.com/p", literalObject("Marge", "fr-be", "")},
+				{"https://example.com/a", "https://example.com/p", literalObject("2026-01-02", "", xsdNS+"date")},
+				{"https://example.com/a", "https://example.com/p", literalObject("x", "", "https://example.com/t")},
+			},
+		},
+		{
+			name:  "numbers and booleans",
+			input: `<https://example.com/a> <https://example.com/p> 42, -1.5, 2e10, true, false .`,
+			want: []rdfTriple{
+				{"https://example.com/a", "https://example.com/p", literalObject("42", "", xsdNS+"integer")},
+				{"https://example.com/a", "https://example.com/p", literalObject("-1.5", "", xsdNS+"decimal")},
+				{"https://example.com/a", "https://example.com/p", literalObject("2e10", "", xsdNS+"double")},
+				{"https://example.com/a", "https://example.com/p", literalObject("true", "", xsdNS+"boolean")},
+				{"https://example.com/a", "https://example.com/p", literalObject("false", "", xsdNS+"boolean")},
+			},
+		},
+		{
+			name:  "comments and byte order mark",
+			input: "\ufeff# a comment\n<https://example.com/a> # another\n<https://example.com/p> \"#not a comment\" . # end",
+			want:  []rdfTriple{{"https://example.com/a", "https://example.com/p", literalObject("#not a comment", "", "")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTurtle(tt.input)
+			if err != nil {
+				t.Fatalf("parseTurtle returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTurtle = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTurtleErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"undefined prefix", "\n\nex:a ex:p ex:b .", `line 3: undefined prefix "ex"`},
+		{"unterminated string", `<https://example.com/a> <https://example.com/p> "open .`, "unterminated string"},
+		{"line break in short string", "<https://example.com/a> <https://example.com/p> \"a\nb\" .", "line break in a short string"},
+		{"invalid escape", `<https://example.com/a> <https://example.com/p> "\q" .`, `invalid escape \q`},
+		{"invalid unicode escape", `<https://example.com/a> <https://example.com/p> "\u12" .`, `invalid escape`},
+		{"collections", `<https://example.com/a> <https://example.com/p> ( 1 2 ) .`, "collections are not supported"},
+		{"missing dot", `<https://example.com/a> <https://example.com/p> <https://example.com/b>`, `expected '.'`},
+		{"unterminated IRI", `<https://example.com/a`, "unterminated IRI"},
+		{"missing language", `<https://example.com/a> <https://example.com/p> "x"@ .`, "expected a language tag"},
+		{"deeply nested blank nodes", "<https://example.com/a> " + strings.Repeat("<https://example.com/p> [", 1000000), "blank nodes nested too deeply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTurtle(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseTurtle error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTurtleEscapes(t *testing.T) {
+	node := &rdfNode{iri: "https://example.com/a b<c>", types: []string{skosNS + "Concept"}}
+	node.add(skosNS+"prefLabel", rdfText("say \"hi\"\\\n", "en"))
+	node.add(testNS+"odd local", rdfString("x"))
+
+	out := string(writeTurtle([]*rdfNode{node}))
+	for _, want := range []string{
+		"<https://example.com/a%20b%3Cc%3E>",
+		"a skos:Concept",
+		`skos:prefLabel "say \"hi\"\\\n"@en`,
+		`<https://example.com/ns#odd%20local> "x"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("writeTurtle output lacks %q:\n%s", want, out)
+		}
+	}
+
+	triples, err := parseTurtle(out)
+	if err != nil {
+		t.Fatalf("parseTurtle of written Turtle returned error: %v", err)
+	}
+	if got := triples[1].object; got != literalObject("say \"hi\"\\\n", "en", "") {
+		t.Errorf("label read back as %+v", got)
+	}
+}
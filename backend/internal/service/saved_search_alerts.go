@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSavedSearchCheckInterval = time.Hour
+
+	// savedSearchNotificationNames is how many term names a notification lists per change
+	savedSearchNotificationNames = 5
+)
+
+// SavedSearchRequest turns a saved search into a search request, seeing the terms visible to the
+// owner's department. The second result reports whether context definitions take part, as for
+// GET /search with cluster, system or product filters.
+func SavedSearchRequest(search *models.SavedSearch) (models.SearchRequest, bool, error) {
+	req := models.SearchRequest{
+		Query:                search.Query,
+		Categories:           search.Filters.Categories,
+		Clusters:             search.Filters.Clusters,
+		Systems:              search.Filters.Systems,
+		Products:             search.Filters.Products,
+		Tags:                 search.Filters.Tags,
+		ComplianceFrameworks: search.Filters.ComplianceFrameworks,
+		Visibility:           search.Filters.Visibility,
+		UserDepartment:       search.Department,
+	}
+
+	expr, err := ParseSearchQuery(search.Query)
+	if err != nil {
+		return req, false, err
+	}
+	req.Expr = expr
+
+	withContexts := len(req.Clusters) > 0 || len(req.Systems) > 0 || len(req.Products) > 0
+	return req, withContexts, nil
+}
+
+// SavedSearchAlertService periodically reruns saved searches with notify enabled and notifies
+// their owners when terms enter or leave the result set. The first check of a search only
+// records its results.
+//
+// Configuration (environment):
+//   - SAVED_SEARCH_CHECK_MINUTES: interval between checks (default 60)
+type SavedSearchAlertService struct {
+	repo          *repository.SavedSearchRepository
+	termRepo      *repository.TermRepository
+	checkInterval time.Duration
+
+	// mu prevents the periodic check and a manual check from overlapping
+	mu sync.Mutex
+}
+
+func NewSavedSearchAlertService() *SavedSearchAlertService {
+	return &SavedSearchAlertService{
+		repo:          repository.NewSavedSearchRepository(),
+		termRepo:      repository.NewTermRepository(),
+		checkInterval: time.Duration(envInt("SAVED_SEARCH_CHECK_MINUTES", int(defaultSavedSearchCheckInterval/time.Minute))) * time.Minute,
+	}
+}
+
+// Start checks saved searches every interval until ctx is cancelled
+func (s *SavedSearchAlertService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			result, err := s.Run(ctx)
+			if err != nil {
+				log.Printf("Saved search alert check failed: %v", err)
+				continue
+			}
+			log.Printf("Saved search alerts: %d checked, %d notifications, %d failed in %dms",
+				result.Checked, result.Notifications, result.Failed, result.DurationMs)
+		}
+	}()
+}
+
+// Run checks every saved search with notify enabled. A search that fails is logged and counted,
+// and does not stop the others.
+func (s *SavedSearchAlertService) Run(ctx context.Context) (*models.SavedSearchAlertResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	result := &models.SavedSearchAlertResult{RanAt: start}
+
+	searches, err := s.repo.ListNotifyingSavedSearches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range searches {
+		notified, err := s.check(ctx, &searches[i])
+		if err != nil {
+			log.Printf("Warning: Failed to check saved search %s: %v", searches[i].ID, err)
+			result.Failed++
+			continue
+		}
+		result.Checked++
+		if notified {
+			result.Notifications++
+		}
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// CheckScope reports an error when a search matches too many terms to track for change alerts
+func (s *SavedSearchAlertService) CheckScope(ctx context.Context, search *models.SavedSearch) error {
+	req, withContexts, err := SavedSearchRequest(search)
+	if err != nil {
+		return err
+	}
+	_, err = s.termRepo.SearchTermIDs(ctx, req, withContexts)
+	return err
+}
+
+// check reruns one saved search and records its results, notifying the owner of changes. A
+// search that has grown past repository.SearchResultCap fails without touching its baseline.
+func (s *SavedSearchAlertService) check(ctx context.Context, search *models.SavedSearch) (bool, error) {
+	req, withContexts, err := SavedSearchRequest(search)
+	if err != nil {
+		return false, err
+	}
+
+	termIDs, err := s.termRepo.SearchTermIDs(ctx, req, withContexts)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	var notification *models.Notification
+	if search.LastCheckedAt != nil {
+		added, removed := diffTermIDs(search.LastResultIDs, termIDs)
+		if len(added) > 0 || len(removed) > 0 {
+			message, err := s.changeMessage(ctx, search, added, removed)
+			if err != nil {
+				return false, err
+			}
+			notification = &models.Notification{
+				ID:          uuid.New(),
+				UserID:      search.UserID,
+				Type:        "saved_search",
+				Message:     message,
+				ReferenceID: &search.ID,
+				CreatedAt:   now,
+			}
+		}
+	}
+
+	if err := s.repo.RecordSavedSearchResults(ctx, search.ID, termIDs, now, notification); err != nil {
+		return false, err
+	}
+	return notification != nil, nil
+}
+
+// diffTermIDs returns the IDs in current but not previous, and in previous but not current
+func diffTermIDs(previous, current []uuid.UUID) ([]uuid.UUID, []uuid.UUID) {
+	before := make(map[uuid.UUID]bool, len(previous))
+	for _, id := range previous {
+		before[id] = true
+	}
+	now := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		now[id] = true
+	}
+
+	added := []uuid.UUID{}
+	for _, id := range current {
+		if !before[id] {
+			added = append(added, id)
+		}
+	}
+	removed := []uuid.UUID{}
+	for _, id := range previous {
+		if !now[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+// changeMessage describes the change, e.g. `Saved search "GDPR Retail": 2 new terms (Consent, Data
+// Subject); 1 term no longer matches (Opt-in)`
+func (s *SavedSearchAlertService) changeMessage(ctx context.Context, search *models.SavedSearch, added, removed []uuid.UUID) (string, error) {
+	names, err := s.termRepo.GetTermNamesByIDs(ctx, append(append([]uuid.UUID{}, added...), removed...), search.Department)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	if len(added) > 0 {
+		parts = append(parts, fmt.Sprintf("%s (%s)", pluralTerms(len(added), "new term", "new terms"), listTermNames(added, names)))
+	}
+	if len(removed) > 0 {
+		verb := "match"
+		if len(removed) == 1 {
+			verb = "matches"
+		}
+		parts = append(parts, fmt.Sprintf("%s no longer %s (%s)", pluralTerms(len(removed), "term", "terms"), verb, listTermNames(removed, names)))
+	}
+	return fmt.Sprintf("Saved search %q: %s", search.Name, strings.Join(parts, "; ")), nil
+}
+
+func pluralTerms(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// listTermNames lists up to savedSearchNotificationNames names alphabetically; terms deleted or
+// hidden from the owner since are only counted
+func listTermNames(ids []uuid.UUID, names map[uuid.UUID]string) string {
+	known := []string{}
+	unavailable := 0
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			known = append(known, name)
+		} else {
+			unavailable++
+		}
+	}
+	sort.Strings(known)
+
+	parts := known
+	if len(known) > savedSearchNotificationNames {
+		parts = append(known[:savedSearchNotificationNames:savedSearchNotificationNames], fmt.Sprintf("%d more", len(known)-savedSearchNotificationNames))
+	}
+	if unavailable > 0 {
+		parts = append(parts, pluralTerms(unavailable, "unavailable term", "unavailable terms"))
+	}
+	return strings.Join(parts, ", ")
+}
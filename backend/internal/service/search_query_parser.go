@@ -0,0 +1,354 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"clarityconnect/internal/models"
+)
+
+// searchQueryFields maps the field prefixes accepted in advanced queries to SearchExpr fields
+var searchQueryFields = map[string]string{
+	"category":  "category",
+	"cluster":   "cluster",
+	"system":    "system",
+	"product":   "product",
+	"framework": "framework",
+	"tag":       "tag",
+	"code":      "code",
+	"updated":   "updated",
+	"created":   "created",
+}
+
+// SearchQueryError reports a syntax error in an advanced search query
+type SearchQueryError struct {
+	Position int    `json:"position"` // byte offset of the offending token
+	Token    string `json:"token"`
+	Message  string `json:"error"`
+}
+
+func (e *SearchQueryError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+	}
+	return fmt.Sprintf("%s at position %d (%q)", e.Message, e.Position, e.Token)
+}
+
+type searchTokenKind int
+
+const (
+	tokenWord searchTokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenOr
+	tokenAnd
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type searchToken struct {
+	kind  searchTokenKind
+	pos   int
+	raw   string
+	field string // for tokenField
+	value string // word, phrase or field value
+}
+
+// ParseSearchQuery parses the advanced search syntax:
+//
+//	"quoted phrase"  a OR b  -word  NOT word  (a OR b) c
+//	category:Risk cluster:Retail framework:"BCBS 239" tag:kpi code:CUST_* updated:>2026-01-01
+//
+// Terms are ANDed unless joined by OR. Field values are case-insensitive and a trailing * matches
+// a prefix. A colon after a word that is not a field name is plain text ("Basel III: capital").
+// It returns nil when the query is plain text, so the default search applies.
+func ParseSearchQuery(input string) (*models.SearchExpr, error) {
+	tokens, err := tokenizeSearchQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := true
+	for _, tok := range tokens {
+		if tok.kind != tokenWord {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return nil, nil
+	}
+
+	p := &searchQueryParser{tokens: tokens, end: len(input)}
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "unexpected closing parenthesis"}
+	}
+
+	return expr, nil
+}
+
+func tokenizeSearchQuery(input string) ([]searchToken, error) {
+	tokens := []searchToken{}
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, searchToken{kind: tokenLParen, pos: i, raw: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, searchToken{kind: tokenRParen, pos: i, raw: ")"})
+			i++
+		case c == '"':
+			value, next, err := readQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(value) == "" {
+				return nil, &SearchQueryError{Position: i, Token: input[i:next], Message: "empty phrase"}
+			}
+			tokens = append(tokens, searchToken{kind: tokenPhrase, pos: i, raw: input[i:next], value: value})
+			i = next
+		case c == '-' && i+1 < len(input) && !isSearchSeparator(input[i+1]):
+			tokens = append(tokens, searchToken{kind: tokenNot, pos: i, raw: "-"})
+			i++
+		default:
+			tok, next, err := readWord(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+	return tokens, nil
+}
+
+func isSearchSeparator(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')'
+}
+
+// readQuoted reads a double-quoted string starting at input[start]
+func readQuoted(input string, start int) (string, int, error) {
+	end := strings.IndexByte(input[start+1:], '"')
+	if end < 0 {
+		return "", 0, &SearchQueryError{Position: start, Token: input[start:], Message: "unterminated quote"}
+	}
+	return input[start+1 : start+1+end], start + end + 2, nil
+}
+
+// readWord reads a bare word, an operator or a field:value pair
+func readWord(input string, start int) (searchToken, int, error) {
+	i := start
+	for i < len(input) && !isSearchSeparator(input[i]) && input[i] != ':' && input[i] != '"' {
+		i++
+	}
+	word := input[start:i]
+
+	field, isField := searchQueryFields[strings.ToLower(word)]
+	if i < len(input) && input[i] == ':' && isField {
+		valueStart := i + 1
+		var value string
+		next := valueStart
+		if valueStart < len(input) && input[valueStart] == '"' {
+			quoted, after, err := readQuoted(input, valueStart)
+			if err != nil {
+				return searchToken{}, 0, err
+			}
+			value, next = quoted, after
+		} else {
+			for next < len(input) && !isSearchSeparator(input[next]) {
+				next++
+			}
+			value = input[valueStart:next]
+		}
+
+		raw := input[start:next]
+		if strings.TrimSpace(value) == "" {
+			return searchToken{}, 0, &SearchQueryError{Position: start, Token: raw, Message: "missing value for field " + word}
+		}
+		return searchToken{kind: tokenField, pos: start, raw: raw, field: field, value: value}, next, nil
+	}
+
+	// A colon or quote that does not start a field is part of the word
+	for i < len(input) && !isSearchSeparator(input[i]) {
+		i++
+	}
+	word = input[start:i]
+
+	switch word {
+	case "OR":
+		return searchToken{kind: tokenOr, pos: start, raw: word}, i, nil
+	case "AND":
+		return searchToken{kind: tokenAnd, pos: start, raw: word}, i, nil
+	case "NOT":
+		return searchToken{kind: tokenNot, pos: start, raw: word}, i, nil
+	}
+	return searchToken{kind: tokenWord, pos: start, raw: word, value: word}, i, nil
+}
+
+type searchQueryParser struct {
+	tokens []searchToken
+	pos    int
+	end    int // length of the input, reported for errors at the end of the query
+}
+
+func (p *searchQueryParser) peek() *searchToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+// parseAnd parses a sequence of OR-groups up to the end of the query or a closing parenthesis
+func (p *searchQueryParser) parseAnd() (*models.SearchExpr, error) {
+	children := []*models.SearchExpr{}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind == tokenRParen {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.pos++
+			if next := p.peek(); next == nil || next.kind == tokenRParen || next.kind == tokenOr || next.kind == tokenAnd {
+				return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "AND must be followed by a search term"}
+			}
+			if len(children) == 0 {
+				return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "AND must follow a search term"}
+			}
+			continue
+		}
+
+		child, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 0 {
+		pos := p.end
+		if tok := p.peek(); tok != nil {
+			pos = tok.pos
+		}
+		return nil, &SearchQueryError{Position: pos, Message: "expected a search term"}
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &models.SearchExpr{Op: "and", Children: children}, nil
+}
+
+func (p *searchQueryParser) parseOr() (*models.SearchExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*models.SearchExpr{left}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind != tokenOr {
+			break
+		}
+		p.pos++
+		if next := p.peek(); next == nil || next.kind == tokenRParen || next.kind == tokenOr || next.kind == tokenAnd {
+			return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "OR must be followed by a search term"}
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &models.SearchExpr{Op: "or", Children: children}, nil
+}
+
+func (p *searchQueryParser) parseUnary() (*models.SearchExpr, error) {
+	tok := p.peek()
+	if tok != nil && tok.kind == tokenNot {
+		p.pos++
+		if next := p.peek(); next == nil || next.kind == tokenRParen || next.kind == tokenOr || next.kind == tokenAnd {
+			return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "negation must be followed by a search term"}
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &models.SearchExpr{Op: "not", Children: []*models.SearchExpr{child}}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *searchQueryParser) parsePrimary() (*models.SearchExpr, error) {
+	tok := p.peek()
+	if tok == nil {
+		return nil, &SearchQueryError{Position: p.end, Message: "expected a search term"}
+	}
+
+	switch tok.kind {
+	case tokenWord:
+		p.pos++
+		return &models.SearchExpr{Op: "text", Value: tok.value}, nil
+	case tokenPhrase:
+		p.pos++
+		return &models.SearchExpr{Op: "phrase", Value: tok.value}, nil
+	case tokenField:
+		p.pos++
+		return parseFieldExpr(tok)
+	case tokenLParen:
+		p.pos++
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != tokenRParen {
+			return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "missing closing parenthesis"}
+		}
+		p.pos++
+		return expr, nil
+	case tokenOr:
+		return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "OR must follow a search term"}
+	default:
+		return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "unexpected closing parenthesis"}
+	}
+}
+
+// parseFieldExpr validates a field:value token. Date fields accept =, >, >=, < and <= prefixes.
+func parseFieldExpr(tok *searchToken) (*models.SearchExpr, error) {
+	expr := &models.SearchExpr{Op: "field", Field: tok.field, Operator: "=", Value: tok.value}
+
+	if tok.field != "updated" && tok.field != "created" {
+		return expr, nil
+	}
+
+	value := tok.value
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			expr.Operator = op
+			value = value[len(op):]
+			break
+		}
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return nil, &SearchQueryError{Position: tok.pos, Token: tok.raw, Message: "invalid date for " + tok.field + ", expected YYYY-MM-DD"}
+	}
+	expr.Value = value
+
+	return expr, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"clarityconnect/internal/models"
+)
+
+func text(value string) *models.SearchExpr {
+	return &models.SearchExpr{Op: "text", Value: value}
+}
+
+func field(name, operator, value string) *models.SearchExpr {
+	return &models.SearchExpr{Op: "field", Field: name, Operator: operator, Value: value}
+}
+
+func and(children ...*models.SearchExpr) *models.SearchExpr {
+	return &models.SearchExpr{Op: "and", Children: children}
+}
+
+func or(children ...*models.SearchExpr) *models.SearchExpr {
+	return &models.SearchExpr{Op: "or", Children: children}
+}
+
+func not(child *models.SearchExpr) *models.SearchExpr {
+	return &models.SearchExpr{Op: "not", Children: []*models.SearchExpr{child}}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *models.SearchExpr
+	}{
+		{"plain words", "liquidity coverage ratio", nil},
+		{"empty", "", nil},
+		{"colon after text", "Basel III: capital", nil},
+		{"colon after unknown word", "ratio: definition", nil},
+		{"url", "see http://intranet/glossary", nil},
+		{"hyphenated word", "loan-to-value", nil},
+		{"lone hyphen", "capital - buffer", nil},
+		{"phrase", `"net interest margin"`, &models.SearchExpr{Op: "phrase", Value: "net interest margin"}},
+		{"or", "loan OR mortgage", or(text("loan"), text("mortgage"))},
+		{"and binds looser than or", "a b OR c", and(text("a"), or(text("b"), text("c")))},
+		{"explicit and", "a AND b", and(text("a"), text("b"))},
+		{"minus negation", "liquidity -retail", and(text("liquidity"), not(text("retail")))},
+		{"not keyword", "NOT retail", not(text("retail"))},
+		{"lowercase or is text", "loan or mortgage", nil},
+		{"parentheses", "(a OR b) c", and(or(text("a"), text("b")), text("c"))},
+		{"field", "category:Risk", field("category", "=", "Risk")},
+		{"field name is case-insensitive", "Category:Risk", field("category", "=", "Risk")},
+		{"quoted field value", `framework:"BCBS 239"`, field("framework", "=", "BCBS 239")},
+		{"prefix field value", "code:CUST_*", field("code", "=", "CUST_*")},
+		{"date comparison", "updated:>=2026-01-01", field("updated", ">=", "2026-01-01")},
+		{"date equality", "created:2026-03-15", field("created", "=", "2026-03-15")},
+		{"unknown prefix beside field", "ratio: category:Risk", and(text("ratio:"), field("category", "=", "Risk"))},
+		{"url beside field", "http://intranet tag:kpi", and(text("http://intranet"), field("tag", "=", "kpi"))},
+		{"negated field", "liquidity -cluster:Retail", and(text("liquidity"), not(field("cluster", "=", "Retail")))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q) returned error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchQuery(%q) = %s, want %s", tt.input, exprString(got), exprString(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+		message  string
+	}{
+		{"unterminated quote", `risk "capital`, 5, "unterminated quote"},
+		{"empty phrase", `risk ""`, 5, "empty phrase"},
+		{"missing field value", "category: risk", 0, "missing value for field category"},
+		{"invalid date", "updated:>yesterday", 0, "invalid date for updated"},
+		{"dangling or", "loan OR", 5, "OR must be followed by a search term"},
+		{"leading or", "OR loan", 0, "OR must follow a search term"},
+		{"dangling and", "loan AND", 5, "AND must be followed by a search term"},
+		{"dangling not", "loan NOT", 5, "negation must be followed by a search term"},
+		{"missing closing parenthesis", "(a OR b", 0, "missing closing parenthesis"},
+		{"extra closing parenthesis", "a OR b)", 6, "unexpected closing parenthesis"},
+		{"empty parentheses", "() tag:kpi", 1, "expected a search term"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.input)
+			var queryErr *SearchQueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseSearchQuery(%q) error = %v, want a SearchQueryError", tt.input, err)
+			}
+			if queryErr.Position != tt.position || !strings.HasPrefix(queryErr.Message, tt.message) {
+				t.Errorf("ParseSearchQuery(%q) error = %q at %d, want %q at %d",
+					tt.input, queryErr.Message, queryErr.Position, tt.message, tt.position)
+			}
+		})
+	}
+}
+
+// exprString renders an expression for test failure messages
+func exprString(e *models.SearchExpr) string {
+	if e == nil {
+		return "<plain>"
+	}
+	switch e.Op {
+	case "text":
+		return e.Value
+	case "phrase":
+		return `"` + e.Value + `"`
+	case "field":
+		return e.Field + ":" + e.Operator + e.Value
+	}
+	parts := make([]string, 0, len(e.Children))
+	for _, child := range e.Children {
+		parts = append(parts, exprString(child))
+	}
+	return e.Op + "(" + strings.Join(parts, " ") + ")"
+}
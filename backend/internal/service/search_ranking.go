@@ -0,0 +1,50 @@
+package service
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"clarityconnect/internal/models"
+)
+
+// Default weights of the personalized ranking blend. Text ranks of good matches are roughly
+// 0.1-1, so a term used 100 times gains about 0.23 from popularity.
+const (
+	defaultPopularityWeight = 0.05
+	defaultAffinityWeight   = 0.1
+	defaultCertifiedBoost   = 0.2
+	defaultApprovedBoost    = 0.05
+	defaultDeprecatedDemote = 0.3
+)
+
+// SearchRankWeightsFromEnv returns the personalized ranking weights.
+//
+// Configuration (environment):
+//   - SEARCH_WEIGHT_POPULARITY: weight of ln(1 + uses) by everyone (default 0.05)
+//   - SEARCH_WEIGHT_AFFINITY: weight of ln(1 + uses) from the caller's cluster or department (default 0.1)
+//   - SEARCH_BOOST_CERTIFIED, SEARCH_BOOST_APPROVED: added for certified and approved terms (default 0.2, 0.05)
+//   - SEARCH_DEMOTE_DEPRECATED: subtracted for deprecated terms (default 0.3)
+func SearchRankWeightsFromEnv() models.SearchRankWeights {
+	return models.SearchRankWeights{
+		Popularity: envNonNegativeFloat("SEARCH_WEIGHT_POPULARITY", defaultPopularityWeight),
+		Affinity:   envNonNegativeFloat("SEARCH_WEIGHT_AFFINITY", defaultAffinityWeight),
+		Certified:  envNonNegativeFloat("SEARCH_BOOST_CERTIFIED", defaultCertifiedBoost),
+		Approved:   envNonNegativeFloat("SEARCH_BOOST_APPROVED", defaultApprovedBoost),
+		Deprecated: envNonNegativeFloat("SEARCH_DEMOTE_DEPRECATED", defaultDeprecatedDemote),
+	}
+}
+
+func envNonNegativeFloat(key string, def float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		log.Printf("Warning: ignoring invalid %s=%q", key, value)
+		return def
+	}
+	return parsed
+}
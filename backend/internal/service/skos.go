@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SKOS serializations
+const (
+	SKOSFormatTurtle = "turtle"
+	SKOSFormatJSONLD = "jsonld"
+)
+
+const (
+	// skosDefaultBaseURI is used for concept IRIs when SKOS_BASE_URI is not set
+	skosDefaultBaseURI = "https://clarityconnect.local/glossary"
+
+	skosLanguage = "en"
+)
+
+// skosRelationships maps relationship types to properties. Parent and child relationships are
+// SKOS hierarchy, the types without a SKOS counterpart use the glossary vocabulary.
+var skosRelationships = map[string]string{
+	"parent":   skosNS + "broader",
+	"child":    skosNS + "narrower",
+	"related":  skosNS + "related",
+	"see_also": skosNS + "related",
+	"synonym":  glossaryNS + "synonym",
+	"antonym":  glossaryNS + "antonym",
+}
+
+// skosImportRelationships is the reverse of skosRelationships; skos:related imports as related
+var skosImportRelationships = map[string]string{
+	skosNS + "broader":     "parent",
+	skosNS + "narrower":    "child",
+	skosNS + "related":     "related",
+	glossaryNS + "synonym": "synonym",
+	glossaryNS + "antonym": "antonym",
+}
+
+// SKOSService exports the glossary as a SKOS concept scheme and imports SKOS vocabularies.
+//
+// Terms are skos:Concepts with their name as prefLabel, code name as notation, base definition
+// as definition and examples as skos:example. Aliases are altLabels, alternative spellings
+// hiddenLabels. Contexts are scopeNotes: nodes with the context definition as rdf:value and
+// cluster, system, product, business rules and compliance flag in the glossary vocabulary.
+// Category, status, tags, compliance frameworks, visibility and allowed departments have glossary
+// vocabulary properties too.
+type SKOSService struct {
+	termRepo *repository.TermRepository
+	baseURI  string
+}
+
+func NewSKOSService() *SKOSService {
+	baseURI := strings.TrimRight(os.Getenv("SKOS_BASE_URI"), "/")
+	if baseURI == "" {
+		baseURI = skosDefaultBaseURI
+	}
+	return &SKOSService{
+		termRepo: repository.NewTermRepository(),
+		baseURI:  baseURI,
+	}
+}
+
+// ParseSKOSFormat returns the serialization of a format name or media type
+func ParseSKOSFormat(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(strings.SplitN(value, ";", 2)[0]))
+	switch value {
+	case "", "turtle", "ttl", "text/turtle", "application/x-turtle":
+		return SKOSFormatTurtle, true
+	case "jsonld", "json-ld", "application/ld+json", "application/json":
+		return SKOSFormatJSONLD, true
+	}
+	return "", false
+}
+
+func (s *SKOSService) conceptIRI(id uuid.UUID) string {
+	return s.baseURI + "/terms/" + id.String()
+}
+
+// Export serializes the terms visible to the department as a SKOS concept scheme. Relationships
+// to terms that are not visible are left out.
+func (s *SKOSService) Export(ctx context.Context, format string, userDepartment *string) ([]byte, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, userDepartment)
+	if err != nil {
+		return nil, err
+	}
+	return s.write(glossary, format)
+}
+
+// write serializes an export as a concept scheme in the format
+func (s *SKOSService) write(glossary *glossaryExport, format string) ([]byte, error) {
+	scheme := &rdfNode{iri: s.baseURI, types: []string{skosNS + "ConceptScheme"}}
+	scheme.add(dctNS+"title", rdfText("Business Glossary", skosLanguage))
+	nodes := []*rdfNode{scheme}
+
+	concepts := map[uuid.UUID]*rdfNode{}
+	for _, term := range glossary.terms {
+		concept := s.concept(term)
+		for _, alias := range glossary.aliases[term.ID] {
+			if alias.AliasType == "alternative_spelling" {
+				concept.add(skosNS+"hiddenLabel", rdfText(alias.Alias, skosLanguage))
+			} else {
+				concept.add(skosNS+"altLabel", rdfText(alias.Alias, skosLanguage))
+			}
+		}
+		for _, example := range glossary.examples[term.ID] {
+			concept.add(skosNS+"example", rdfText(example.ExampleText, skosLanguage))
+		}
+		concepts[term.ID] = concept
+		nodes = append(nodes, concept)
+	}
+
+	for _, rel := range glossary.relationships {
+		if predicate := skosRelationships[rel.RelationshipType]; predicate != "" {
+			concepts[rel.TermID].add(predicate, rdfIRI(s.conceptIRI(rel.RelatedTermID)))
+		}
+	}
+
+	parents := glossary.parents()
+	for _, term := range glossary.terms {
+		if _, ok := parents[term.ID]; !ok {
+			scheme.add(skosNS+"hasTopConcept", rdfIRI(s.conceptIRI(term.ID)))
+		}
+	}
+
+	if format == SKOSFormatJSONLD {
+		return writeJSONLD(nodes)
+	}
+	return writeTurtle(nodes), nil
+}
+
+func (s *SKOSService) concept(term models.Term) *rdfNode {
+	concept := &rdfNode{iri: s.conceptIRI(term.ID), types: []string{skosNS + "Concept"}}
+	concept.add(skosNS+"inScheme", rdfIRI(s.baseURI))
+	concept.add(skosNS+"prefLabel", rdfText(term.Term, skosLanguage))
+	if term.CodeName != nil && *term.CodeName != "" {
+		concept.add(skosNS+"notation", rdfString(*term.CodeName))
+	}
+	if term.BaseDefinition != "" {
+		concept.add(skosNS+"definition", rdfText(term.BaseDefinition, skosLanguage))
+	}
+
+	for _, tc := range term.Contexts {
+		note := &rdfNode{}
+		note.add(rdfNS+"value", rdfText(tc.ContextDefinition, skosLanguage))
+		scope := []struct {
+			predicate string
+			value     *string
+		}{{"cluster", tc.Cluster}, {"system", tc.System}, {"product", tc.Product}}
+		for _, part := range scope {
+			if part.value != nil && *part.value != "" {
+				note.add(glossaryNS+part.predicate, rdfString(*part.value))
+			}
+		}
+		for _, rule := range tc.BusinessRules {
+			note.add(glossaryNS+"businessRule", rdfText(rule, skosLanguage))
+		}
+		note.add(glossaryNS+"complianceRequired", rdfBool(tc.ComplianceRequired))
+		concept.add(skosNS+"scopeNote", rdfValue{node: note})
+	}
+
+	if term.Category != nil && *term.Category != "" {
+		concept.add(glossaryNS+"category", rdfString(*term.Category))
+	}
+	if term.Status != "" {
+		concept.add(glossaryNS+"status", rdfString(term.Status))
+	}
+	for _, tag := range term.Tags {
+		concept.add(glossaryNS+"tag", rdfString(tag))
+	}
+	for _, framework := range term.ComplianceFrameworks {
+		concept.add(glossaryNS+"complianceFramework", rdfString(framework))
+	}
+	if term.VisibilityType != nil && *term.VisibilityType != "" {
+		concept.add(glossaryNS+"visibilityType", rdfString(*term.VisibilityType))
+	}
+	for _, department := range term.AllowedDepartments {
+		concept.add(glossaryNS+"allowedDepartment", rdfString(department))
+	}
+	concept.add(dctNS+"created", rdfDateTime(term.CreatedAt))
+	concept.add(dctNS+"modified", rdfDateTime(term.UpdatedAt))
+
+	return concept
+}
+
+// ReadSKOS parses a Turtle or JSON-LD document and converts its skos:Concepts to import sheets:
+// concepts become terms, referred to by notation (code name) or else prefLabel. Relationships to
+// concepts outside the document are skipped. Row subjects are concept IRIs.
+func ReadSKOS(data []byte, format string) (*ImportSource, error) {
+	var triples []rdfTriple
+	var err error
+	if format == SKOSFormatJSONLD {
+		triples, err = parseJSONLD(data)
+	} else {
+		triples, err = parseTurtle(string(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	converter := newSKOSImportConverter(triples)
+	if len(converter.concepts) == 0 {
+		return nil, fmt.Errorf("no skos:Concept found")
+	}
+	return converter.convert(), nil
+}
+
+// skosImportConverter turns parsed triples into import sheets, remembering the concept of each row
+type skosImportConverter struct {
+	properties map[string][]rdfTriple
+	concepts   []string
+	references map[string]string // concept -> value of the term column
+	source     *ImportSource
+}
+
+func newSKOSImportConverter(triples []rdfTriple) *skosImportConverter {
+	c := &skosImportConverter{
+		properties: map[string][]rdfTriple{},
+		references: map[string]string{},
+		source:     newImportSource(),
+	}
+
+	isConcept := map[string]bool{}
+	for _, triple := range triples {
+		c.properties[triple.subject] = append(c.properties[triple.subject], triple)
+		if triple.predicate == rdfType && triple.object.value == skosNS+"Concept" && !isConcept[triple.subject] {
+			isConcept[triple.subject] = true
+			c.concepts = append(c.concepts, triple.subject)
+		}
+	}
+	return c
+}
+
+// literals returns the literal values of a property, English and untagged ones first
+func (c *skosImportConverter) literals(subject string, predicate string) []string {
+	preferred, other := []string{}, []string{}
+	for _, triple := range c.properties[subject] {
+		if triple.predicate != predicate || !triple.object.literal {
+			continue
+		}
+		language := triple.object.language
+		if language == "" || language == skosLanguage || strings.HasPrefix(language, skosLanguage+"-") {
+			preferred = append(preferred, triple.object.value)
+		} else {
+			other = append(other, triple.object.value)
+		}
+	}
+	return append(preferred, other...)
+}
+
+func (c *skosImportConverter) literal(subject string, predicate string) string {
+	if values := c.literals(subject, predicate); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+func (c *skosImportConverter) resources(subject string, predicate string) []string {
+	resources := []string{}
+	for _, triple := range c.properties[subject] {
+		if triple.predicate == predicate && !triple.object.literal {
+			resources = append(resources, triple.object.value)
+		}
+	}
+	return resources
+}
+
+func (c *skosImportConverter) convert() *ImportSource {
+	for _, concept := range c.concepts {
+		reference := c.literal(concept, skosNS+"notation")
+		if reference == "" {
+			reference = c.literal(concept, skosNS+"prefLabel")
+		}
+		c.references[concept] = reference
+	}
+
+	for _, concept := range c.concepts {
+		c.source.add("terms", concept, map[string]string{
+			"term":                  c.literal(concept, skosNS+"prefLabel"),
+			"code_name":             c.literal(concept, skosNS+"notation"),
+			"base_definition":       c.literal(concept, skosNS+"definition"),
+			"category":              c.literal(concept, glossaryNS+"category"),
+			"status":                c.literal(concept, glossaryNS+"status"),
+			"tags":                  strings.Join(c.literals(concept, glossaryNS+"tag"), importListSeparator),
+			"compliance_frameworks": strings.Join(c.literals(concept, glossaryNS+"complianceFramework"), importListSeparator),
+			"visibility_type":       c.literal(concept, glossaryNS+"visibilityType"),
+			"allowed_departments":   strings.Join(c.literals(concept, glossaryNS+"allowedDepartment"), importListSeparator),
+		})
+		if c.references[concept] == "" {
+			continue
+		}
+
+		c.convertScopeNotes(concept)
+
+		for _, example := range uniqueFold(c.literals(concept, skosNS+"example")) {
+			c.source.add("examples", concept, map[string]string{"term": c.references[concept], "example_text": example})
+		}
+
+		labels := map[string]bool{}
+		for _, property := range []string{skosNS + "altLabel", skosNS + "hiddenLabel"} {
+			for _, label := range c.literals(concept, property) {
+				label = strings.TrimSpace(label)
+				if label == "" || labels[strings.ToLower(label)] {
+					continue
+				}
+				labels[strings.ToLower(label)] = true
+				aliasType := "other"
+				if property == skosNS+"hiddenLabel" {
+					aliasType = "alternative_spelling"
+				} else if isAcronymPattern(label) {
+					aliasType = "acronym"
+				}
+				c.source.add("aliases", concept, map[string]string{"term": c.references[concept], "alias": label, "alias_type": aliasType})
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, triple := range c.properties[concept] {
+			relationshipType, ok := skosImportRelationships[triple.predicate]
+			related := c.references[triple.object.value]
+			if !ok || triple.object.literal || related == "" || seen[relationshipType+"\x00"+related] {
+				continue
+			}
+			seen[relationshipType+"\x00"+related] = true
+			c.source.add("relationships", concept, map[string]string{"term": c.references[concept], "related_term": related, "relationship_type": relationshipType})
+		}
+	}
+
+	return c.source
+}
+
+// convertScopeNotes imports structured scope notes as contexts. Plain scope notes carry no
+// cluster, system or product, so together they become the concept's general context.
+func (c *skosImportConverter) convertScopeNotes(concept string) {
+	general := c.literals(concept, skosNS+"scopeNote")
+	if len(general) > 0 {
+		c.source.add("contexts", concept, map[string]string{
+			"term":               c.references[concept],
+			"context_definition": strings.Join(general, "\n\n"),
+		})
+	}
+
+	for _, note := range c.resources(concept, skosNS+"scopeNote") {
+		compliance := ""
+		if values := c.literals(note, glossaryNS+"complianceRequired"); len(values) > 0 {
+			compliance = values[0]
+		}
+		c.source.add("contexts", concept, map[string]string{
+			"term":                c.references[concept],
+			"cluster":             c.literal(note, glossaryNS+"cluster"),
+			"system":              c.literal(note, glossaryNS+"system"),
+			"product":             c.literal(note, glossaryNS+"product"),
+			"context_definition":  c.literal(note, rdfNS+"value"),
+			"business_rules":      strings.Join(c.literals(note, glossaryNS+"businessRule"), importListSeparator),
+			"compliance_required": compliance,
+		})
+	}
+}
+
+// uniqueFold drops empty values and repeats that differ only in case
+func uniqueFold(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// testGlossaryExport returns an export with the values that are hard to serialize: quotes,
+// backslashes, line breaks, non-ASCII text and nested scope notes
+func testGlossaryExport() *glossaryExport {
+	margin := models.Term{
+		ID:                   uuid.MustParse("00000000-0000-0000-0000-00000000000a"),
+		Term:                 `Net "Interest" Margin`,
+		CodeName:             strPtr("NIM"),
+		BaseDefinition:       "Interest income minus expense,\nover assets.\tSee C:\\finance\\nim",
+		Category:             strPtr("Finance"),
+		Status:               "approved",
+		Tags:                 []string{"kpi", "profit"},
+		ComplianceFrameworks: []string{"IFRS 9"},
+		VisibilityType:       strPtr("department_restricted"),
+		AllowedDepartments:   []string{"Finance", "Risk"},
+		Contexts: []models.TermContext{{
+			Cluster:            strPtr("Retail"),
+			Product:            strPtr("Loans"),
+			ContextDefinition:  `Margin on """retail""" loans`,
+			BusinessRules:      []string{"Monthly", "Net of fees"},
+			ComplianceRequired: true,
+		}},
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC),
+	}
+	earnings := models.Term{
+		ID:             uuid.MustParse("00000000-0000-0000-0000-00000000000b"),
+		Term:           "Earnings à la carte 💶",
+		BaseDefinition: `Ends with a quote"`,
+		Status:         "draft",
+	}
+
+	export := &glossaryExport{
+		terms: []models.Term{margin, earnings},
+		byID:  map[uuid.UUID]*models.Term{},
+		aliases: map[uuid.UUID][]models.TermAlias{
+			margin.ID: {
+				{TermID: margin.ID, Alias: "NM", AliasType: "acronym"},
+				{TermID: margin.ID, Alias: "Net margine", AliasType: "alternative_spelling"},
+			},
+		},
+		examples: map[uuid.UUID][]models.TermExample{
+			margin.ID: {{TermID: margin.ID, ExampleText: "2.5% in Q1"}},
+		},
+		relationships: []models.TermRelationship{
+			{TermID: margin.ID, RelatedTermID: earnings.ID, RelationshipType: "parent"},
+			{TermID: earnings.ID, RelatedTermID: margin.ID, RelationshipType: "synonym"},
+		},
+	}
+	for i := range export.terms {
+		export.byID[export.terms[i].ID] = &export.terms[i]
+	}
+	return export
+}
+
+func TestSKOSRoundTrip(t *testing.T) {
+	want := map[string][][]string{
+		"terms": {
+			importColumns["terms"],
+			{`Net "Interest" Margin`, "NIM", "Finance", "Interest income minus expense,\nover assets.\tSee C:\\finance\\nim", "kpi|profit", "IFRS 9", "approved", "department_restricted", "Finance|Risk"},
+			{"Earnings à la carte 💶", "", "", `Ends with a quote"`, "", "", "draft", "", ""},
+		},
+		"contexts": {
+			importColumns["contexts"],
+			{"NIM", "Retail", "", "Loans", `Margin on """retail""" loans`, "Monthly|Net of fees", "true"},
+		},
+		"examples": {
+			importColumns["examples"],
+			{"NIM", "2.5% in Q1", ""},
+		},
+		"relationships": {
+			importColumns["relationships"],
+			{"NIM", "Earnings à la carte 💶", "parent"},
+			{"Earnings à la carte 💶", "NIM", "synonym"},
+		},
+		"aliases": {
+			importColumns["aliases"],
+			{"NIM", "NM", "acronym"},
+			{"NIM", "Net margine", "alternative_spelling"},
+		},
+	}
+
+	s := &SKOSService{baseURI: "https://example.com/glossary"}
+	for _, format := range []string{SKOSFormatTurtle, SKOSFormatJSONLD} {
+		t.Run(format, func(t *testing.T) {
+			data, err := s.write(testGlossaryExport(), format)
+			if err != nil {
+				t.Fatalf("write returned error: %v", err)
+			}
+			source, err := ReadSKOS(data, format)
+			if err != nil {
+				t.Fatalf("ReadSKOS returned error: %v\n%s", err, data)
+			}
+
+			for _, table := range source.Tables {
+				if !reflect.DeepEqual(table.Rows, want[table.Name]) {
+					t.Errorf("%s = %q\nwant %q", table.Name, table.Rows, want[table.Name])
+				}
+			}
+			if subject := source.subjects["aliases"][3]; subject != "https://example.com/glossary/terms/00000000-0000-0000-0000-00000000000a" {
+				t.Errorf("subject of aliases row 3 = %q, want the concept IRI", subject)
+			}
+
+			v := newImportValidator(nil)
+			v.plan(source.Tables)
+			if len(v.errors) != 0 {
+				t.Errorf("round trip does not import: %+v", v.errors)
+			}
+		})
+	}
+}
+
+func TestReadSKOSWithoutConcepts(t *testing.T) {
+	if _, err := ReadSKOS([]byte(`<https://example.com/a> <https://example.com/b> "c" .`), SKOSFormatTurtle); err == nil {
+		t.Error("ReadSKOS without concepts returned no error")
+	}
+}
+
+func TestParseSKOSFormat(t *testing.T) {
+	tests := []struct {
+		value  string
+		format string
+		ok     bool
+	}{
+		{"", SKOSFormatTurtle, true},
+		{"text/turtle; charset=utf-8", SKOSFormatTurtle, true},
+		{"TTL", SKOSFormatTurtle, true},
+		{"application/ld+json", SKOSFormatJSONLD, true},
+		{"json-ld", SKOSFormatJSONLD, true},
+		{"application/rdf+xml", "", false},
+	}
+	for _, tt := range tests {
+		if format, ok := ParseSKOSFormat(tt.value); format != tt.format || ok != tt.ok {
+			t.Errorf("ParseSKOSFormat(%q) = %q, %v, want %q, %v", tt.value, format, ok, tt.format, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Static site page formats
+const (
+	StaticSiteFormatHTML     = "html"
+	StaticSiteFormatMarkdown = "markdown"
+)
+
+// staticSiteOtherLetter groups the terms that do not start with a letter in the A–Z index
+const staticSiteOtherLetter = "#"
+
+var staticSiteSlugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// staticSiteRelationshipSections are the relationship sections of a term page in page order.
+// Outgoing relationships use the type's heading, incoming ones the heading of the inverse.
+var staticSiteRelationshipSections = []struct {
+	relationshipType string
+	inverse          string
+	heading          string
+}{
+	{"parent", "child", "Broader terms"},
+	{"child", "parent", "Narrower terms"},
+	{"synonym", "synonym", "Synonyms"},
+	{"antonym", "antonym", "Antonyms"},
+	{"related", "related", "Related terms"},
+	{"see_also", "", "See also"},
+}
+
+// StaticSiteOptions select what a static site build covers and how it is written
+type StaticSiteOptions struct {
+	Format         string  // StaticSiteFormatHTML or StaticSiteFormatMarkdown
+	Title          string  // site title, defaults to the catalog glossary display name
+	OrganizationID string  // organization whose branding colors style the HTML pages
+	Public         bool    // leave out department_restricted terms
+	Department     *string // build the glossary as this department sees it
+}
+
+// StaticSiteService renders the glossary to a directory of static pages for audiences without
+// access to the application: an A–Z index, a page per category and per cluster, and a page per
+// term with its contexts, examples, relationships and compliance frameworks.
+type StaticSiteService struct {
+	termRepo     *repository.TermRepository
+	gapRepo      *repository.GapRepository
+	brandingRepo *repository.BrandingRepository
+}
+
+func NewStaticSiteService() *StaticSiteService {
+	return &StaticSiteService{
+		termRepo:     repository.NewTermRepository(),
+		gapRepo:      repository.NewGapRepository(),
+		brandingRepo: repository.NewBrandingRepository(),
+	}
+}
+
+// ParseStaticSiteFormat returns the page format of a format name
+func ParseStaticSiteFormat(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "html":
+		return StaticSiteFormatHTML, true
+	case "markdown", "md":
+		return StaticSiteFormatMarkdown, true
+	}
+	return "", false
+}
+
+// Build renders the site and writes its files under dir, returning the number of files written.
+// Existing files with the same names are overwritten; other files in dir are left alone.
+func (s *StaticSiteService) Build(ctx context.Context, dir string, options StaticSiteOptions) (int, error) {
+	glossary, err := loadGlossaryExport(ctx, s.termRepo, options.Department)
+	if err != nil {
+		return 0, err
+	}
+	if options.Public {
+		glossary = glossary.withoutRestricted()
+	}
+
+	clusters, err := s.gapRepo.GetClusters(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	organizationID := options.OrganizationID
+	if organizationID == "" {
+		organizationID = "default"
+	}
+	branding, err := s.brandingRepo.GetBrandingConfig(ctx, organizationID)
+	if err != nil {
+		return 0, err
+	}
+
+	site := newStaticSite(glossary, clusters, options.Title)
+	var files map[string][]byte
+	switch options.Format {
+	case StaticSiteFormatMarkdown:
+		files = writeStaticSiteMarkdown(site)
+	default:
+		files, err = writeStaticSiteHTML(site, branding)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(files), writeStaticSiteFiles(dir, files)
+}
+
+func writeStaticSiteFiles(dir string, files map[string][]byte) error {
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// staticSite is the glossary arranged into the pages of the site
+type staticSite struct {
+	Title      string
+	Letters    []*staticSiteGroup
+	Categories []*staticSiteGroup
+	Clusters   []*staticSiteGroup
+	Terms      []*staticSiteTerm
+}
+
+// staticSiteGroup is a letter of the A–Z index, a category or a cluster with its terms
+type staticSiteGroup struct {
+	Name        string
+	Slug        string
+	Description string
+	Entries     []staticSiteEntry
+}
+
+// staticSiteEntry lists a term on a group page with a summary: the base definition, or on
+// cluster pages the definition in the cluster
+type staticSiteEntry struct {
+	Term    staticSiteLink
+	Summary string
+}
+
+type staticSiteLink struct {
+	Name string
+	Slug string
+}
+
+type staticSiteTerm struct {
+	Name                 string
+	Slug                 string
+	CodeName             string
+	Category             *staticSiteLink
+	Status               string
+	Definition           string
+	Restricted           []string // departments a restricted term is visible to
+	Tags                 []string
+	ComplianceFrameworks []string
+	Aliases              []string
+	Contexts             []staticSiteContext
+	Examples             []string
+	Relationships        []staticSiteRelationships
+}
+
+type staticSiteContext struct {
+	Cluster            *staticSiteLink
+	System             string
+	Product            string
+	Definition         string
+	BusinessRules      []string
+	ComplianceRequired bool
+}
+
+type staticSiteRelationships struct {
+	Heading string
+	Terms   []staticSiteLink
+}
+
+// newStaticSite arranges the glossary into pages. Terms, categories and clusters get slugs made
+// from their names, with a numeric suffix where names clash. Clusters without terms are listed
+// with their description; clusters only named by contexts are added.
+func newStaticSite(glossary *glossaryExport, clusters []models.Cluster, title string) *staticSite {
+	if title == "" {
+		title = catalogGlossaryDisplayName
+	}
+	site := &staticSite{Title: title}
+
+	terms := append([]models.Term(nil), glossary.terms...)
+	sort.SliceStable(terms, func(i, j int) bool {
+		return nameLess(terms[i].Term, terms[j].Term)
+	})
+
+	termSlugs := newStaticSiteSlugs()
+	links := map[uuid.UUID]staticSiteLink{}
+	for _, term := range terms {
+		links[term.ID] = staticSiteLink{Name: term.Term, Slug: termSlugs.slug(term.Term)}
+	}
+
+	categorySlugs := newStaticSiteSlugs("index")
+	categories := map[string]*staticSiteGroup{}
+	clusterSlugs := newStaticSiteSlugs("index")
+	clusterGroups := map[string]*staticSiteGroup{}
+	for _, cluster := range clusters {
+		clusterGroups[cluster.Name] = &staticSiteGroup{Name: cluster.Name, Slug: clusterSlugs.slug(cluster.Name), Description: valueOrEmpty(cluster.Description)}
+	}
+
+	related := staticSiteRelated(glossary.relationships)
+	letters := map[string]*staticSiteGroup{}
+	for _, term := range terms {
+		link := links[term.ID]
+		page := &staticSiteTerm{
+			Name:                 term.Term,
+			Slug:                 link.Slug,
+			CodeName:             valueOrEmpty(term.CodeName),
+			Status:               term.Status,
+			Definition:           term.BaseDefinition,
+			Tags:                 term.Tags,
+			ComplianceFrameworks: term.ComplianceFrameworks,
+			Aliases:              glossary.aliasNames(term),
+			Examples:             glossary.exampleTexts(term),
+		}
+		if term.VisibilityType != nil && *term.VisibilityType == "department_restricted" {
+			page.Restricted = term.AllowedDepartments
+		}
+
+		letter := staticSiteLetter(term.Term)
+		if letters[letter] == nil {
+			letters[letter] = &staticSiteGroup{Name: letter, Slug: staticSiteLetterSlug(letter)}
+			site.Letters = append(site.Letters, letters[letter])
+		}
+		letters[letter].Entries = append(letters[letter].Entries, staticSiteEntry{Term: link, Summary: term.BaseDefinition})
+
+		if category := strings.TrimSpace(valueOrEmpty(term.Category)); category != "" {
+			if categories[category] == nil {
+				categories[category] = &staticSiteGroup{Name: category, Slug: categorySlugs.slug(category)}
+				site.Categories = append(site.Categories, categories[category])
+			}
+			categories[category].Entries = append(categories[category].Entries, staticSiteEntry{Term: link, Summary: term.BaseDefinition})
+			page.Category = &staticSiteLink{Name: category, Slug: categories[category].Slug}
+		}
+
+		inCluster := map[string]bool{}
+		for _, tc := range term.Contexts {
+			pageContext := staticSiteContext{
+				System:             valueOrEmpty(tc.System),
+				Product:            valueOrEmpty(tc.Product),
+				Definition:         tc.ContextDefinition,
+				BusinessRules:      tc.BusinessRules,
+				ComplianceRequired: tc.ComplianceRequired,
+			}
+			if cluster := strings.TrimSpace(valueOrEmpty(tc.Cluster)); cluster != "" {
+				if clusterGroups[cluster] == nil {
+					clusterGroups[cluster] = &staticSiteGroup{Name: cluster, Slug: clusterSlugs.slug(cluster)}
+				}
+				pageContext.Cluster = &staticSiteLink{Name: cluster, Slug: clusterGroups[cluster].Slug}
+				// a term is listed once per cluster, with its first definition there
+				if !inCluster[cluster] {
+					inCluster[cluster] = true
+					clusterGroups[cluster].Entries = append(clusterGroups[cluster].Entries, staticSiteEntry{Term: link, Summary: tc.ContextDefinition})
+				}
+			}
+			page.Contexts = append(page.Contexts, pageContext)
+		}
+
+		for _, section := range staticSiteRelationshipSections {
+			ids := related[term.ID][section.relationshipType]
+			if len(ids) == 0 {
+				continue
+			}
+			group := staticSiteRelationships{Heading: section.heading}
+			for _, id := range ids {
+				group.Terms = append(group.Terms, links[id])
+			}
+			sort.SliceStable(group.Terms, func(i, j int) bool {
+				return nameLess(group.Terms[i].Name, group.Terms[j].Name)
+			})
+			page.Relationships = append(page.Relationships, group)
+		}
+
+		site.Terms = append(site.Terms, page)
+	}
+
+	sort.SliceStable(site.Letters, func(i, j int) bool {
+		// terms that do not start with a letter come last
+		if (site.Letters[i].Name == staticSiteOtherLetter) != (site.Letters[j].Name == staticSiteOtherLetter) {
+			return site.Letters[j].Name == staticSiteOtherLetter
+		}
+		return site.Letters[i].Name < site.Letters[j].Name
+	})
+	sort.SliceStable(site.Categories, func(i, j int) bool {
+		return nameLess(site.Categories[i].Name, site.Categories[j].Name)
+	})
+	for _, group := range clusterGroups {
+		site.Clusters = append(site.Clusters, group)
+	}
+	sort.SliceStable(site.Clusters, func(i, j int) bool {
+		return nameLess(site.Clusters[i].Name, site.Clusters[j].Name)
+	})
+
+	return site
+}
+
+// staticSiteRelated returns the related terms of each term by relationship type, with incoming
+// relationships added under the inverse type so that both terms link to each other
+func staticSiteRelated(relationships []models.TermRelationship) map[uuid.UUID]map[string][]uuid.UUID {
+	related := map[uuid.UUID]map[string][]uuid.UUID{}
+	seen := map[string]bool{}
+	add := func(termID uuid.UUID, relationshipType string, relatedID uuid.UUID) {
+		key := termID.String() + relationshipType + relatedID.String()
+		if relationshipType == "" || termID == relatedID || seen[key] {
+			return
+		}
+		seen[key] = true
+		if related[termID] == nil {
+			related[termID] = map[string][]uuid.UUID{}
+		}
+		related[termID][relationshipType] = append(related[termID][relationshipType], relatedID)
+	}
+	for _, rel := range relationships {
+		for _, section := range staticSiteRelationshipSections {
+			if section.relationshipType == rel.RelationshipType {
+				add(rel.TermID, section.relationshipType, rel.RelatedTermID)
+				add(rel.RelatedTermID, section.inverse, rel.TermID)
+			}
+		}
+	}
+	return related
+}
+
+// staticSiteSlugs hands out unique slugs
+type staticSiteSlugs map[string]bool
+
+// newStaticSiteSlugs returns slugs that avoid the reserved ones, e.g. the section's index page
+func newStaticSiteSlugs(reserved ...string) staticSiteSlugs {
+	s := staticSiteSlugs{}
+	for _, slug := range reserved {
+		s[slug] = true
+	}
+	return s
+}
+
+func (s staticSiteSlugs) slug(name string) string {
+	slug := strings.Trim(staticSiteSlugInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "page"
+	}
+	unique := slug
+	for i := 2; s[unique]; i++ {
+		unique = slug + "-" + strconv.Itoa(i)
+	}
+	s[unique] = true
+	return unique
+}
+
+// staticSiteLetter is the A–Z index letter of a term name
+func staticSiteLetter(name string) string {
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		break
+	}
+	return staticSiteOtherLetter
+}
+
+// staticSiteLetterSlug is the anchor of an index letter
+func staticSiteLetterSlug(letter string) string {
+	if letter == staticSiteOtherLetter {
+		return "other"
+	}
+	return "letter-" + strings.ToLower(letter)
+}
+
+// nameLess orders names case-insensitively, breaking ties by case
+func nameLess(a, b string) bool {
+	if fa, fb := strings.ToLower(a), strings.ToLower(b); fa != fb {
+		return fa < fb
+	}
+	return a < b
+}
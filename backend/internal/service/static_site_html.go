@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"clarityconnect/internal/models"
+)
+
+// staticSiteColor accepts the hex colors the branding settings hold; anything else falls back to
+// the default palette so branding values cannot inject CSS
+var staticSiteColor = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// staticSiteDefaultPalette is the default branding palette: light, pastel, primary, dark
+var staticSiteDefaultPalette = [4]string{"#EAF9E7", "#C0E6BA", "#4CA771", "#013237"}
+
+const staticSiteCSS = `:root {
+  --color-light: %s;
+  --color-pastel: %s;
+  --color-primary: %s;
+  --color-dark: %s;
+}
+body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; color: var(--color-dark); background: var(--color-light); line-height: 1.5; }
+header { background: var(--color-dark); color: var(--color-light); padding: 1rem 2rem; }
+header a { color: var(--color-light); text-decoration: none; margin-right: 1.5rem; }
+header .title { font-size: 1.25rem; font-weight: 600; }
+main { max-width: 60rem; margin: 0 auto; padding: 1.5rem 2rem 3rem; }
+a { color: var(--color-primary); }
+h1, h2, h3 { color: var(--color-dark); }
+nav.letters a { display: inline-block; min-width: 1.5rem; font-weight: 600; }
+dl.entries dt { margin-top: 0.75rem; font-weight: 600; }
+dl.entries dd { margin-left: 1rem; }
+.badge { display: inline-block; background: var(--color-pastel); border-radius: 0.75rem; padding: 0 0.6rem; margin: 0 0.25rem 0.25rem 0; font-size: 0.85rem; }
+.badge.primary { background: var(--color-primary); color: var(--color-light); }
+.context { background: #fff; border-left: 4px solid var(--color-primary); padding: 0.75rem 1rem; margin: 0.75rem 0; }
+.meta { color: var(--color-primary); font-size: 0.9rem; }
+footer { text-align: center; font-size: 0.8rem; padding: 1rem; color: var(--color-primary); }
+`
+
+const staticSiteLayout = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.Site.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header>
+<a class="title" href="{{.Root}}index.html">{{.Site.Title}}</a>
+<a href="{{.Root}}index.html">A–Z</a>
+<a href="{{.Root}}categories/index.html">Categories</a>
+<a href="{{.Root}}clusters/index.html">Clusters</a>
+</header>
+<main>
+{{template "content" .}}
+</main>
+<footer>{{len .Site.Terms}} terms</footer>
+</body>
+</html>
+{{end}}
+{{define "entries"}}<dl class="entries">
+{{range .}}<dt><a href="{{.Root}}terms/{{.Term.Slug}}.html">{{.Term.Name}}</a></dt>
+{{with .Summary}}<dd>{{.}}</dd>{{end}}
+{{end}}</dl>{{end}}`
+
+// staticSitePages are the page bodies; each page gets Root (the path to the site root), Title,
+// Site and the page's Data
+var staticSitePages = map[string]string{
+	"index": `<h1>{{.Site.Title}}</h1>
+<nav class="letters">{{range .Site.Letters}}<a href="#{{.Slug}}">{{.Name}}</a> {{end}}</nav>
+{{range .Site.Letters}}<h2 id="{{.Slug}}">{{.Name}}</h2>
+{{template "entries" (entries $.Root .Entries)}}
+{{else}}<p>The glossary has no terms.</p>
+{{end}}`,
+
+	"groups": `<h1>{{.Title}}</h1>
+<dl class="entries">
+{{range .Data}}<dt><a href="{{.Slug}}.html">{{.Name}}</a> <span class="meta">{{len .Entries}} terms</span></dt>
+{{with .Description}}<dd>{{.}}</dd>{{end}}
+{{else}}<dt>None</dt>
+{{end}}</dl>`,
+
+	"group": `<h1>{{.Data.Name}}</h1>
+{{with .Data.Description}}<p>{{.}}</p>{{end}}
+{{if .Data.Entries}}{{template "entries" (entries .Root .Data.Entries)}}{{else}}<p>No terms.</p>{{end}}`,
+
+	"term": `{{with .Data}}<h1>{{.Name}}</h1>
+<p class="meta">{{with .CodeName}}Code name <code>{{.}}</code> · {{end}}{{with .Category}}<a href="../categories/{{.Slug}}.html">{{.Name}}</a> · {{end}}{{.Status}}</p>
+{{with .Restricted}}<p class="meta">Visible to: {{join . ", "}}</p>{{end}}
+<p>{{.Definition}}</p>
+{{with .Aliases}}<p>Also known as: {{range .}}<span class="badge">{{.}}</span>{{end}}</p>{{end}}
+{{with .ComplianceFrameworks}}<h2>Compliance frameworks</h2>
+<p>{{range .}}<span class="badge primary">{{.}}</span>{{end}}</p>{{end}}
+{{with .Contexts}}<h2>Contexts</h2>
+{{range .}}<div class="context">
+<p class="meta">{{with .Cluster}}<a href="../clusters/{{.Slug}}.html">{{.Name}}</a>{{else}}All clusters{{end}}{{with .System}} · {{.}}{{end}}{{with .Product}} · {{.}}{{end}}{{if .ComplianceRequired}} · compliance required{{end}}</p>
+<p>{{.Definition}}</p>
+{{with .BusinessRules}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>
+{{end}}{{end}}
+{{with .Examples}}<h2>Examples</h2>
+<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{range .Relationships}}<h2>{{.Heading}}</h2>
+<p>{{range .Terms}}<a class="badge" href="{{.Slug}}.html">{{.Name}}</a>{{end}}</p>
+{{end}}
+{{with .Tags}}<p class="meta">Tags: {{range .}}<span class="badge">{{.}}</span>{{end}}</p>{{end}}{{end}}`,
+}
+
+// staticSiteEntryView is an entry with the path to the site root, for the entries template
+type staticSiteEntryView struct {
+	staticSiteEntry
+	Root string
+}
+
+// writeStaticSiteHTML renders the site as HTML pages styled with the branding palette
+func writeStaticSiteHTML(site *staticSite, branding *models.BrandingConfig) (map[string][]byte, error) {
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		"entries": func(root string, entries []staticSiteEntry) []staticSiteEntryView {
+			views := make([]staticSiteEntryView, len(entries))
+			for i, entry := range entries {
+				views[i] = staticSiteEntryView{Root: root, staticSiteEntry: entry}
+			}
+			return views
+		},
+	}
+	templates := map[string]*template.Template{}
+	for name, body := range staticSitePages {
+		tmpl, err := template.New(name).Funcs(funcs).Parse(staticSiteLayout)
+		if err == nil {
+			_, err = tmpl.Parse(`{{define "content"}}` + body + `{{end}}`)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s page template: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+
+	files := map[string][]byte{}
+	render := func(path, page, root, title string, data interface{}) error {
+		var out bytes.Buffer
+		err := templates[page].ExecuteTemplate(&out, "layout", map[string]interface{}{
+			"Root":  root,
+			"Title": title,
+			"Site":  site,
+			"Data":  data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		files[path] = out.Bytes()
+		return nil
+	}
+
+	if err := render("index.html", "index", "", "A–Z", nil); err != nil {
+		return nil, err
+	}
+	for _, section := range []struct {
+		dir    string
+		title  string
+		groups []*staticSiteGroup
+	}{
+		{"categories", "Categories", site.Categories},
+		{"clusters", "Clusters", site.Clusters},
+	} {
+		if err := render(section.dir+"/index.html", "groups", "../", section.title, section.groups); err != nil {
+			return nil, err
+		}
+		for _, group := range section.groups {
+			if err := render(section.dir+"/"+group.Slug+".html", "group", "../", group.Name, group); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, term := range site.Terms {
+		if err := render("terms/"+term.Slug+".html", "term", "../", term.Name, term); err != nil {
+			return nil, err
+		}
+	}
+
+	palette := staticSiteDefaultPalette
+	for i, color := range []string{branding.LightColor, branding.PastelColor, branding.PrimaryColor, branding.DarkColor} {
+		if staticSiteColor.MatchString(color) {
+			palette[i] = color
+		}
+	}
+	files["style.css"] = []byte(fmt.Sprintf(staticSiteCSS, palette[0], palette[1], palette[2], palette[3]))
+
+	return files, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// staticSiteMarkdownEscaper escapes the characters that start Markdown or inline HTML markup
+var staticSiteMarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", "&lt;", ">", "&gt;", "#", `\#`, "|", `\|`,
+)
+
+// staticSiteMarkdownText escapes text for Markdown, on a single line
+func staticSiteMarkdownText(text string) string {
+	return staticSiteMarkdownEscaper.Replace(strings.Join(strings.Fields(text), " "))
+}
+
+// writeStaticSiteMarkdown renders the site as Markdown pages with the layout of the HTML pages,
+// for documentation tools that bring their own styling
+func writeStaticSiteMarkdown(site *staticSite) map[string][]byte {
+	files := map[string][]byte{}
+	nav := func(root string) string {
+		return fmt.Sprintf("[A–Z](%sindex.md) · [Categories](%scategories/index.md) · [Clusters](%sclusters/index.md)\n\n", root, root, root)
+	}
+	entries := func(out *bytes.Buffer, root string, list []staticSiteEntry) {
+		for _, entry := range list {
+			fmt.Fprintf(out, "- [%s](%sterms/%s.md)", staticSiteMarkdownText(entry.Term.Name), root, entry.Term.Slug)
+			if entry.Summary != "" {
+				fmt.Fprintf(out, ": %s", staticSiteMarkdownText(entry.Summary))
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	var index bytes.Buffer
+	index.WriteString(nav(""))
+	fmt.Fprintf(&index, "# %s\n\n", staticSiteMarkdownText(site.Title))
+	letters := []string{}
+	for _, letter := range site.Letters {
+		letters = append(letters, fmt.Sprintf("[%s](#%s)", staticSiteMarkdownText(letter.Name), letter.Slug))
+	}
+	if len(letters) == 0 {
+		index.WriteString("The glossary has no terms.\n")
+	} else {
+		fmt.Fprintf(&index, "%s\n", strings.Join(letters, " "))
+	}
+	for _, letter := range site.Letters {
+		fmt.Fprintf(&index, "\n<a id=\"%s\"></a>\n\n## %s\n\n", letter.Slug, staticSiteMarkdownText(letter.Name))
+		entries(&index, "", letter.Entries)
+	}
+	files["index.md"] = index.Bytes()
+
+	for _, section := range []struct {
+		dir    string
+		title  string
+		groups []*staticSiteGroup
+	}{
+		{"categories", "Categories", site.Categories},
+		{"clusters", "Clusters", site.Clusters},
+	} {
+		var list bytes.Buffer
+		list.WriteString(nav("../"))
+		fmt.Fprintf(&list, "# %s\n\n", section.title)
+		if len(section.groups) == 0 {
+			list.WriteString("None.\n")
+		}
+		for _, group := range section.groups {
+			fmt.Fprintf(&list, "- [%s](%s.md) (%d terms)", staticSiteMarkdownText(group.Name), group.Slug, len(group.Entries))
+			if group.Description != "" {
+				fmt.Fprintf(&list, ": %s", staticSiteMarkdownText(group.Description))
+			}
+			list.WriteString("\n")
+
+			var page bytes.Buffer
+			page.WriteString(nav("../"))
+			fmt.Fprintf(&page, "# %s\n\n", staticSiteMarkdownText(group.Name))
+			if group.Description != "" {
+				fmt.Fprintf(&page, "%s\n\n", staticSiteMarkdownText(group.Description))
+			}
+			if len(group.Entries) == 0 {
+				page.WriteString("No terms.\n")
+			}
+			entries(&page, "../", group.Entries)
+			files[section.dir+"/"+group.Slug+".md"] = page.Bytes()
+		}
+		files[section.dir+"/index.md"] = list.Bytes()
+	}
+
+	for _, term := range site.Terms {
+		files["terms/"+term.Slug+".md"] = writeStaticSiteMarkdownTerm(term, nav("../"))
+	}
+
+	return files
+}
+
+func writeStaticSiteMarkdownTerm(term *staticSiteTerm, nav string) []byte {
+	var out bytes.Buffer
+	out.WriteString(nav)
+	fmt.Fprintf(&out, "# %s\n\n", staticSiteMarkdownText(term.Name))
+
+	meta := []string{}
+	if term.CodeName != "" {
+		meta = append(meta, "Code name `"+strings.ReplaceAll(term.CodeName, "`", "'")+"`")
+	}
+	if term.Category != nil {
+		meta = append(meta, fmt.Sprintf("[%s](../categories/%s.md)", staticSiteMarkdownText(term.Category.Name), term.Category.Slug))
+	}
+	if term.Status != "" {
+		meta = append(meta, term.Status)
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&out, "*%s*\n\n", strings.Join(meta, " · "))
+	}
+	if len(term.Restricted) > 0 {
+		fmt.Fprintf(&out, "*Visible to: %s*\n\n", staticSiteMarkdownText(strings.Join(term.Restricted, ", ")))
+	}
+	fmt.Fprintf(&out, "%s\n\n", staticSiteMarkdownText(term.Definition))
+	if len(term.Aliases) > 0 {
+		fmt.Fprintf(&out, "Also known as: %s\n\n", staticSiteMarkdownList(term.Aliases))
+	}
+	if len(term.ComplianceFrameworks) > 0 {
+		fmt.Fprintf(&out, "## Compliance frameworks\n\n%s\n\n", staticSiteMarkdownList(term.ComplianceFrameworks))
+	}
+
+	if len(term.Contexts) > 0 {
+		out.WriteString("## Contexts\n\n")
+		for _, tc := range term.Contexts {
+			scope := []string{"All clusters"}
+			if tc.Cluster != nil {
+				scope[0] = fmt.Sprintf("[%s](../clusters/%s.md)", staticSiteMarkdownText(tc.Cluster.Name), tc.Cluster.Slug)
+			}
+			for _, value := range []string{tc.System, tc.Product} {
+				if value != "" {
+					scope = append(scope, staticSiteMarkdownText(value))
+				}
+			}
+			if tc.ComplianceRequired {
+				scope = append(scope, "compliance required")
+			}
+			fmt.Fprintf(&out, "### %s\n\n%s\n\n", strings.Join(scope, " · "), staticSiteMarkdownText(tc.Definition))
+			for _, rule := range tc.BusinessRules {
+				fmt.Fprintf(&out, "- %s\n", staticSiteMarkdownText(rule))
+			}
+			if len(tc.BusinessRules) > 0 {
+				out.WriteString("\n")
+			}
+		}
+	}
+
+	if len(term.Examples) > 0 {
+		out.WriteString("## Examples\n\n")
+		for _, example := range term.Examples {
+			fmt.Fprintf(&out, "- %s\n", staticSiteMarkdownText(example))
+		}
+		out.WriteString("\n")
+	}
+
+	for _, group := range term.Relationships {
+		links := []string{}
+		for _, link := range group.Terms {
+			links = append(links, fmt.Sprintf("[%s](%s.md)", staticSiteMarkdownText(link.Name), link.Slug))
+		}
+		fmt.Fprintf(&out, "## %s\n\n%s\n\n", group.Heading, strings.Join(links, ", "))
+	}
+
+	if len(term.Tags) > 0 {
+		fmt.Fprintf(&out, "Tags: %s\n", staticSiteMarkdownList(term.Tags))
+	}
+
+	return append(bytes.TrimRight(out.Bytes(), "\n"), '\n')
+}
+
+func staticSiteMarkdownList(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = staticSiteMarkdownText(value)
+	}
+	return strings.Join(escaped, ", ")
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestStaticSitePublicLeavesOutRestrictedTerms(t *testing.T) {
+	margin := models.Term{
+		ID:             uuid.New(),
+		Term:           "Margin",
+		BaseDefinition: "Income minus expense",
+		Category:       strPtr("Finance"),
+		Status:         "approved",
+		VisibilityType: strPtr("public"),
+		Contexts:       []models.TermContext{{Cluster: strPtr("Retail"), ContextDefinition: "Retail margin"}},
+	}
+	salary := models.Term{
+		ID:                 uuid.New(),
+		Term:               "Salary Band",
+		BaseDefinition:     "Pay range of a grade",
+		Category:           strPtr("Compensation"),
+		Status:             "approved",
+		VisibilityType:     strPtr("department_restricted"),
+		AllowedDepartments: []string{"HR"},
+		Contexts: []models.TermContext{
+			{Cluster: strPtr("Retail"), ContextDefinition: "Retail pay range"},
+			{Cluster: strPtr("Payroll"), ContextDefinition: "Payroll pay range"},
+		},
+	}
+	glossary := &glossaryExport{
+		terms:    []models.Term{margin, salary},
+		byID:     map[uuid.UUID]*models.Term{},
+		aliases:  map[uuid.UUID][]models.TermAlias{salary.ID: {{TermID: salary.ID, Alias: "Pay grade"}}},
+		examples: map[uuid.UUID][]models.TermExample{},
+		relationships: []models.TermRelationship{
+			{TermID: margin.ID, RelatedTermID: salary.ID, RelationshipType: "related"},
+			{TermID: salary.ID, RelatedTermID: margin.ID, RelationshipType: "child"},
+		},
+	}
+	for i := range glossary.terms {
+		glossary.byID[glossary.terms[i].ID] = &glossary.terms[i]
+	}
+
+	site := newStaticSite(glossary.withoutRestricted(), nil, "")
+
+	if len(site.Terms) != 1 || site.Terms[0].Name != "Margin" {
+		t.Fatalf("term pages = %+v, want only Margin", site.Terms)
+	}
+	if len(site.Terms[0].Relationships) != 0 {
+		t.Errorf("Margin links to %+v, want no relationships", site.Terms[0].Relationships)
+	}
+	for kind, groups := range map[string][]*staticSiteGroup{"letter": site.Letters, "category": site.Categories, "cluster": site.Clusters} {
+		for _, group := range groups {
+			if group.Name == "S" || group.Name == "Compensation" || group.Name == "Payroll" {
+				t.Errorf("%s %q is listed, though only the restricted term is in it", kind, group.Name)
+			}
+			for _, entry := range group.Entries {
+				if entry.Term.Name != "Margin" {
+					t.Errorf("%s %q lists %q", kind, group.Name, entry.Term.Name)
+				}
+			}
+		}
+	}
+
+	html, err := writeStaticSiteHTML(site, &models.BrandingConfig{})
+	if err != nil {
+		t.Fatalf("writeStaticSiteHTML returned error: %v", err)
+	}
+	for format, files := range map[string]map[string][]byte{"HTML": html, "Markdown": writeStaticSiteMarkdown(site)} {
+		for name, data := range files {
+			for _, restricted := range []string{"Salary", "salary", "Pay grade", "Compensation", "Payroll"} {
+				if strings.Contains(name, restricted) || strings.Contains(string(data), restricted) {
+					t.Errorf("%s file %s mentions %q", format, name, restricted)
+				}
+			}
+		}
+	}
+
+	if full := newStaticSite(glossary, nil, ""); len(full.Terms) != 2 {
+		t.Errorf("non-public site has %d term pages, want 2", len(full.Terms))
+	}
+}
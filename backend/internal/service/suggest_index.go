@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSuggestRefreshInterval = 5 * time.Minute
+
+	// suggestPopularityWindow is how far back usage counts towards suggestion ranking
+	suggestPopularityWindow = 90 * 24 * time.Hour
+
+	// suggestBuildTimeout bounds a single index rebuild
+	suggestBuildTimeout = 30 * time.Second
+)
+
+// suggestKindRank orders matches of equal popularity: names before code names, acronyms and synonyms
+var suggestKindRank = map[string]int{
+	"term":      0,
+	"code_name": 1,
+	"acronym":   2,
+	"alias":     2,
+	"synonym":   3,
+}
+
+// suggestTerm is the term an index entry points to
+type suggestTerm struct {
+	id          uuid.UUID
+	name        string
+	popularity  int64
+	restricted  bool
+	departments []string
+}
+
+// termVisibility returns the identity and visibility of a term
+func termVisibility(term models.Term) *suggestTerm {
+	return &suggestTerm{
+		id:          term.ID,
+		name:        term.Term,
+		restricted:  term.VisibilityType != nil && *term.VisibilityType == "department_restricted",
+		departments: term.AllowedDepartments,
+	}
+}
+
+// visibleTo mirrors termVisibilityClause: without a department all terms are visible
+func (t *suggestTerm) visibleTo(department *string) bool {
+	if !t.restricted || department == nil {
+		return true
+	}
+	for _, allowed := range t.departments {
+		if allowed == *department {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestEntry is one searchable key. Every word start of a text gets its own entry, so
+// "cov" finds "Liquidity Coverage Ratio".
+type suggestEntry struct {
+	key    string // lowercased text from a word start
+	prefix bool   // key starts at the beginning of the text
+	text   string
+	kind   string
+	term   *suggestTerm
+}
+
+// SuggestIndex is an in-memory prefix index of term names, code names, acronyms, aliases and synonyms
+// for typeahead. It is rebuilt periodically and shortly after Invalidate is called.
+//
+// Configuration (environment):
+//   - SUGGEST_INDEX_REFRESH_SECONDS: interval of the periodic rebuild (default 300)
+type SuggestIndex struct {
+	termRepo  *repository.TermRepository
+	usageRepo *repository.UsageRepository
+
+	mu      sync.RWMutex
+	entries []suggestEntry // sorted by key; replaced, never modified, on rebuild
+
+	indexRefresher
+}
+
+func NewSuggestIndex() *SuggestIndex {
+	return &SuggestIndex{
+		termRepo:       repository.NewTermRepository(),
+		usageRepo:      repository.NewUsageRepository(),
+		indexRefresher: newIndexRefresher(time.Duration(envInt("SUGGEST_INDEX_REFRESH_SECONDS", int(defaultSuggestRefreshInterval/time.Second))) * time.Second),
+	}
+}
+
+// Start builds the index in the background and keeps it fresh until ctx is cancelled
+func (s *SuggestIndex) Start(ctx context.Context) {
+	s.start(ctx, s.rebuild)
+}
+
+// Suggest returns up to limit terms matching prefix, one per term, visible to the department
+func (s *SuggestIndex) Suggest(prefix string, userDepartment *string, limit int) []models.Suggestion {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	suggestions := []models.Suggestion{}
+	if prefix == "" || limit <= 0 {
+		return suggestions
+	}
+
+	s.mu.RLock()
+	entries := s.entries
+	s.mu.RUnlock()
+
+	// Keep the best entry per term
+	best := map[uuid.UUID]*suggestEntry{}
+	start := sort.Search(len(entries), func(i int) bool { return entries[i].key >= prefix })
+	for i := start; i < len(entries) && strings.HasPrefix(entries[i].key, prefix); i++ {
+		entry := &entries[i]
+		if !entry.term.visibleTo(userDepartment) {
+			continue
+		}
+		if current, ok := best[entry.term.id]; !ok || suggestEntryLess(entry, current) {
+			best[entry.term.id] = entry
+		}
+	}
+
+	matches := make([]*suggestEntry, 0, len(best))
+	for _, entry := range best {
+		matches = append(matches, entry)
+	}
+	sort.Slice(matches, func(i, j int) bool { return suggestEntryLess(matches[i], matches[j]) })
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	for _, entry := range matches {
+		suggestions = append(suggestions, models.Suggestion{
+			TermID:     entry.term.id,
+			Term:       entry.term.name,
+			Text:       entry.text,
+			Kind:       entry.kind,
+			Popularity: entry.term.popularity,
+		})
+	}
+
+	return suggestions
+}
+
+// suggestEntryLess ranks whole-text prefix matches first, then popularity, kind and length
+func suggestEntryLess(a, b *suggestEntry) bool {
+	if a.prefix != b.prefix {
+		return a.prefix
+	}
+	if a.term.popularity != b.term.popularity {
+		return a.term.popularity > b.term.popularity
+	}
+	if suggestKindRank[a.kind] != suggestKindRank[b.kind] {
+		return suggestKindRank[a.kind] < suggestKindRank[b.kind]
+	}
+	if len(a.text) != len(b.text) {
+		return len(a.text) < len(b.text)
+	}
+	return a.text < b.text
+}
+
+// rebuild loads terms, synonyms and popularity and swaps in a new index. On failure the
+// previous index is kept.
+func (s *SuggestIndex) rebuild(ctx context.Context) {
+	start := time.Now()
+	entries, err := s.build(ctx)
+	if err != nil {
+		log.Printf("Suggest index rebuild failed: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.entries = entries
+	s.mu.Unlock()
+
+	log.Printf("Suggest index rebuilt: %d entries in %dms", len(entries), time.Since(start).Milliseconds())
+}
+
+func (s *SuggestIndex) build(ctx context.Context) ([]suggestEntry, error) {
+	terms, err := s.termRepo.ListTermNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms, err := s.termRepo.ListRelationshipsByType(ctx, "synonym")
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := s.termRepo.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	popularity, err := s.usageRepo.GetTermPopularity(ctx, time.Now().Add(-suggestPopularityWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*suggestTerm, len(terms))
+	entries := []suggestEntry{}
+	for _, term := range terms {
+		st := termVisibility(term)
+		st.popularity = popularity[term.ID]
+		byID[term.ID] = st
+
+		entries = appendSuggestEntries(entries, st, term.Term, "term")
+		if term.CodeName != nil && *term.CodeName != "" {
+			entries = appendSuggestEntries(entries, st, *term.CodeName, "code_name")
+		}
+		if acronym := deriveAcronym(term.Term); acronym != "" {
+			entries = appendSuggestEntries(entries, st, acronym, "acronym")
+		}
+	}
+
+	// Curated aliases; acronyms are reported as such, other alias types as "alias"
+	for _, alias := range aliases {
+		term := byID[alias.TermID]
+		if term == nil {
+			continue
+		}
+		kind := "alias"
+		if alias.AliasType == "acronym" {
+			kind = "acronym"
+		}
+		entries = appendSuggestEntries(entries, term, alias.Alias, kind)
+	}
+
+	// Synonyms work both ways: typing either name suggests the other term too
+	for _, rel := range synonyms {
+		term, related := byID[rel.TermID], byID[rel.RelatedTermID]
+		if term == nil || related == nil {
+			continue
+		}
+		entries = appendSuggestEntries(entries, term, related.name, "synonym")
+		entries = appendSuggestEntries(entries, related, term.name, "synonym")
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	return entries, nil
+}
+
+// appendSuggestEntries adds an entry for every word start of text
+func appendSuggestEntries(entries []suggestEntry, term *suggestTerm, text string, kind string) []suggestEntry {
+	lower := strings.ToLower(text)
+	wordStart := true
+	for i, r := range lower {
+		isWordChar := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordChar && wordStart {
+			entries = append(entries, suggestEntry{key: lower[i:], prefix: i == 0, text: text, kind: kind, term: term})
+		}
+		wordStart = !isWordChar
+	}
+	return entries
+}
+
+// deriveAcronym returns the initials of a multi-word name ("Know Your Customer" -> "KYC"),
+// or "" when the name is a single word
+func deriveAcronym(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < 2 || len(words) > 10 {
+		return ""
+	}
+
+	var acronym strings.Builder
+	for _, word := range words {
+		acronym.WriteRune(unicode.ToUpper([]rune(word)[0]))
+	}
+	return acronym.String()
+}
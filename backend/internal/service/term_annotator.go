@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const defaultAnnotatorRefreshInterval = 5 * time.Minute
+
+// annotationKindRank orders patterns for the same term: the name before code names and aliases
+var annotationKindRank = map[string]int{
+	"term":      0,
+	"code_name": 1,
+	"acronym":   2,
+	"alias":     2,
+}
+
+// annotationPattern is one searchable text. Terms sharing a text (e.g. an ambiguous acronym)
+// share its pattern.
+type annotationPattern struct {
+	text          string
+	kind          string
+	caseSensitive bool
+	terms         []*suggestTerm
+}
+
+// annotationMatcher is an immutable Aho-Corasick automaton over the lowercased patterns
+type annotationMatcher struct {
+	automaton *ahoCorasick
+	patterns  []*annotationPattern
+}
+
+// TermAnnotator finds glossary terms, aliases and code names in free text. Its matcher is rebuilt
+// periodically and shortly after Invalidate is called.
+//
+// Matching is case-insensitive on word boundaries, where letters, digits and underscores are word
+// characters. Short all-caps patterns such as acronyms ("KYC") only match in upper case. Where
+// matches overlap the leftmost, then longest wins.
+//
+// Configuration (environment):
+//   - TERM_ANNOTATOR_REFRESH_SECONDS: interval of the periodic rebuild (default 300)
+type TermAnnotator struct {
+	termRepo *repository.TermRepository
+
+	mu      sync.RWMutex
+	matcher *annotationMatcher // replaced, never modified, on rebuild
+
+	indexRefresher
+}
+
+func NewTermAnnotator() *TermAnnotator {
+	return &TermAnnotator{
+		termRepo:       repository.NewTermRepository(),
+		indexRefresher: newIndexRefresher(time.Duration(envInt("TERM_ANNOTATOR_REFRESH_SECONDS", int(defaultAnnotatorRefreshInterval/time.Second))) * time.Second),
+	}
+}
+
+// Start builds the matcher in the background and keeps it fresh until ctx is cancelled
+func (a *TermAnnotator) Start(ctx context.Context) {
+	a.start(ctx, a.rebuild)
+}
+
+// FindMentions returns the non-overlapping mentions of terms visible to the department, in
+// document order. Offsets are in characters (Unicode code points).
+func (a *TermAnnotator) FindMentions(text string, userDepartment *string) []models.TermMention {
+	mentions := []models.TermMention{}
+
+	a.mu.RLock()
+	matcher := a.matcher
+	a.mu.RUnlock()
+	if matcher == nil || text == "" {
+		return mentions
+	}
+
+	original := []rune(text)
+	lower := make([]rune, len(original))
+	for i, r := range original {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	type candidate struct {
+		match acMatch
+		terms []*suggestTerm
+	}
+	candidates := []candidate{}
+	for _, match := range matcher.automaton.findAll(lower) {
+		pattern := matcher.patterns[match.pattern]
+		if !isWordBoundary(original, match.start, match.end) {
+			continue
+		}
+		if pattern.caseSensitive && string(original[match.start:match.end]) != pattern.text {
+			continue
+		}
+		visible := []*suggestTerm{}
+		for _, term := range pattern.terms {
+			if term.visibleTo(userDepartment) {
+				visible = append(visible, term)
+			}
+		}
+		if len(visible) > 0 {
+			candidates = append(candidates, candidate{match: match, terms: visible})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		x, y := candidates[i].match, candidates[j].match
+		if x.start != y.start {
+			return x.start < y.start
+		}
+		if x.end != y.end {
+			return x.end > y.end
+		}
+		return annotationKindRank[matcher.patterns[x.pattern].kind] < annotationKindRank[matcher.patterns[y.pattern].kind]
+	})
+
+	covered := 0
+	for _, c := range candidates {
+		if c.match.start < covered {
+			continue
+		}
+		covered = c.match.end
+
+		mention := models.TermMention{
+			Start:      c.match.start,
+			End:        c.match.end,
+			Text:       string(original[c.match.start:c.match.end]),
+			TermID:     c.terms[0].id,
+			Term:       c.terms[0].name,
+			MatchedVia: matcher.patterns[c.match.pattern].kind,
+		}
+		for _, other := range c.terms[1:] {
+			mention.AlternativeTermIDs = append(mention.AlternativeTermIDs, other.id)
+		}
+		mentions = append(mentions, mention)
+	}
+
+	return mentions
+}
+
+// Annotate finds the mentions in text and resolves the definition of every mentioned term for
+// cluster, falling back to the base definition
+func (a *TermAnnotator) Annotate(ctx context.Context, text string, cluster *string, userDepartment *string) (*models.AnnotationResult, error) {
+	result := &models.AnnotationResult{Mentions: a.FindMentions(text, userDepartment), Terms: []models.AnnotatedTerm{}}
+
+	counts := map[uuid.UUID]int{}
+	termIDs := []uuid.UUID{}
+	for _, mention := range result.Mentions {
+		if counts[mention.TermID] == 0 {
+			termIDs = append(termIDs, mention.TermID)
+		}
+		counts[mention.TermID]++
+	}
+	if len(termIDs) == 0 {
+		return result, nil
+	}
+
+	definitions, err := a.termRepo.ResolveDefinitions(ctx, termIDs, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, termID := range termIDs {
+		term, ok := definitions[termID]
+		if !ok {
+			continue // deleted since the last rebuild
+		}
+		term.Mentions = counts[termID]
+		result.Terms = append(result.Terms, *term)
+	}
+
+	return result, nil
+}
+
+// isWordBoundary reports whether text[start:end] is not part of a longer word
+func isWordBoundary(text []rune, start, end int) bool {
+	if start > 0 && isAnnotationWordRune(text[start-1]) && isAnnotationWordRune(text[start]) {
+		return false
+	}
+	if end < len(text) && isAnnotationWordRune(text[end]) && isAnnotationWordRune(text[end-1]) {
+		return false
+	}
+	return true
+}
+
+func isAnnotationWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// isAcronymPattern reports whether a pattern only matches in upper case: up to six characters
+// with upper-case letters and no lower-case ones
+func isAcronymPattern(text string) bool {
+	runes := []rune(text)
+	if len(runes) > 6 {
+		return false
+	}
+	upper := false
+	for _, r := range runes {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			upper = true
+		}
+	}
+	return upper
+}
+
+func (a *TermAnnotator) rebuild(ctx context.Context) {
+	start := time.Now()
+	matcher, err := a.build(ctx)
+	if err != nil {
+		log.Printf("Term annotator rebuild failed: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	a.matcher = matcher
+	a.mu.Unlock()
+
+	log.Printf("Term annotator rebuilt: %d patterns in %dms", len(matcher.patterns), time.Since(start).Milliseconds())
+}
+
+func (a *TermAnnotator) build(ctx context.Context) (*annotationMatcher, error) {
+	terms, err := a.termRepo.ListTermNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := a.termRepo.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAnnotationMatcher(terms, aliases), nil
+}
+
+// newAnnotationMatcher builds the matcher over the names and code names of terms and their aliases
+func newAnnotationMatcher(terms []models.Term, aliases []models.TermAlias) *annotationMatcher {
+	byKey := map[string]*annotationPattern{}
+	patterns := []*annotationPattern{}
+	add := func(term *suggestTerm, text string, kind string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		caseSensitive := isAcronymPattern(text)
+		key := strings.ToLower(text)
+		if caseSensitive {
+			key = "\x00" + text
+		}
+
+		pattern, ok := byKey[key]
+		if !ok {
+			pattern = &annotationPattern{text: text, kind: kind, caseSensitive: caseSensitive}
+			byKey[key] = pattern
+			patterns = append(patterns, pattern)
+		} else if annotationKindRank[kind] < annotationKindRank[pattern.kind] {
+			pattern.kind = kind
+		}
+		for _, existing := range pattern.terms {
+			if existing == term {
+				return
+			}
+		}
+		pattern.terms = append(pattern.terms, term)
+	}
+
+	byID := make(map[uuid.UUID]*suggestTerm, len(terms))
+	for _, term := range terms {
+		st := termVisibility(term)
+		byID[term.ID] = st
+
+		add(st, term.Term, "term")
+		if term.CodeName != nil {
+			add(st, *term.CodeName, "code_name")
+		}
+	}
+
+	for _, alias := range aliases {
+		term := byID[alias.TermID]
+		if term == nil {
+			continue
+		}
+		kind := "alias"
+		if alias.AliasType == "acronym" {
+			kind = "acronym"
+		}
+		add(term, alias.Alias, kind)
+	}
+
+	lowered := make([][]rune, len(patterns))
+	for i, pattern := range patterns {
+		runes := []rune(pattern.text)
+		for j, r := range runes {
+			runes[j] = unicode.ToLower(r)
+		}
+		lowered[i] = runes
+	}
+
+	return &annotationMatcher{automaton: newAhoCorasick(lowered), patterns: patterns}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAhoCorasickFindAll(t *testing.T) {
+	patterns := [][]rune{[]rune("he"), []rune("she"), []rune("his"), []rune("hers"), []rune("")}
+	ac := newAhoCorasick(patterns)
+
+	got := ac.findAll([]rune("ushers"))
+	want := []acMatch{
+		{pattern: 1, start: 1, end: 4},
+		{pattern: 0, start: 2, end: 4},
+		{pattern: 3, start: 2, end: 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findAll(ushers) = %v, want %v", got, want)
+	}
+
+	if got := ac.findAll([]rune("xyz")); len(got) != 0 {
+		t.Errorf("findAll(xyz) = %v, want no matches", got)
+	}
+}
+
+func TestAhoCorasickFindAllRuneOffsets(t *testing.T) {
+	ac := newAhoCorasick([][]rune{[]rune("größe")})
+
+	got := ac.findAll([]rune("die größe"))
+	want := []acMatch{{pattern: 0, start: 4, end: 9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findAll = %v, want %v", got, want)
+	}
+}
+
+func TestTermAnnotatorFindMentions(t *testing.T) {
+	restricted := "department_restricted"
+	lcr := models.Term{ID: uuid.New(), Term: "Liquidity Coverage Ratio"}
+	coverage := models.Term{ID: uuid.New(), Term: "Coverage Ratio"}
+	kyc := models.Term{ID: uuid.New(), Term: "Know Your Customer"}
+	customer := models.Term{ID: uuid.New(), Term: "Customer", CodeName: strPtr("CUST_ID")}
+	secret := models.Term{ID: uuid.New(), Term: "Stress Scenario", VisibilityType: &restricted, AllowedDepartments: []string{"Risk"}}
+	aliases := []models.TermAlias{
+		{TermID: lcr.ID, Alias: "LCR", AliasType: "acronym"},
+		{TermID: kyc.ID, Alias: "KYC", AliasType: "acronym"},
+		{TermID: customer.ID, Alias: "Client", AliasType: "alternative_spelling"},
+	}
+	annotator := &TermAnnotator{matcher: newAnnotationMatcher([]models.Term{lcr, coverage, kyc, customer, secret}, aliases)}
+
+	tests := []struct {
+		name       string
+		text       string
+		department *string
+		want       []models.TermMention
+	}{
+		{
+			name: "leftmost longest wins",
+			text: "The liquidity coverage ratio is reported daily.",
+			want: []models.TermMention{{Start: 4, End: 28, Text: "liquidity coverage ratio", TermID: lcr.ID, Term: lcr.Term, MatchedVia: "term"}},
+		},
+		{
+			name: "shorter term alone",
+			text: "Our coverage ratio improved.",
+			want: []models.TermMention{{Start: 4, End: 18, Text: "coverage ratio", TermID: coverage.ID, Term: coverage.Term, MatchedVia: "term"}},
+		},
+		{
+			name: "word boundaries",
+			text: "Customers and the CUSTOMER_ID column",
+			want: []models.TermMention{},
+		},
+		{
+			name: "acronyms are case-sensitive",
+			text: "KYC checks, not kyc or Kyc.",
+			want: []models.TermMention{{Start: 0, End: 3, Text: "KYC", TermID: kyc.ID, Term: kyc.Term, MatchedVia: "acronym"}},
+		},
+		{
+			name: "code names and aliases",
+			text: "Join on CUST_ID for each client.",
+			want: []models.TermMention{
+				{Start: 8, End: 15, Text: "CUST_ID", TermID: customer.ID, Term: customer.Term, MatchedVia: "code_name"},
+				{Start: 25, End: 31, Text: "client", TermID: customer.ID, Term: customer.Term, MatchedVia: "alias"},
+			},
+		},
+		{
+			name:       "restricted term hidden from other departments",
+			text:       "Run the stress scenario.",
+			department: strPtr("Finance"),
+			want:       []models.TermMention{},
+		},
+		{
+			name:       "restricted term visible to its department",
+			text:       "Run the stress scenario.",
+			department: strPtr("Risk"),
+			want:       []models.TermMention{{Start: 8, End: 23, Text: "stress scenario", TermID: secret.ID, Term: secret.Term, MatchedVia: "term"}},
+		},
+		{
+			name: "offsets in characters",
+			text: "Größe: LCR",
+			want: []models.TermMention{{Start: 7, End: 10, Text: "LCR", TermID: lcr.ID, Term: lcr.Term, MatchedVia: "acronym"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := annotator.FindMentions(tt.text, tt.department)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindMentions(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTermAnnotatorSharedAlias(t *testing.T) {
+	first := models.Term{ID: uuid.New(), Term: "Probability of Default"}
+	second := models.Term{ID: uuid.New(), Term: "Payment on Delivery"}
+	aliases := []models.TermAlias{
+		{TermID: first.ID, Alias: "PD", AliasType: "acronym"},
+		{TermID: second.ID, Alias: "PD", AliasType: "acronym"},
+	}
+	annotator := &TermAnnotator{matcher: newAnnotationMatcher([]models.Term{first, second}, aliases)}
+
+	got := annotator.FindMentions("PD", nil)
+	want := []models.TermMention{{
+		Start: 0, End: 2, Text: "PD", TermID: first.ID, Term: first.Term, MatchedVia: "acronym",
+		AlternativeTermIDs: []uuid.UUID{second.ID},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindMentions = %+v, want %+v", got, want)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// discoveryMaxExamples is how many example sentences are kept per candidate
+	discoveryMaxExamples = 3
+
+	// discoveryMaxExampleLength truncates long example sentences, in characters
+	discoveryMaxExampleLength = 300
+
+	// discoveryMaxPhraseWords is the longest noun phrase considered
+	discoveryMaxPhraseWords = 4
+
+	// discoverySubsumedRatio drops a phrase when a longer phrase containing it occurs at least
+	// this often relative to it ("coverage ratio" inside "liquidity coverage ratio")
+	discoverySubsumedRatio = 0.8
+)
+
+// discoveryStopwords end noun phrases: articles, pronouns, prepositions, conjunctions, auxiliary
+// and very common verbs and adverbs
+var discoveryStopwords = toSet(strings.Fields(`
+	a about above across after again against all almost also although always am among an and any
+	are around as at be because been before being below between both but by can cannot could did
+	do does doing done down during each either else enough etc even ever every few for from
+	further get gets getting given had has have having he her here hers him his how however i if
+	in into is it its itself just least less like made make makes many may me might more most
+	much must my neither no nor not now of off often on once one only or other others our ours out
+	over own per rather same see shall she should since so some such than that the their theirs
+	them then there these they this those though through thus to too toward under until up upon
+	us use used uses using very via was we were what when where whether which while who whom whose
+	why will with within without would yet you your yours e.g i.e new include includes including
+	based following need needs needed well first second another
+`))
+
+// discoveryUpperStopwords are upper-case tokens that are not acronyms: shouted words and SQL keywords
+var discoveryUpperStopwords = toSet(strings.Fields(`
+	A I OK TODO NB FYI AND OR NOT THE FOR WITH FROM TO OF IN ON AT BY IS IT AS BE NO YES ALL ANY
+	SELECT INSERT UPDATE DELETE WHERE JOIN LEFT RIGHT INNER OUTER FULL CROSS GROUP ORDER HAVING LIMIT
+	OFFSET UNION DISTINCT COUNT SUM MIN MAX AVG CASE WHEN THEN ELSE END NULL INTO VALUES SET CREATE
+	TABLE VIEW ALTER DROP INDEX ASC DESC LIKE BETWEEN EXISTS COALESCE CAST TRUE FALSE WITH OVER
+	PARTITION ROW ROWS
+`))
+
+var (
+	discoverySentenceSplit = regexp.MustCompile(`[.!?;]+(\s+|$)|\n+`)
+	discoveryIdentifier    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(_[A-Za-z0-9]+)+$`)
+
+	// Markdown syntax removed before extraction; underscores are kept for snake_case identifiers
+	markdownImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownLinePrefix = regexp.MustCompile(`(?m)^\s*(#{1,6}\s+|>\s*|[-*+]\s+|\d+\.\s+|\x60{3}.*$)`)
+	markdownEmphasis   = regexp.MustCompile(`[*\x60~]+`)
+)
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// StripMarkdown removes markdown markup, keeping link and image text
+func StripMarkdown(text string) string {
+	text = markdownImage.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownLinePrefix.ReplaceAllString(text, "")
+	return markdownEmphasis.ReplaceAllString(text, "")
+}
+
+// TermDiscoveryService finds jargon in documents that the glossary does not define yet: frequent
+// noun phrases (word runs between stopwords), ALL-CAPS acronyms and snake_case identifiers. Text
+// that already is a term name, alias or code name is left out.
+type TermDiscoveryService struct {
+	termRepo       *repository.TermRepository
+	governanceRepo *repository.GovernanceRepository
+}
+
+func NewTermDiscoveryService() *TermDiscoveryService {
+	return &TermDiscoveryService{
+		termRepo:       repository.NewTermRepository(),
+		governanceRepo: repository.NewGovernanceRepository(),
+	}
+}
+
+// discoveryCount accumulates the occurrences of one candidate
+type discoveryCount struct {
+	kind      string
+	words     int
+	frequency int
+	forms     map[string]int // spelling as written -> count
+	documents map[string]bool
+	examples  []models.CandidateExample
+}
+
+// Discover returns up to limit candidates occurring at least minFrequency times, best first.
+// Candidates are scored by frequency x (1 + ln(documents)); acronyms and identifiers, which are
+// rarely ordinary words, count 1.5 times.
+func (s *TermDiscoveryService) Discover(ctx context.Context, documents []models.DiscoveryDocument, minFrequency int, limit int) (*models.DiscoveryResult, error) {
+	known, err := s.knownNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return discoverCandidates(documents, known, minFrequency, limit), nil
+}
+
+// discoverCandidates extracts and ranks candidates, leaving out the lowercased known names
+func discoverCandidates(documents []models.DiscoveryDocument, known map[string]bool, minFrequency int, limit int) *models.DiscoveryResult {
+	result := &models.DiscoveryResult{Candidates: []models.TermCandidate{}, Documents: len(documents)}
+	counts := map[string]*discoveryCount{}
+	record := func(key, kind, form string, words int, document, sentence string) {
+		count, ok := counts[key]
+		if !ok {
+			count = &discoveryCount{kind: kind, words: words, forms: map[string]int{}, documents: map[string]bool{}}
+			counts[key] = count
+		}
+		count.frequency++
+		count.forms[form]++
+		count.documents[document] = true
+		sentence = truncateRunes(sentence, discoveryMaxExampleLength)
+		if len(count.examples) < discoveryMaxExamples && !hasExample(count.examples, sentence) {
+			count.examples = append(count.examples, models.CandidateExample{Document: document, Sentence: sentence})
+		}
+	}
+
+	for _, document := range documents {
+		for _, segment := range document.Segments {
+			for _, sentence := range discoverySentenceSplit.Split(segment, -1) {
+				sentence = strings.TrimSpace(sentence)
+				tokens := discoveryTokens(sentence)
+				if len(tokens) == 0 {
+					continue
+				}
+				result.Sentences++
+				shouting := isShouting(tokens)
+
+				run := []string{}
+				flush := func() {
+					for n := 2; n <= discoveryMaxPhraseWords; n++ {
+						for i := 0; i+n <= len(run); i++ {
+							form := strings.Join(run[i:i+n], " ")
+							record("phrase:"+strings.ToLower(form), "phrase", form, n, document.Name, sentence)
+						}
+					}
+					run = run[:0]
+				}
+
+				for _, token := range tokens {
+					switch {
+					case discoveryIdentifier.MatchString(token):
+						flush()
+						record("identifier:"+strings.ToLower(token), "identifier", token, 1, document.Name, sentence)
+					case isAcronymToken(token):
+						flush()
+						if !shouting && !discoveryUpperStopwords[token] {
+							record("acronym:"+token, "acronym", token, 1, document.Name, sentence)
+						}
+					case !isPhraseWord(token) || discoveryStopwords[strings.ToLower(token)]:
+						flush()
+					default:
+						run = append(run, token)
+					}
+				}
+				flush()
+			}
+		}
+	}
+
+	dropSubsumedPhrases(counts)
+
+	for _, count := range counts {
+		if count.frequency < minFrequency {
+			continue
+		}
+		text := preferredForm(count.forms)
+		if known[strings.ToLower(text)] {
+			continue
+		}
+
+		score := float64(count.frequency) * (1 + math.Log(float64(len(count.documents))))
+		if count.kind != "phrase" {
+			score *= 1.5
+		}
+		result.Candidates = append(result.Candidates, models.TermCandidate{
+			Text:          text,
+			Kind:          count.kind,
+			Frequency:     count.frequency,
+			DocumentCount: len(count.documents),
+			Score:         math.Round(score*100) / 100,
+			Examples:      count.examples,
+		})
+	}
+
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		a, b := result.Candidates[i], result.Candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return strings.ToLower(a.Text) < strings.ToLower(b.Text)
+	})
+	if limit > 0 && len(result.Candidates) > limit {
+		result.Candidates = result.Candidates[:limit]
+	}
+
+	return result
+}
+
+// ProposeCandidate creates a pending create proposal for a candidate. The proposed data uses the
+// fields of a create term request; identifiers are proposed as the code name too.
+func (s *TermDiscoveryService) ProposeCandidate(ctx context.Context, req models.ProposeCandidateRequest, userID *uuid.UUID) (*models.TermProposal, error) {
+	text := strings.TrimSpace(req.Text)
+	data := map[string]interface{}{
+		"term":   text,
+		"source": "discovery",
+	}
+	if req.BaseDefinition != nil {
+		data["base_definition"] = *req.BaseDefinition
+	}
+	if req.Category != nil {
+		data["category"] = *req.Category
+	}
+	if req.Kind == "identifier" {
+		data["code_name"] = text
+	}
+	if len(req.Examples) > 0 {
+		data["examples"] = req.Examples
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		defaultReason := "Found by term discovery in uploaded documents"
+		reason = &defaultReason
+	}
+
+	return s.governanceRepo.CreateProposal(ctx, models.CreateProposalRequest{
+		ProposalType: "create",
+		ProposedData: data,
+		Reason:       reason,
+	}, userID)
+}
+
+// knownNames returns the lowercased term names, code names and aliases
+func (s *TermDiscoveryService) knownNames(ctx context.Context) (map[string]bool, error) {
+	terms, err := s.termRepo.ListTermNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.termRepo.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(terms)*2+len(aliases))
+	for _, term := range terms {
+		known[strings.ToLower(term.Term)] = true
+		if term.CodeName != nil {
+			known[strings.ToLower(*term.CodeName)] = true
+		}
+	}
+	for _, alias := range aliases {
+		known[strings.ToLower(alias.Alias)] = true
+	}
+	return known, nil
+}
+
+// discoveryTokens splits a sentence into words, keeping underscores, inner hyphens and apostrophes
+func discoveryTokens(sentence string) []string {
+	fields := strings.FieldsFunc(sentence, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '\''
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.Trim(field, "-'"); field != "" {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// isAcronymToken reports whether a token is 2-10 characters with at least two upper-case letters
+// and no lower-case ones, e.g. "LCR" or "IFRS9"
+func isAcronymToken(token string) bool {
+	runes := []rune(token)
+	if len(runes) < 2 || len(runes) > 10 {
+		return false
+	}
+	upper := 0
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsDigit(r) || r == '-':
+		default:
+			return false
+		}
+	}
+	return upper >= 2
+}
+
+// isPhraseWord reports whether a token can be part of a noun phrase: it contains a letter and is
+// not a number
+func isPhraseWord(token string) bool {
+	for _, r := range token {
+		if unicode.IsLetter(r) {
+			return len([]rune(token)) > 1
+		}
+	}
+	return false
+}
+
+// isShouting reports whether most of a longer sentence is upper case, as in headings or warnings,
+// where upper-case words are not acronyms
+func isShouting(tokens []string) bool {
+	if len(tokens) < 4 {
+		return false
+	}
+	upper := 0
+	for _, token := range tokens {
+		if isAcronymToken(token) {
+			upper++
+		}
+	}
+	return upper*2 > len(tokens)
+}
+
+// dropSubsumedPhrases removes phrases that mostly occur as part of a longer phrase
+func dropSubsumedPhrases(counts map[string]*discoveryCount) {
+	subsumed := map[string]bool{}
+	for key, count := range counts {
+		if count.kind != "phrase" || count.words < 3 {
+			continue
+		}
+		words := strings.Fields(strings.TrimPrefix(key, "phrase:"))
+		for n := 2; n < len(words); n++ {
+			for i := 0; i+n <= len(words); i++ {
+				subKey := "phrase:" + strings.Join(words[i:i+n], " ")
+				if sub, ok := counts[subKey]; ok && float64(count.frequency) >= discoverySubsumedRatio*float64(sub.frequency) {
+					subsumed[subKey] = true
+				}
+			}
+		}
+	}
+	for key := range subsumed {
+		delete(counts, key)
+	}
+}
+
+// preferredForm returns the most common spelling, ties broken alphabetically
+func preferredForm(forms map[string]int) string {
+	best, bestCount := "", 0
+	for form, count := range forms {
+		if count > bestCount || (count == bestCount && form < best) {
+			best, bestCount = form, count
+		}
+	}
+	return best
+}
+
+func hasExample(examples []models.CandidateExample, sentence string) bool {
+	for _, example := range examples {
+		if example.Sentence == sentence {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"clarityconnect/internal/models"
+)
+
+func TestDiscoverCandidates(t *testing.T) {
+	document := func(name string, segments ...string) models.DiscoveryDocument {
+		return models.DiscoveryDocument{Name: name, Segments: segments}
+	}
+
+	tests := []struct {
+		name         string
+		documents    []models.DiscoveryDocument
+		known        map[string]bool
+		minFrequency int
+		limit        int
+		want         []string // kind:text:frequency:documents, best first
+	}{
+		{
+			"acronyms",
+			[]models.DiscoveryDocument{document("a", "The LCR rose. LCR and NSFR are ratios. LCR fell.")},
+			nil, 2, 0,
+			[]string{"acronym:LCR:3:1"},
+		},
+		{
+			"shouted sentences have no acronyms",
+			[]models.DiscoveryDocument{document("a", "WARNING DO NOT EDIT THIS FILE. WARNING DO NOT EDIT THIS FILE.")},
+			nil, 1, 0,
+			[]string{},
+		},
+		{
+			"SQL keywords are not acronyms",
+			[]models.DiscoveryDocument{document("a", "SELECT amount FROM ledger. SELECT amount FROM ledger.")},
+			nil, 1, 0,
+			[]string{},
+		},
+		{
+			"identifiers",
+			[]models.DiscoveryDocument{document("a", "Join on customer_id. The customer_id column.")},
+			nil, 2, 0,
+			[]string{"identifier:customer_id:2:1"},
+		},
+		{
+			"phrases across documents, in their most common spelling",
+			[]models.DiscoveryDocument{
+				document("a", "Liquidity coverage ratio is reported daily."),
+				document("b", "The liquidity coverage ratio fell."),
+			},
+			nil, 2, 0,
+			[]string{"phrase:Liquidity coverage ratio:2:2"},
+		},
+		{
+			"subsumed phrases dropped unless mostly used alone",
+			[]models.DiscoveryDocument{document("a",
+				"Liquidity coverage ratio is high. The liquidity coverage ratio is low.",
+				"The coverage ratio is key. The coverage ratio is fine. The coverage ratio is set.",
+			)},
+			nil, 2, 0,
+			[]string{"phrase:coverage ratio:5:1", "phrase:Liquidity coverage ratio:2:1"},
+		},
+		{
+			"known terms, aliases and code names left out",
+			[]models.DiscoveryDocument{document("a", "The LCR uses customer_id. The LCR uses customer_id and the funding gap. Our funding gap.")},
+			map[string]bool{"lcr": true, "funding gap": true},
+			2, 0,
+			[]string{"identifier:customer_id:2:1"},
+		},
+		{
+			"acronyms outrank phrases of the same frequency; limited",
+			[]models.DiscoveryDocument{document("a", "The NSFR and the funding gap. The NSFR and the funding gap.")},
+			nil, 2, 1,
+			[]string{"acronym:NSFR:2:1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			known := tt.known
+			if known == nil {
+				known = map[string]bool{}
+			}
+			result := discoverCandidates(tt.documents, known, tt.minFrequency, tt.limit)
+			got := []string{}
+			for _, candidate := range result.Candidates {
+				got = append(got, fmt.Sprintf("%s:%s:%d:%d", candidate.Kind, candidate.Text, candidate.Frequency, candidate.DocumentCount))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("candidates = %q, want %q", got, tt.want)
+			}
+			if result.Documents != len(tt.documents) {
+				t.Errorf("documents = %d, want %d", result.Documents, len(tt.documents))
+			}
+		})
+	}
+}
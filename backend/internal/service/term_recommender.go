@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRecommenderRefreshInterval = 10 * time.Minute
+
+	// recommenderBatchSize is the number of terms loaded per query when building the index
+	recommenderBatchSize = 500
+
+	// Default blend of the recommendation score
+	defaultRecommendContentWeight = 0.6
+	defaultRecommendCoViewWeight  = 0.4
+
+	defaultRecommendCoViewDays   = 90
+	defaultRecommendMinCoViewers = 2
+
+	// recommendMinContentScore is the similarity below which definitions alone do not make a
+	// recommendation
+	recommendMinContentScore = 0.05
+
+	// recommendCoViewCandidates bounds the co-viewed terms considered per request
+	recommendCoViewCandidates = 100
+
+	// recommendSharedKeywords is how many shared keywords explain a content match
+	recommendSharedKeywords = 5
+)
+
+// tfidfDocument is the TF-IDF vector of one term, normalised to unit length
+type tfidfDocument struct {
+	term     *suggestTerm
+	category *string
+	weights  map[string]float64
+}
+
+type tfidfPosting struct {
+	doc    int32
+	weight float64
+}
+
+// tfidfIndex is an immutable inverted index of the term vectors
+type tfidfIndex struct {
+	docs     []*tfidfDocument
+	byID     map[uuid.UUID]int32
+	postings map[string][]tfidfPosting
+}
+
+// TermRecommender suggests related terms by blending the TF-IDF similarity of term names, base
+// and context definitions with co-viewing ("people who viewed this also viewed"). Terms already
+// related in either direction are never suggested. The TF-IDF index is rebuilt periodically and
+// shortly after Invalidate is called; co-views are read from the raw usage logs per request.
+//
+// Configuration (environment):
+//   - RECOMMEND_WEIGHT_CONTENT, RECOMMEND_WEIGHT_COVIEW: weights of the two signals (default 0.6, 0.4)
+//   - RECOMMEND_COVIEW_DAYS: how far back views count (default 90)
+//   - RECOMMEND_MIN_COVIEWERS: shared viewers needed for a co-view signal (default 2)
+//   - RECOMMEND_INDEX_REFRESH_SECONDS: interval of the periodic rebuild (default 600)
+type TermRecommender struct {
+	termRepo      *repository.TermRepository
+	usageRepo     *repository.UsageRepository
+	contentWeight float64
+	coViewWeight  float64
+	coViewWindow  time.Duration
+	minCoViewers  int
+
+	mu    sync.RWMutex
+	index *tfidfIndex // replaced, never modified, on rebuild
+
+	indexRefresher
+}
+
+func NewTermRecommender() *TermRecommender {
+	return &TermRecommender{
+		termRepo:       repository.NewTermRepository(),
+		usageRepo:      repository.NewUsageRepository(),
+		contentWeight:  envNonNegativeFloat("RECOMMEND_WEIGHT_CONTENT", defaultRecommendContentWeight),
+		coViewWeight:   envNonNegativeFloat("RECOMMEND_WEIGHT_COVIEW", defaultRecommendCoViewWeight),
+		coViewWindow:   time.Duration(envInt("RECOMMEND_COVIEW_DAYS", defaultRecommendCoViewDays)) * 24 * time.Hour,
+		minCoViewers:   envInt("RECOMMEND_MIN_COVIEWERS", defaultRecommendMinCoViewers),
+		indexRefresher: newIndexRefresher(time.Duration(envInt("RECOMMEND_INDEX_REFRESH_SECONDS", int(defaultRecommenderRefreshInterval/time.Second))) * time.Second),
+	}
+}
+
+// Start builds the index in the background and keeps it fresh until ctx is cancelled
+func (s *TermRecommender) Start(ctx context.Context) {
+	s.start(ctx, s.rebuild)
+}
+
+// Recommend returns up to limit terms visible to the department that are likely related to
+// termID but not yet linked to it, best first. Views by anonymousUserID, the user of requests
+// without one, say nothing about co-viewing and are ignored.
+func (s *TermRecommender) Recommend(ctx context.Context, termID uuid.UUID, userDepartment *string, anonymousUserID uuid.UUID, limit int) ([]models.TermRecommendation, error) {
+	if _, err := s.termRepo.GetTermByID(ctx, termID); err != nil {
+		return nil, err
+	}
+
+	related, err := s.termRepo.GetRelatedTermIDs(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	coViewed, err := s.usageRepo.GetCoViewedTerms(ctx, termID, time.Now().Add(-s.coViewWindow), anonymousUserID, s.minCoViewers, recommendCoViewCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+	if index == nil {
+		return []models.TermRecommendation{}, nil
+	}
+
+	return s.rank(index, termID, related, coViewed, userDepartment, limit), nil
+}
+
+// rank blends the content and co-view scores of the candidates for termID, leaving out the term
+// itself, the related terms and terms the department cannot see
+func (s *TermRecommender) rank(index *tfidfIndex, termID uuid.UUID, related map[uuid.UUID]bool, coViewed []models.CoViewedTerm, userDepartment *string, limit int) []models.TermRecommendation {
+	candidates := map[uuid.UUID]*models.TermRecommendation{}
+	candidate := func(id uuid.UUID) *models.TermRecommendation {
+		if rec, ok := candidates[id]; ok {
+			return rec
+		}
+		pos, ok := index.byID[id]
+		if !ok || id == termID || related[id] || !index.docs[pos].term.visibleTo(userDepartment) {
+			return nil
+		}
+		doc := index.docs[pos]
+		rec := &models.TermRecommendation{TermID: id, Term: doc.term.name, Category: doc.category, Reasons: []string{}}
+		candidates[id] = rec
+		return rec
+	}
+
+	var target *tfidfDocument
+	if pos, ok := index.byID[termID]; ok {
+		target = index.docs[pos]
+		for id, score := range index.similar(target) {
+			if score < recommendMinContentScore {
+				continue
+			}
+			if rec := candidate(id); rec != nil {
+				rec.ContentScore = score
+				rec.Reasons = append(rec.Reasons, "similar_definition")
+				rec.SharedKeywords = sharedKeywords(target, index.docs[index.byID[id]], recommendSharedKeywords)
+			}
+		}
+	}
+
+	for _, co := range coViewed {
+		if rec := candidate(co.TermID); rec != nil && co.TermViewers > 0 && co.RelatedViewers > 0 {
+			// Cosine of the viewer sets, so terms everyone views do not dominate
+			rec.CoViewScore = float64(co.CoViewers) / math.Sqrt(float64(co.TermViewers)*float64(co.RelatedViewers))
+			rec.CoViewers = co.CoViewers
+			rec.Reasons = append(rec.Reasons, "co_viewed")
+		}
+	}
+
+	recommendations := []models.TermRecommendation{}
+	for _, rec := range candidates {
+		rec.Score = s.contentWeight*rec.ContentScore + s.coViewWeight*rec.CoViewScore
+		recommendations = append(recommendations, *rec)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Term < recommendations[j].Term
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations
+}
+
+// similar returns the cosine similarity of doc to every other document sharing a word with it
+func (idx *tfidfIndex) similar(doc *tfidfDocument) map[uuid.UUID]float64 {
+	scores := map[int32]float64{}
+	for word, weight := range doc.weights {
+		for _, posting := range idx.postings[word] {
+			scores[posting.doc] += weight * posting.weight
+		}
+	}
+
+	similar := make(map[uuid.UUID]float64, len(scores))
+	for pos, score := range scores {
+		similar[idx.docs[pos].term.id] = score
+	}
+	return similar
+}
+
+// sharedKeywords returns the words contributing most to the similarity of two documents
+func sharedKeywords(a, b *tfidfDocument, max int) []string {
+	type contribution struct {
+		word  string
+		value float64
+	}
+	shared := []contribution{}
+	for word, weight := range a.weights {
+		if other, ok := b.weights[word]; ok {
+			shared = append(shared, contribution{word: word, value: weight * other})
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].value != shared[j].value {
+			return shared[i].value > shared[j].value
+		}
+		return shared[i].word < shared[j].word
+	})
+
+	words := []string{}
+	for i := 0; i < len(shared) && i < max; i++ {
+		words = append(words, shared[i].word)
+	}
+	return words
+}
+
+// recommendationTokens returns the normalised content words of a text: lower case, without
+// stopwords, numbers and words under three characters, with plurals reduced to the singular
+func recommendationTokens(text string) []string {
+	tokens := []string{}
+	for _, token := range discoveryTokens(text) {
+		word := strings.ToLower(token)
+		if len([]rune(word)) < 3 || discoveryStopwords[word] || strings.Trim(word, "0123456789") == "" {
+			continue
+		}
+		tokens = append(tokens, singularize(word))
+	}
+	return tokens
+}
+
+// singularize strips common English plural endings ("liabilities", "assets"); it only needs to
+// map both forms of a word to the same key, not to produce a real word
+func singularize(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func (s *TermRecommender) rebuild(ctx context.Context) {
+	start := time.Now()
+	index, err := s.build(ctx)
+	if err != nil {
+		log.Printf("Term recommender rebuild failed: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+
+	log.Printf("Term recommender rebuilt: %d terms, %d words in %dms", len(index.docs), len(index.postings), time.Since(start).Milliseconds())
+}
+
+func (s *TermRecommender) build(ctx context.Context) (*tfidfIndex, error) {
+	builder := newTFIDFBuilder()
+
+	var afterID *uuid.UUID
+	for {
+		batch, err := s.termRepo.ListTermsWithContextsAfter(ctx, afterID, recommenderBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, term := range batch {
+			builder.add(term)
+		}
+
+		afterID = &batch[len(batch)-1].ID
+		if len(batch) < recommenderBatchSize {
+			break
+		}
+	}
+
+	return builder.finish(), nil
+}
+
+// tfidfBuilder counts the words of terms for a new index
+type tfidfBuilder struct {
+	index             *tfidfIndex
+	counts            []map[string]int
+	documentFrequency map[string]int
+}
+
+func newTFIDFBuilder() *tfidfBuilder {
+	return &tfidfBuilder{
+		index:             &tfidfIndex{byID: map[uuid.UUID]int32{}, postings: map[string][]tfidfPosting{}},
+		documentFrequency: map[string]int{},
+	}
+}
+
+// add counts the words of the name, base and context definitions of a term
+func (b *tfidfBuilder) add(term models.Term) {
+	text := []string{term.Term, term.BaseDefinition}
+	for _, tc := range term.Contexts {
+		text = append(text, tc.ContextDefinition)
+	}
+
+	termCounts := map[string]int{}
+	for _, word := range recommendationTokens(strings.Join(text, "\n")) {
+		termCounts[word]++
+	}
+	for word := range termCounts {
+		b.documentFrequency[word]++
+	}
+
+	b.index.byID[term.ID] = int32(len(b.index.docs))
+	b.index.docs = append(b.index.docs, &tfidfDocument{
+		term:     termVisibility(term),
+		category: term.Category,
+	})
+	b.counts = append(b.counts, termCounts)
+}
+
+// finish weighs the counted words and returns the index
+func (b *tfidfBuilder) finish() *tfidfIndex {
+	index := b.index
+
+	// Sublinear term frequency and smoothed inverse document frequency; words in every document
+	// get no weight
+	n := float64(len(index.docs))
+	for pos, doc := range index.docs {
+		weights := map[string]float64{}
+		norm := 0.0
+		for word, count := range b.counts[pos] {
+			weight := (1 + math.Log(float64(count))) * math.Log((1+n)/(1+float64(b.documentFrequency[word])))
+			if weight <= 0 {
+				continue
+			}
+			weights[word] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		for word := range weights {
+			weights[word] /= norm
+			index.postings[word] = append(index.postings[word], tfidfPosting{doc: int32(pos), weight: weights[word]})
+		}
+		doc.weights = weights
+	}
+
+	return index
+}
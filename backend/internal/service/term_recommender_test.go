@@ -0,0 +1,168 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"clarityconnect/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// testRecommenderTerms are a target term, three terms sharing words with it (one restricted to
+// Treasury) and one sharing none
+func testRecommenderTerms() (nim, expense, provision, deposit, headcount models.Term) {
+	nim = models.Term{ID: uuid.New(), Term: "Net Interest Margin", BaseDefinition: "Interest income minus interest expense on loans"}
+	expense = models.Term{ID: uuid.New(), Term: "Interest Expense", BaseDefinition: "Interest paid on deposits and loans"}
+	provision = models.Term{ID: uuid.New(), Term: "Loan Loss Provision", BaseDefinition: "Expected credit losses on loans"}
+	deposit = models.Term{
+		ID:                 uuid.New(),
+		Term:               "Deposit Rate",
+		BaseDefinition:     "Interest paid on customer deposits",
+		VisibilityType:     strPtr("department_restricted"),
+		AllowedDepartments: []string{"Treasury"},
+	}
+	headcount = models.Term{ID: uuid.New(), Term: "Headcount", BaseDefinition: "Number of employees"}
+	return
+}
+
+func testRecommenderIndex(terms ...models.Term) *tfidfIndex {
+	builder := newTFIDFBuilder()
+	for _, term := range terms {
+		builder.add(term)
+	}
+	return builder.finish()
+}
+
+func TestTFIDFSimilar(t *testing.T) {
+	nim, expense, provision, deposit, headcount := testRecommenderTerms()
+	index := testRecommenderIndex(nim, expense, provision, deposit, headcount)
+	names := map[uuid.UUID]string{}
+	for _, term := range []models.Term{nim, expense, provision, deposit, headcount} {
+		names[term.ID] = term.Term
+	}
+
+	tests := []struct {
+		name   string
+		target models.Term
+		want   []string
+	}{
+		{"shared words rank by weight", nim, []string{"Net Interest Margin", "Interest Expense", "Deposit Rate", "Loan Loss Provision"}},
+		{"plurals match the singular", deposit, []string{"Deposit Rate", "Interest Expense", "Net Interest Margin"}},
+		{"no shared words", headcount, []string{"Headcount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := index.similar(index.docs[index.byID[tt.target.ID]])
+			ids := []uuid.UUID{}
+			for id := range scores {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return scores[ids[i]] > scores[ids[j]] })
+			got := []string{}
+			for _, id := range ids {
+				got = append(got, names[id])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("similar terms = %q, want %q", got, tt.want)
+			}
+			if self := scores[tt.target.ID]; math.Abs(self-1) > 1e-9 {
+				t.Errorf("similarity to itself = %v, want 1", self)
+			}
+		})
+	}
+}
+
+func TestTermRecommenderRank(t *testing.T) {
+	nim, expense, provision, deposit, headcount := testRecommenderTerms()
+	index := testRecommenderIndex(nim, expense, provision, deposit, headcount)
+	recommender := &TermRecommender{contentWeight: defaultRecommendContentWeight, coViewWeight: defaultRecommendCoViewWeight}
+	treasury, retail := "Treasury", "Retail"
+
+	tests := []struct {
+		name       string
+		related    map[uuid.UUID]bool
+		coViewed   []models.CoViewedTerm
+		department *string
+		limit      int
+		want       []string
+	}{
+		{"content matches above the minimum score, best first", nil, nil, nil, 10, []string{"Interest Expense", "Deposit Rate"}},
+		{"related terms left out", map[uuid.UUID]bool{expense.ID: true}, nil, nil, 10, []string{"Deposit Rate"}},
+		{"restricted term left out for other departments", nil, nil, &retail, 10, []string{"Interest Expense"}},
+		{"restricted term kept for its department", nil, nil, &treasury, 10, []string{"Interest Expense", "Deposit Rate"}},
+		{
+			"co-viewed terms added, the target itself never",
+			nil,
+			[]models.CoViewedTerm{
+				{TermID: headcount.ID, CoViewers: 8, TermViewers: 10, RelatedViewers: 10},
+				{TermID: nim.ID, CoViewers: 10, TermViewers: 10, RelatedViewers: 10},
+			},
+			nil,
+			10,
+			[]string{"Headcount", "Interest Expense", "Deposit Rate"},
+		},
+		{"limited", nil, nil, nil, 1, []string{"Interest Expense"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			related := tt.related
+			if related == nil {
+				related = map[uuid.UUID]bool{}
+			}
+			got := []string{}
+			for _, rec := range recommender.rank(index, nim.ID, related, tt.coViewed, tt.department, tt.limit) {
+				got = append(got, rec.Term)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recommendations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedKeywords(t *testing.T) {
+	a := &tfidfDocument{weights: map[string]float64{"interest": 0.8, "loan": 0.4, "margin": 0.3, "net": 0.3}}
+	b := &tfidfDocument{weights: map[string]float64{"interest": 0.5, "loan": 0.9, "net": 0.1, "paid": 0.2}}
+
+	tests := []struct {
+		name string
+		max  int
+		want []string
+	}{
+		{"by contribution", 5, []string{"interest", "loan", "net"}},
+		{"limited", 2, []string{"interest", "loan"}},
+		{"none", 0, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sharedKeywords(a, b, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sharedKeywords = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"liabilities", "liability"},
+		{"liability", "liability"},
+		{"assets", "asset"},
+		{"asset", "asset"},
+		{"ties", "tie"},
+		{"gas", "gas"},
+		{"class", "class"},
+		{"status", "status"},
+		{"basis", "basis"},
+	}
+	for _, tt := range tests {
+		if got := singularize(tt.word); got != tt.want {
+			t.Errorf("singularize(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUsageBufferSize    = 10000
+	defaultUsageBatchSize     = 500
+	defaultUsageFlushInterval = 2 * time.Second
+
+	// usageFlushTimeout bounds a single write to the database
+	usageFlushTimeout = 10 * time.Second
+)
+
+// UsageRecorder buffers usage events and search logs in memory and writes them to
+// term_usage_logs and search_query_logs in batches. Events are dropped (and counted) rather than
+// blocking requests when the buffer is full.
+type UsageRecorder struct {
+	repo          *repository.UsageRepository
+	events        chan models.TermUsageLog
+	searches      chan *models.SearchLog
+	batchSize     int
+	flushInterval time.Duration
+
+	// pendingSearches holds buffered searches by ID until they are written, so that
+	// click-throughs reported meanwhile are not lost
+	searchMu        sync.Mutex
+	pendingSearches map[uuid.UUID]*models.SearchLog
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+
+	enqueued int64
+	dropped  int64
+	written  int64
+	failed   int64
+
+	searchesDropped int64
+	searchesWritten int64
+	searchesFailed  int64
+}
+
+// NewUsageRecorder creates a usage recorder and starts its background writer
+func NewUsageRecorder() *UsageRecorder {
+	bufferSize := envInt("USAGE_LOG_BUFFER_SIZE", defaultUsageBufferSize)
+	r := &UsageRecorder{
+		repo:            repository.NewUsageRepository(),
+		events:          make(chan models.TermUsageLog, bufferSize),
+		searches:        make(chan *models.SearchLog, bufferSize),
+		batchSize:       envInt("USAGE_LOG_BATCH_SIZE", defaultUsageBatchSize),
+		flushInterval:   time.Duration(envInt("USAGE_LOG_FLUSH_INTERVAL_MS", int(defaultUsageFlushInterval/time.Millisecond))) * time.Millisecond,
+		pendingSearches: map[uuid.UUID]*models.SearchLog{},
+		done:            make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+// Record enqueues a usage event without blocking. It returns false if the event was dropped
+// because the buffer is full or the recorder has been closed.
+func (r *UsageRecorder) Record(event models.TermUsageLog) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		atomic.AddInt64(&r.dropped, 1)
+		return false
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	select {
+	case r.events <- event:
+		atomic.AddInt64(&r.enqueued, 1)
+		return true
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+		return false
+	}
+}
+
+// RecordSearch enqueues a search log without blocking. It returns false if the search was
+// dropped because the buffer is full or the recorder has been closed.
+func (r *UsageRecorder) RecordSearch(searchLog models.SearchLog) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		atomic.AddInt64(&r.searchesDropped, 1)
+		return false
+	}
+
+	if searchLog.CreatedAt.IsZero() {
+		searchLog.CreatedAt = time.Now()
+	}
+
+	entry := &searchLog
+	r.searchMu.Lock()
+	r.pendingSearches[entry.ID] = entry
+	r.searchMu.Unlock()
+
+	select {
+	case r.searches <- entry:
+		return true
+	default:
+		r.searchMu.Lock()
+		delete(r.pendingSearches, entry.ID)
+		r.searchMu.Unlock()
+		atomic.AddInt64(&r.searchesDropped, 1)
+		return false
+	}
+}
+
+// ClickSearch stores a click-through on a search that has not been written yet. It returns
+// false if the search is not in the buffer, in which case the click goes to the database.
+func (r *UsageRecorder) ClickSearch(searchID uuid.UUID, termID uuid.UUID) (*models.SearchLog, bool) {
+	r.searchMu.Lock()
+	defer r.searchMu.Unlock()
+
+	entry, ok := r.pendingSearches[searchID]
+	if !ok {
+		return nil, false
+	}
+
+	clickedAt := time.Now()
+	entry.ClickedTermID = &termID
+	entry.ClickedAt = &clickedAt
+
+	searchLog := *entry
+	return &searchLog, true
+}
+
+// Close stops accepting events and waits for the buffer to be drained to the database
+func (r *UsageRecorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+		close(r.searches)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns counters for the pipeline
+func (r *UsageRecorder) Stats() models.UsagePipelineStats {
+	return models.UsagePipelineStats{
+		Enqueued:   atomic.LoadInt64(&r.enqueued),
+		Dropped:    atomic.LoadInt64(&r.dropped),
+		Written:    atomic.LoadInt64(&r.written),
+		Failed:     atomic.LoadInt64(&r.failed),
+		Buffered:   len(r.events),
+		BufferSize: cap(r.events),
+
+		SearchesDropped:  atomic.LoadInt64(&r.searchesDropped),
+		SearchesWritten:  atomic.LoadInt64(&r.searchesWritten),
+		SearchesFailed:   atomic.LoadInt64(&r.searchesFailed),
+		SearchesBuffered: len(r.searches),
+	}
+}
+
+// run collects events and searches into batches and flushes them when full or on every tick
+func (r *UsageRecorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.TermUsageLog, 0, r.batchSize)
+	searchBatch := make([]*models.SearchLog, 0, r.batchSize)
+	events, searches := r.events, r.searches
+	for events != nil || searches != nil {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			batch = append(batch, event)
+			if len(batch) >= r.batchSize {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case entry, ok := <-searches:
+			if !ok {
+				searches = nil
+				continue
+			}
+			searchBatch = append(searchBatch, entry)
+			if len(searchBatch) >= r.batchSize {
+				r.flushSearches(searchBatch)
+				searchBatch = searchBatch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+			if len(searchBatch) > 0 {
+				r.flushSearches(searchBatch)
+				searchBatch = searchBatch[:0]
+			}
+		}
+	}
+
+	r.flush(batch)
+	r.flushSearches(searchBatch)
+}
+
+// flush writes a batch with COPY. If the batch is rejected (e.g. a term was deleted meanwhile)
+// it falls back to row-by-row inserts so one bad event does not lose the whole batch.
+func (r *UsageRecorder) flush(batch []models.TermUsageLog) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+	defer cancel()
+
+	written, err := r.repo.CreateUsageLogs(ctx, batch)
+	if err == nil {
+		atomic.AddInt64(&r.written, written)
+		return
+	}
+
+	log.Printf("Usage log batch of %d failed, retrying individually: %v", len(batch), err)
+	for _, event := range batch {
+		if err := r.repo.CreateUsageLog(ctx, event); err != nil {
+			atomic.AddInt64(&r.failed, 1)
+			continue
+		}
+		atomic.AddInt64(&r.written, 1)
+	}
+}
+
+// flushSearches writes a batch of searches with COPY, falling back to row-by-row inserts like
+// flush. Clicks stored while the batch was being written are applied to the written rows.
+func (r *UsageRecorder) flushSearches(batch []*models.SearchLog) {
+	if len(batch) == 0 {
+		return
+	}
+
+	r.searchMu.Lock()
+	logs := make([]models.SearchLog, len(batch))
+	for i, entry := range batch {
+		logs[i] = *entry
+	}
+	r.searchMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+	defer cancel()
+
+	written, err := r.repo.CreateSearchLogs(ctx, logs)
+	if err == nil {
+		atomic.AddInt64(&r.searchesWritten, written)
+	} else {
+		log.Printf("Search log batch of %d failed, retrying individually: %v", len(logs), err)
+		for i := range logs {
+			if err := r.repo.CreateSearchLog(ctx, &logs[i]); err != nil {
+				atomic.AddInt64(&r.searchesFailed, 1)
+				continue
+			}
+			atomic.AddInt64(&r.searchesWritten, 1)
+		}
+	}
+
+	clicked := []models.SearchLog{}
+	r.searchMu.Lock()
+	for i, entry := range batch {
+		if entry.ClickedAt != logs[i].ClickedAt {
+			clicked = append(clicked, *entry)
+		}
+		delete(r.pendingSearches, entry.ID)
+	}
+	r.searchMu.Unlock()
+
+	for _, entry := range clicked {
+		if _, err := r.repo.RecordSearchClick(ctx, entry.ID, *entry.ClickedTermID); err != nil {
+			log.Printf("Warning: Failed to record click on search %s: %v", entry.ID, err)
+		}
+	}
+}
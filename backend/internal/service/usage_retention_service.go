@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"clarityconnect/internal/models"
+	"clarityconnect/internal/repository"
+)
+
+const (
+	defaultUsageRetentionDays = 90
+	defaultUsageRollupHour    = 2
+)
+
+// UsageRetentionService rolls raw usage logs up into daily aggregates, pseudonymises user IDs
+// and purges raw logs past the retention period (POPIA/GDPR). Search query logs are pseudonymised
+// and purged on the same schedule.
+//
+// Configuration (environment):
+//   - USAGE_RETENTION_DAYS: days raw logs are kept; 0 keeps them forever (default 90)
+//   - USAGE_PSEUDONYMISE_AFTER_DAYS: days after which user_id is pseudonymised; 0 disables (default 0)
+//   - USAGE_PSEUDONYM_KEY: secret for the user pseudonym hash; without it user_id is removed instead
+//   - USAGE_ROLLUP_HOUR: local hour of the nightly run (default 2)
+type UsageRetentionService struct {
+	repo             *repository.UsageRepository
+	retentionDays    int
+	pseudonymiseDays int
+	pseudonymKey     string
+	rollupHour       int
+
+	// mu prevents the nightly run and a manual run from overlapping
+	mu sync.Mutex
+}
+
+func NewUsageRetentionService() *UsageRetentionService {
+	return &UsageRetentionService{
+		repo:             repository.NewUsageRepository(),
+		retentionDays:    envNonNegativeInt("USAGE_RETENTION_DAYS", defaultUsageRetentionDays),
+		pseudonymiseDays: envNonNegativeInt("USAGE_PSEUDONYMISE_AFTER_DAYS", 0),
+		pseudonymKey:     os.Getenv("USAGE_PSEUDONYM_KEY"),
+		rollupHour:       envNonNegativeInt("USAGE_ROLLUP_HOUR", defaultUsageRollupHour) % 24,
+	}
+}
+
+// Run rolls up complete days, then pseudonymises and purges raw logs according to the configuration.
+// Purging only removes days that have already been rolled up.
+func (s *UsageRetentionService) Run(ctx context.Context) (*models.UsageRetentionResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	today := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	result := &models.UsageRetentionResult{
+		RetentionDays:    s.retentionDays,
+		PseudonymiseDays: s.pseudonymiseDays,
+		RanAt:            start,
+	}
+
+	rolledUp, err := s.repo.RollupDailyUsage(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	result.RollupRows = rolledUp
+
+	if s.pseudonymiseDays > 0 {
+		pseudonymised, err := s.repo.PseudonymiseUsageLogs(ctx, today.AddDate(0, 0, -s.pseudonymiseDays), s.pseudonymKey)
+		if err != nil {
+			return nil, err
+		}
+		result.PseudonymisedRows = pseudonymised
+
+		pseudonymised, err = s.repo.PseudonymiseSearchLogs(ctx, today.AddDate(0, 0, -s.pseudonymiseDays), s.pseudonymKey)
+		if err != nil {
+			return nil, err
+		}
+		result.SearchLogsPseudonymised = pseudonymised
+	}
+
+	if s.retentionDays > 0 {
+		purged, err := s.repo.PurgeUsageLogs(ctx, today.AddDate(0, 0, -s.retentionDays))
+		if err != nil {
+			return nil, err
+		}
+		result.PurgedRows = purged
+
+		purged, err = s.repo.PurgeSearchLogs(ctx, today.AddDate(0, 0, -s.retentionDays))
+		if err != nil {
+			return nil, err
+		}
+		result.SearchLogsPurged = purged
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// StartNightly runs the retention job every night at the configured hour until ctx is cancelled
+func (s *UsageRetentionService) StartNightly(ctx context.Context) {
+	if s.pseudonymiseDays > 0 && s.pseudonymKey == "" {
+		log.Printf("Warning: USAGE_PSEUDONYM_KEY is not set, user IDs in old usage and search logs will be removed instead of pseudonymised")
+	}
+
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(s.nextRun(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			result, err := s.Run(ctx)
+			if err != nil {
+				log.Printf("Usage retention run failed: %v", err)
+				continue
+			}
+			log.Printf("Usage retention: %d rollup rows, %d pseudonymised, %d purged, %d search logs pseudonymised, %d purged in %dms",
+				result.RollupRows, result.PseudonymisedRows, result.PurgedRows, result.SearchLogsPseudonymised, result.SearchLogsPurged, result.DurationMs)
+		}
+	}()
+}
+
+// nextRun returns the next occurrence of the configured rollup hour after now
+func (s *UsageRetentionService) nextRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.rollupHour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// envNonNegativeInt reads a non-negative integer from the environment, falling back to def
+func envNonNegativeInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Printf("Warning: ignoring invalid %s=%q", key, value)
+		return def
+	}
+	return parsed
+}
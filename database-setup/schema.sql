@@ -49,12 +49,24 @@ CREATE TABLE IF NOT EXISTS terms (
     updated_by UUID REFERENCES users(id)
 );
 
--- Create GIN index for full-text search on terms
-CREATE INDEX IF NOT EXISTS idx_terms_search ON terms USING gin(to_tsvector('english', term || ' ' || COALESCE(base_definition, '')));
-
 -- Create index for category filtering
 CREATE INDEX IF NOT EXISTS idx_terms_category ON terms(category);
 
+-- Term lifecycle status; certified terms are boosted and deprecated terms demoted in search
+ALTER TABLE terms ADD COLUMN IF NOT EXISTS status VARCHAR(50) NOT NULL DEFAULT 'approved'
+    CHECK (status IN ('draft', 'approved', 'certified', 'deprecated'));
+CREATE INDEX IF NOT EXISTS idx_terms_status ON terms(status);
+
+-- Immutable tags-to-text helper so tags can carry a trigram index
+CREATE OR REPLACE FUNCTION tags_text(tags TEXT[]) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$ SELECT lower(array_to_string(tags, ' ')) $$;
+
+-- Trigram indexes for typo-tolerant and partial-word search
+CREATE INDEX IF NOT EXISTS idx_terms_term_trgm ON terms USING gin(lower(term) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_terms_code_name_trgm ON terms USING gin(lower(COALESCE(code_name, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_terms_tags_trgm ON terms USING gin(tags_text(tags) gin_trgm_ops);
+
 -- Term contexts - contextual variations across clusters/systems
 CREATE TABLE IF NOT EXISTS term_contexts (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -106,6 +118,105 @@ CREATE TABLE IF NOT EXISTS term_relationships (
 CREATE INDEX IF NOT EXISTS idx_term_relationships_term_id ON term_relationships(term_id);
 CREATE INDEX IF NOT EXISTS idx_term_relationships_related_term_id ON term_relationships(related_term_id);
 
+-- Term aliases - acronyms, abbreviations and alternative spellings used to find a term
+CREATE TABLE IF NOT EXISTS term_aliases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    term_id UUID NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    alias_type VARCHAR(50) NOT NULL DEFAULT 'other' CHECK (alias_type IN ('acronym', 'abbreviation', 'alternative_spelling', 'other')),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_term_aliases_term_alias ON term_aliases(term_id, lower(alias));
+CREATE INDEX IF NOT EXISTS idx_term_aliases_alias ON term_aliases(lower(alias));
+
+-- Weighted search document per term: term (A), code name and aliases (B), base and context
+-- definitions (C), examples and business rules (D). search_vector_base leaves out the context
+-- text; searches filtered by cluster, system or product use it and match only the filtered
+-- contexts. Both are maintained by the triggers below.
+ALTER TABLE terms ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE terms ADD COLUMN IF NOT EXISTS search_vector_base tsvector;
+
+CREATE OR REPLACE FUNCTION term_base_search_vector(p_term_id UUID, p_term TEXT, p_code_name TEXT, p_base_definition TEXT)
+RETURNS tsvector LANGUAGE sql STABLE
+AS $$
+    SELECT
+        setweight(to_tsvector('english', COALESCE(p_term, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(p_code_name, '') || ' ' ||
+            COALESCE((SELECT string_agg(alias, ' ') FROM term_aliases WHERE term_id = p_term_id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(p_base_definition, '')), 'C') ||
+        setweight(to_tsvector('english',
+            COALESCE((SELECT string_agg(example_text, ' ') FROM term_examples WHERE term_id = p_term_id), '')), 'D')
+$$;
+
+CREATE OR REPLACE FUNCTION term_search_vector(p_term_id UUID, p_term TEXT, p_code_name TEXT, p_base_definition TEXT)
+RETURNS tsvector LANGUAGE sql STABLE
+AS $$
+    SELECT
+        term_base_search_vector(p_term_id, p_term, p_code_name, p_base_definition) ||
+        setweight(to_tsvector('english',
+            COALESCE((SELECT string_agg(context_definition, ' ') FROM term_contexts WHERE term_id = p_term_id), '')), 'C') ||
+        setweight(to_tsvector('english',
+            COALESCE((SELECT string_agg(array_to_string(business_rules, ' '), ' ') FROM term_contexts WHERE term_id = p_term_id), '')), 'D')
+$$;
+
+CREATE OR REPLACE FUNCTION terms_search_vector_trigger() RETURNS trigger LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW.search_vector := term_search_vector(NEW.id, NEW.term, NEW.code_name, NEW.base_definition);
+    NEW.search_vector_base := term_base_search_vector(NEW.id, NEW.term, NEW.code_name, NEW.base_definition);
+    RETURN NEW;
+END
+$$;
+
+-- Refreshes the parent term's document when aliases, contexts or examples change
+CREATE OR REPLACE FUNCTION term_children_search_vector_trigger() RETURNS trigger LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE terms SET search_vector = term_search_vector(id, term, code_name, base_definition),
+                         search_vector_base = term_base_search_vector(id, term, code_name, base_definition)
+        WHERE id = OLD.term_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE terms SET search_vector = term_search_vector(id, term, code_name, base_definition),
+                         search_vector_base = term_base_search_vector(id, term, code_name, base_definition)
+        WHERE id = NEW.term_id;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS trg_terms_search_vector ON terms;
+CREATE TRIGGER trg_terms_search_vector
+    BEFORE INSERT OR UPDATE OF term, code_name, base_definition ON terms
+    FOR EACH ROW EXECUTE FUNCTION terms_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS trg_term_aliases_search_vector ON term_aliases;
+CREATE TRIGGER trg_term_aliases_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON term_aliases
+    FOR EACH ROW EXECUTE FUNCTION term_children_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS trg_term_contexts_search_vector ON term_contexts;
+CREATE TRIGGER trg_term_contexts_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON term_contexts
+    FOR EACH ROW EXECUTE FUNCTION term_children_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS trg_term_examples_search_vector ON term_examples;
+CREATE TRIGGER trg_term_examples_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON term_examples
+    FOR EACH ROW EXECUTE FUNCTION term_children_search_vector_trigger();
+
+-- Backfill terms created before the columns existed
+UPDATE terms SET search_vector = term_search_vector(id, term, code_name, base_definition),
+                 search_vector_base = term_base_search_vector(id, term, code_name, base_definition)
+WHERE search_vector IS NULL OR search_vector_base IS NULL;
+
+DROP INDEX IF EXISTS idx_terms_search;
+CREATE INDEX IF NOT EXISTS idx_terms_search_vector ON terms USING gin(search_vector);
+CREATE INDEX IF NOT EXISTS idx_terms_search_vector_base ON terms USING gin(search_vector_base);
+
 -- Term proposals - proposed updates/changes
 CREATE TABLE IF NOT EXISTS term_proposals (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -176,6 +287,13 @@ CREATE INDEX IF NOT EXISTS idx_gap_analyses_gap_type ON gap_analyses(gap_type);
 CREATE INDEX IF NOT EXISTS idx_gap_analyses_severity ON gap_analyses(severity);
 CREATE INDEX IF NOT EXISTS idx_gap_analyses_resolved_at ON gap_analyses(resolved_at) WHERE resolved_at IS NULL;
 
+-- Gap dimension - conflicts can be detected across clusters, or across systems/products within a cluster
+ALTER TABLE gap_analyses ADD COLUMN IF NOT EXISTS dimension VARCHAR(20) DEFAULT 'cluster' CHECK (dimension IN ('cluster', 'system', 'product'));
+ALTER TABLE gap_analyses ADD COLUMN IF NOT EXISTS affected_systems TEXT[];
+ALTER TABLE gap_analyses ADD COLUMN IF NOT EXISTS affected_products TEXT[];
+CREATE INDEX IF NOT EXISTS idx_gap_analyses_dimension ON gap_analyses(dimension);
+CREATE INDEX IF NOT EXISTS idx_term_contexts_product ON term_contexts(product);
+
 -- Term usage logs table (optional) - Track term usage for analytics
 CREATE TABLE IF NOT EXISTS term_usage_logs (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -191,6 +309,78 @@ CREATE INDEX IF NOT EXISTS idx_term_usage_logs_term_id ON term_usage_logs(term_i
 CREATE INDEX IF NOT EXISTS idx_term_usage_logs_cluster ON term_usage_logs(cluster);
 CREATE INDEX IF NOT EXISTS idx_term_usage_logs_created_at ON term_usage_logs(created_at);
 
+-- Department of the user who generated the usage event
+ALTER TABLE term_usage_logs ADD COLUMN IF NOT EXISTS department VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_term_usage_logs_department ON term_usage_logs(department);
+
+-- Pseudonymised user reference, replaces user_id once raw logs pass the pseudonymisation age
+ALTER TABLE term_usage_logs ADD COLUMN IF NOT EXISTS user_pseudonym VARCHAR(64);
+
+-- Daily usage rollups - aggregate counts that outlive the raw usage logs
+CREATE TABLE IF NOT EXISTS term_usage_daily (
+    day DATE NOT NULL,
+    term_id UUID NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    cluster VARCHAR(100) NOT NULL DEFAULT '', -- '' when unknown, so the primary key can include it
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    event_count BIGINT NOT NULL,
+    PRIMARY KEY (day, term_id, cluster, department, action)
+);
+
+-- Create indexes for daily usage rollups
+CREATE INDEX IF NOT EXISTS idx_term_usage_daily_term_id ON term_usage_daily(term_id);
+
+-- Usage events - daily rollups for rolled-up days, raw logs for the days after
+CREATE OR REPLACE VIEW term_usage_events AS
+WITH boundary AS (
+    SELECT COALESCE(MAX(day) + 1, '-infinity'::date) AS day FROM term_usage_daily
+)
+SELECT l.term_id, l.cluster, l.department, l.action, l.created_at, 1::bigint AS event_count
+FROM term_usage_logs l, boundary b
+WHERE l.created_at >= b.day
+UNION ALL
+SELECT d.term_id, NULLIF(d.cluster, ''), NULLIF(d.department, ''), d.action, d.day::timestamp, d.event_count
+FROM term_usage_daily d, boundary b
+WHERE d.day < b.day;
+
+-- Search query logs - every search with its result count and the result the user clicked
+CREATE TABLE IF NOT EXISTS search_query_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    query TEXT NOT NULL,
+    filters JSONB, -- category, cluster, system and tags used with the query
+    result_count INTEGER NOT NULL,
+    clicked_term_id UUID REFERENCES terms(id) ON DELETE SET NULL,
+    clicked_at TIMESTAMP,
+    user_id UUID REFERENCES users(id),
+    department VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for search query logs
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_created_at ON search_query_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_zero_results ON search_query_logs(created_at) WHERE result_count = 0;
+
+-- Keyed pseudonym of the user, set by the retention job when user_id is removed
+ALTER TABLE search_query_logs ADD COLUMN IF NOT EXISTS user_pseudonym VARCHAR(64);
+
+-- Term references - external systems (reports, dashboards, data pipelines) that reference a term
+CREATE TABLE IF NOT EXISTS term_references (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    term_id UUID NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    system VARCHAR(100) NOT NULL,
+    reference_type VARCHAR(50) NOT NULL DEFAULT 'other' CHECK (reference_type IN ('report', 'dashboard', 'pipeline', 'application', 'other')),
+    reference_name VARCHAR(255) NOT NULL DEFAULT '',
+    url TEXT,
+    cluster VARCHAR(100),
+    first_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(term_id, system, reference_name)
+);
+
+-- Create indexes for term references
+CREATE INDEX IF NOT EXISTS idx_term_references_term_id ON term_references(term_id);
+CREATE INDEX IF NOT EXISTS idx_term_references_system ON term_references(system);
+
 -- Onboarding paths table - Define learning paths for different roles
 CREATE TABLE IF NOT EXISTS onboarding_paths (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -209,7 +399,7 @@ CREATE INDEX IF NOT EXISTS idx_onboarding_paths_cluster ON onboarding_paths(clus
 CREATE TABLE IF NOT EXISTS notifications (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    type VARCHAR(50) NOT NULL, -- 'proposal', 'flag', 'gap_resolved', 'new_term'
+    type VARCHAR(50) NOT NULL, -- 'proposal', 'flag', 'gap_resolved', 'new_term', 'saved_search'
     message TEXT NOT NULL,
     read BOOLEAN DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
@@ -219,6 +409,31 @@ CREATE TABLE IF NOT EXISTS notifications (
 CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id);
 CREATE INDEX IF NOT EXISTS idx_notifications_read ON notifications(read) WHERE read = FALSE;
 
+-- Object a notification is about, e.g. the saved search whose results changed
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS reference_id UUID;
+
+-- Saved searches - a query plus filters a user can rerun, optionally alerting on result changes
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    query TEXT NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}', -- categories, clusters, systems, products, tags, compliance_frameworks, visibility
+    notify BOOLEAN NOT NULL DEFAULT FALSE, -- notify the user when terms enter or leave the results
+    last_result_ids UUID[] NOT NULL DEFAULT '{}', -- matching terms at the last alert check
+    last_checked_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Department of the owner, so alert checks only see the terms the owner can see
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS department VARCHAR(100);
+
+-- Create indexes for saved searches
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_searches_user_name ON saved_searches(user_id, lower(name));
+CREATE INDEX IF NOT EXISTS idx_saved_searches_notify ON saved_searches(notify) WHERE notify = TRUE;
+
 -- Term versions table - Audit trail for term changes
 CREATE TABLE IF NOT EXISTS term_versions (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),